@@ -35,6 +35,9 @@ var (
 	// JWTConfig is the configuration for json web auth token
 	JWTConfig = GasperConfig.JWT
 
+	// BackupsConfig is the configuration for storing the backups of databases
+	BackupsConfig = GasperConfig.Backups
+
 	//GithubConfig is the authentication configuration for the Gasper Github user
 	GithubConfig = GasperConfig.Github
 
@@ -57,10 +60,6 @@ var (
 			Deploy: ServiceConfig.GenProxy.Deploy,
 			Port:   ServiceConfig.GenProxy.Port,
 		},
-		types.MongoDB: {
-			Deploy: ServiceConfig.DbMaker.MongoDB.PlugIn && ServiceConfig.DbMaker.Deploy,
-			Port:   ServiceConfig.DbMaker.Port,
-		},
 		types.GenDNS: {
 			Deploy: ServiceConfig.GenDNS.Deploy,
 			Port:   ServiceConfig.GenDNS.Port,
@@ -69,17 +68,9 @@ var (
 			Deploy: ServiceConfig.Jikan.Deploy,
 			Port:   ServiceConfig.Jikan.Port,
 		},
-		types.MySQL: {
-			Deploy: ServiceConfig.DbMaker.MySQL.PlugIn && ServiceConfig.DbMaker.Deploy,
-			Port:   ServiceConfig.DbMaker.Port,
-		},
-		types.PostgreSQL: {
-			Deploy: ServiceConfig.DbMaker.PostgreSQL.PlugIn && ServiceConfig.DbMaker.Deploy,
-			Port:   ServiceConfig.DbMaker.Port,
-		},
-		types.Redis: {
-			Deploy: ServiceConfig.DbMaker.Redis.PlugIn && ServiceConfig.DbMaker.Deploy,
-			Port:   ServiceConfig.DbMaker.Port,
+		types.Exporter: {
+			Deploy: ServiceConfig.Exporter.Deploy,
+			Port:   ServiceConfig.Exporter.Port,
 		},
 	}
 )
@@ -90,4 +81,12 @@ func init() {
 	} else {
 		gin.SetMode(gin.ReleaseMode)
 	}
+
+	// The database engines are served by DbMaker and are deployed along with it
+	for engine, config := range ServiceConfig.DbMaker.Engines() {
+		ServiceMap[engine] = &GenericService{
+			Deploy: config.PlugIn && ServiceConfig.DbMaker.Deploy,
+			Port:   ServiceConfig.DbMaker.Port,
+		}
+	}
 }
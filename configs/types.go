@@ -53,21 +53,65 @@ type AppMakerService struct {
 	AppLimit        int           `toml:"app_limit"`
 }
 
+// SMTPConfig is the configuration of the SMTP server used for sending emails
+type SMTPConfig struct {
+	Host     string `toml:"host"`
+	Port     int    `toml:"port"`
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+	From     string `toml:"from"`
+}
+
+// AlertingConfig is the configuration for evaluating alert rules in Master microservice
+type AlertingConfig struct {
+	PlugIn             bool          `toml:"plugin"`
+	EvaluationInterval time.Duration `toml:"evaluation_interval"`
+	RenotifyInterval   time.Duration `toml:"renotify_interval"`
+	Retention          int64         `toml:"retention"`
+	SMTP               SMTPConfig    `toml:"smtp"`
+}
+
+// UptimeConfig is the configuration for probing applications and reporting their uptime in Master microservice
+type UptimeConfig struct {
+	PlugIn        bool          `toml:"plugin"`
+	ProbeInterval time.Duration `toml:"probe_interval"`
+	Timeout       time.Duration `toml:"timeout"`
+	Retention     int64         `toml:"retention"`
+}
+
 // MasterService is the default configuration for Master microservice
 type MasterService struct {
 	GenericService
-	CleanupInterval time.Duration   `toml:"cleanup_interval"`
-	MongoDB         DatabaseService `toml:"mongodb"`
-	Redis           DatabaseService `toml:"redis"`
+	CleanupInterval  time.Duration   `toml:"cleanup_interval"`
+	DatabaseFailover bool            `toml:"database_failover"`
+	MongoDB          DatabaseService `toml:"mongodb"`
+	Redis            DatabaseService `toml:"redis"`
+	Alerting         AlertingConfig  `toml:"alerting"`
+	Uptime           UptimeConfig    `toml:"uptime"`
+}
+
+// SessionRecordingConfig is the configuration for recording SSH sessions in GenSSH microservice
+type SessionRecordingConfig struct {
+	PlugIn    bool  `toml:"plugin"`
+	Retention int64 `toml:"retention"`
+}
+
+// RetentionDays returns the number of days for which the SSH sessions are retained
+func (config SessionRecordingConfig) RetentionDays() int {
+	if config.Retention <= 0 {
+		return 30
+	}
+	return int(config.Retention)
 }
 
 // GenSSHService is the configuration for GenSSH microservice
 type GenSSHService struct {
 	GenericService
-	HostSigners     []string `toml:"host_signers"`
-	UsingPassphrase bool     `toml:"using_passphrase"`
-	Passphrase      string   `toml:"passphrase"`
-	EntrypointIP    string   `toml:"entrypoint_ip"`
+	HostSigners     []string               `toml:"host_signers"`
+	UsingPassphrase bool                   `toml:"using_passphrase"`
+	Passphrase      string                 `toml:"passphrase"`
+	EntrypointIP    string                 `toml:"entrypoint_ip"`
+	Recording       SessionRecordingConfig `toml:"recording"`
 }
 
 // SSLConfig is the configuration for SSL in GenProxy microservice
@@ -85,28 +129,109 @@ type GenProxyService struct {
 	RecordUpdateInterval time.Duration `toml:"record_update_interval"`
 }
 
+// SecureDNSConfig is the configuration for serving DNS over an encrypted transport in GenDNS microservice
+type SecureDNSConfig struct {
+	PlugIn bool `toml:"plugin"`
+	Port   int  `toml:"port"`
+}
+
 // GenDNSService is the configuration for GenDNS microservice
 type GenDNSService struct {
 	GenericService
-	RecordUpdateInterval time.Duration `toml:"record_update_interval"`
+	RecordUpdateInterval time.Duration   `toml:"record_update_interval"`
+	DoH                  SecureDNSConfig `toml:"doh"`
+	DoT                  SecureDNSConfig `toml:"dot"`
 }
 
 // DatabaseService is the configuration for database servers
 type DatabaseService struct {
-	PlugIn        bool    `toml:"plugin"`
-	ContainerPort int     `toml:"container_port"`
-	Env           types.M `toml:"env"`
-	Password      string  `toml:"password"`
+	PlugIn        bool     `toml:"plugin"`
+	ContainerPort int      `toml:"container_port"`
+	Env           types.M  `toml:"env"`
+	Password      string   `toml:"password"`
+	Versions      []string `toml:"versions"`
+}
+
+// BackupConfig is the configuration for backing up databases in DbMaker microservice
+type BackupConfig struct {
+	PlugIn        bool          `toml:"plugin"`
+	CheckInterval time.Duration `toml:"check_interval"`
+	Retention     int           `toml:"retention"`
+}
+
+// QuotaConfig is the configuration for the default quotas of the databases in DbMaker microservice
+type QuotaConfig struct {
+	PlugIn        bool          `toml:"plugin"`
+	CheckInterval time.Duration `toml:"check_interval"`
+	Size          int64         `toml:"size"`
+	Connections   int           `toml:"connections"`
+	Memory        float64       `toml:"memory"`
+	CPU           float64       `toml:"cpu"`
+}
+
+// QueryConfig is the configuration for the query console of the databases in DbMaker microservice
+type QueryConfig struct {
+	PlugIn    bool          `toml:"plugin"`
+	Timeout   time.Duration `toml:"timeout"`
+	MaxRows   int           `toml:"max_rows"`
+	ReadOnly  bool          `toml:"read_only"`
+	Retention int64         `toml:"retention"`
+}
+
+// GetTimeout returns the maximum time for which a query is run
+func (config QueryConfig) GetTimeout() time.Duration {
+	if config.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return config.Timeout * time.Second
+}
+
+// GetMaxRows returns the maximum number of rows, documents or elements returned by a query
+func (config QueryConfig) GetMaxRows() int {
+	if config.MaxRows <= 0 {
+		return 1000
+	}
+	return config.MaxRows
+}
+
+// RetentionWindow returns the duration for which the audit logs of the queries are retained
+func (config QueryConfig) RetentionWindow() time.Duration {
+	if config.Retention <= 0 {
+		return 90 * 24 * time.Hour
+	}
+	return time.Duration(config.Retention) * 24 * time.Hour
 }
 
 // DbMakerService is the configuration for DbMaker microservice
 type DbMakerService struct {
 	GenericService
-	MySQL      DatabaseService `toml:"mysql"`
-	MongoDB    DatabaseService `toml:"mongodb"`
-	PostgreSQL DatabaseService `toml:"postgresql"`
-	Redis      DatabaseService `toml:"redis"`
-	DBLimit    int             `toml:"db_limit"`
+	MySQL           DatabaseService `toml:"mysql"`
+	MongoDB         DatabaseService `toml:"mongodb"`
+	PostgreSQL      DatabaseService `toml:"postgresql"`
+	Redis           DatabaseService `toml:"redis"`
+	MariaDB         DatabaseService `toml:"mariadb"`
+	Memcached       DatabaseService `toml:"memcached"`
+	RabbitMQ        DatabaseService `toml:"rabbitmq"`
+	MinIO           DatabaseService `toml:"minio"`
+	DBLimit         int             `toml:"db_limit"`
+	Backup          BackupConfig    `toml:"backup"`
+	Quota           QuotaConfig     `toml:"quota"`
+	Query           QueryConfig     `toml:"query"`
+	MetricsInterval time.Duration   `toml:"metrics_interval"`
+}
+
+// Engines returns the configuration of the database engines managed by DbMaker keyed by their type
+func (service *DbMakerService) Engines() map[string]DatabaseService {
+	return map[string]DatabaseService{
+		types.MySQL:      service.MySQL,
+		types.MongoDB:    service.MongoDB,
+		types.PostgreSQL: service.PostgreSQL,
+		types.Redis:      service.Redis,
+		types.MariaDB:    service.MariaDB,
+		types.Memcached:  service.Memcached,
+		types.RabbitMQ:   service.RabbitMQ,
+		types.MinIO:      service.MinIO,
+	}
 }
 
 // JikanService is the configuration for Jikan microservice
@@ -114,6 +239,12 @@ type JikanService struct {
 	GenericService
 }
 
+// ExporterService is the configuration for the Prometheus metrics exporter
+type ExporterService struct {
+	GenericService
+	ContainerInterval time.Duration `toml:"container_interval"`
+}
+
 // Images is the configuration for the docker images in use
 type Images struct {
 	Static     string `toml:"static"`
@@ -128,20 +259,101 @@ type Images struct {
 	Mongodb    string `toml:"mongodb"`
 	Postgresql string `toml:"postgresql"`
 	Redis      string `toml:"redis"`
+	Mariadb    string `toml:"mariadb"`
+	Memcached  string `toml:"memcached"`
+	Rabbitmq   string `toml:"rabbitmq"`
+	Minio      string `toml:"minio"`
+}
+
+// LogShippingConfig is the configuration for shipping the container logs of AppMaker and DbMaker to MongoDB
+type LogShippingConfig struct {
+	PlugIn          bool          `toml:"plugin"`
+	Retention       int64         `toml:"retention"`
+	BatchSize       int           `toml:"batch_size"`
+	FlushInterval   time.Duration `toml:"flush_interval"`
+	AllowedNetworks []string      `toml:"allowed_networks"`
+}
+
+// RetentionWindow returns the duration for which the shipped logs are retained
+func (config LogShippingConfig) RetentionWindow() time.Duration {
+	if config.Retention <= 0 {
+		return 14 * 24 * time.Hour
+	}
+	return time.Duration(config.Retention) * 24 * time.Hour
+}
+
+// MetricsRetentionConfig is the configuration for retaining the metrics of the containers
+// and their 5-minute and hourly rollups
+type MetricsRetentionConfig struct {
+	Raw        int64 `toml:"raw"`
+	FiveMinute int64 `toml:"five_minute"`
+	Hourly     int64 `toml:"hourly"`
+}
+
+// RawWindow returns the duration for which the raw metrics are retained
+func (config MetricsRetentionConfig) RawWindow() time.Duration {
+	if config.Raw <= 0 {
+		return 48 * time.Hour
+	}
+	return time.Duration(config.Raw) * time.Hour
+}
+
+// FiveMinuteWindow returns the duration for which the 5-minute rollups are retained
+func (config MetricsRetentionConfig) FiveMinuteWindow() time.Duration {
+	if config.FiveMinute <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(config.FiveMinute) * 24 * time.Hour
+}
+
+// HourlyWindow returns the duration for which the hourly rollups are retained
+func (config MetricsRetentionConfig) HourlyWindow() time.Duration {
+	if config.Hourly <= 0 {
+		return 365 * 24 * time.Hour
+	}
+	return time.Duration(config.Hourly) * 24 * time.Hour
 }
 
 // Services is the configuration for all Services
 type Services struct {
-	ExposureInterval time.Duration   `toml:"exposure_interval"`
-	RateInterval     time.Duration   `toml:"rate_interval"`
-	RateLimit        int             `toml:"rate_limit"`
-	Master           MasterService   `toml:"master"`
-	AppMaker         AppMakerService `toml:"appmaker"`
-	GenSSH           GenSSHService   `toml:"genssh"`
-	GenProxy         GenProxyService `toml:"genproxy"`
-	GenDNS           GenDNSService   `toml:"gendns"`
-	DbMaker          DbMakerService  `toml:"dbmaker"`
-	Jikan            JikanService    `toml:"jikan"`
+	ExposureInterval time.Duration          `toml:"exposure_interval"`
+	RateInterval     time.Duration          `toml:"rate_interval"`
+	RateLimit        int                    `toml:"rate_limit"`
+	LogShipping      LogShippingConfig      `toml:"log_shipping"`
+	MetricsRetention MetricsRetentionConfig `toml:"metrics_retention"`
+	Master           MasterService          `toml:"master"`
+	AppMaker         AppMakerService        `toml:"appmaker"`
+	GenSSH           GenSSHService          `toml:"genssh"`
+	GenProxy         GenProxyService        `toml:"genproxy"`
+	GenDNS           GenDNSService          `toml:"gendns"`
+	DbMaker          DbMakerService         `toml:"dbmaker"`
+	Jikan            JikanService           `toml:"jikan"`
+	Exporter         ExporterService        `toml:"exporter"`
+}
+
+// StorageTarget is the configuration of a storage target for the backups of databases
+// The fields used depend on the type of the target which is one of local, s3 or sftp
+type StorageTarget struct {
+	Type       string `toml:"type"`
+	Encrypt    bool   `toml:"encrypt"`
+	Directory  string `toml:"directory"`
+	Endpoint   string `toml:"endpoint"`
+	Region     string `toml:"region"`
+	Bucket     string `toml:"bucket"`
+	AccessKey  string `toml:"access_key"`
+	SecretKey  string `toml:"secret_key"`
+	Host       string `toml:"host"`
+	Port       int    `toml:"port"`
+	Username   string `toml:"username"`
+	Password   string `toml:"password"`
+	PrivateKey string `toml:"private_key"`
+	HostKey    string `toml:"host_key"`
+}
+
+// Backups is the configuration for storing the backups of databases
+type Backups struct {
+	Target  string                   `toml:"target"`
+	Targets map[string]StorageTarget `toml:"targets"`
 }
 
 type Github struct {
@@ -166,5 +378,6 @@ type GasperCfg struct {
 	Redis       Redis      `toml:"redis"`
 	Images      Images     `toml:"images"`
 	Services    Services   `toml:"services"`
+	Backups     Backups    `toml:"backups"`
 	Github      Github     `toml:"github"`
 }
@@ -41,6 +41,14 @@ func buildHTTPServer(handler http.Handler, port int) *http.Server {
 	return server
 }
 
+// buildStreamingHTTPServer builds a HTTP server whose handlers serving long-lived responses
+// like server-sent events and WebSockets can lift the write timeout with utils.ClearWriteDeadline
+func buildStreamingHTTPServer(handler http.Handler, port int) *http.Server {
+	server := buildHTTPServer(handler, port)
+	server.ConnContext = utils.ConnContext
+	return server
+}
+
 func setupDatabaseContainer(serviceName string) {
 	containers := appmaker.FetchAllApplicationNames()
 
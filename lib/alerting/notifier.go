@@ -0,0 +1,164 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// Timeout for delivering a notification to a webhook
+const sendTimeout = 10 * time.Second
+
+// webhookClient refuses to connect to internal addresses outside the allowed networks so that webhooks
+// cannot reach the internal network
+var webhookClient = utils.NewPublicHTTPClient(sendTimeout)
+
+// Notification is an alert sent to the notification channels of an alert rule, or an event
+// of a database sent to the notification channels of its owner
+type Notification struct {
+	App       string    `json:"app,omitempty"`
+	Database  string    `json:"database,omitempty"`
+	RuleID    string    `json:"rule_id,omitempty"`
+	Rule      string    `json:"rule"`
+	Condition string    `json:"condition,omitempty"`
+	State     string    `json:"state"`
+	Value     float64   `json:"value"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewNotification returns the notification of a change in the state of an alert rule
+func NewNotification(rule *types.AlertRule, state string, value float64, at time.Time) *Notification {
+	condition := Describe(rule)
+	return &Notification{
+		App:       rule.App,
+		RuleID:    rule.ID,
+		Rule:      rule.Name,
+		Condition: condition,
+		State:     state,
+		Value:     value,
+		Message: fmt.Sprintf("[%s] %s: %s on application %s (current value %g)",
+			strings.ToUpper(state), rule.Name, condition, rule.App, value),
+		Timestamp: at,
+	}
+}
+
+// NewDatabaseNotification returns the notification of an event of a database like its migration
+func NewDatabaseNotification(database, event, state, message string, at time.Time) *Notification {
+	return &Notification{
+		Database:  database,
+		Rule:      event,
+		State:     state,
+		Message:   fmt.Sprintf("[%s] %s: %s", strings.ToUpper(state), event, message),
+		Timestamp: at,
+	}
+}
+
+// instance returns the name of the application or the database the notification is about
+func (notification *Notification) instance() string {
+	if notification.Database != "" {
+		return notification.Database
+	}
+	return notification.App
+}
+
+// ValidateChannel checks whether notifications can be sent to a notification channel
+func ValidateChannel(channel *types.NotificationChannel) error {
+	switch channel.GetType() {
+	case types.EmailChannel:
+		if !govalidator.IsEmail(channel.GetTarget()) {
+			return fmt.Errorf("target of an email channel must be an email address")
+		}
+		if configs.ServiceConfig.Master.Alerting.SMTP.Host == "" {
+			return fmt.Errorf("email channels are not available as no SMTP server is configured")
+		}
+	case types.WebhookChannel, types.SlackChannel:
+		target, err := url.Parse(channel.GetTarget())
+		if err != nil {
+			return fmt.Errorf("target of a %s channel must be a URL: %s", channel.GetType(), err.Error())
+		}
+		if (target.Scheme != "https" && target.Scheme != "http") || target.Hostname() == "" {
+			return fmt.Errorf("target of a %s channel must be a http or https URL", channel.GetType())
+		}
+		if err := utils.ValidatePublicHost(target.Hostname()); err != nil {
+			return fmt.Errorf("target of a %s channel is not allowed: %s", channel.GetType(), err.Error())
+		}
+	default:
+		return fmt.Errorf("channel type `%s` is not supported, must be one of %s, %s or %s",
+			channel.GetType(), types.EmailChannel, types.WebhookChannel, types.SlackChannel)
+	}
+	return nil
+}
+
+// Send delivers a notification to a notification channel
+func Send(channel *types.NotificationChannel, notification *Notification) error {
+	switch channel.GetType() {
+	case types.EmailChannel:
+		return sendEmail(channel.GetTarget(), notification)
+	case types.WebhookChannel:
+		return postJSON(channel.GetTarget(), notification)
+	case types.SlackChannel:
+		return postJSON(channel.GetTarget(), types.M{"text": notification.Message})
+	}
+	return fmt.Errorf("channel type `%s` is not supported", channel.GetType())
+}
+
+// headerValue removes the line breaks from a value so that it cannot inject headers into an email
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+// sendEmail sends a notification as an email through the configured SMTP server
+func sendEmail(to string, notification *Notification) error {
+	config := configs.ServiceConfig.Master.Alerting.SMTP
+	if config.Host == "" {
+		return fmt.Errorf("no SMTP server is configured")
+	}
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "From: %s\r\n", config.From)
+	fmt.Fprintf(body, "To: %s\r\n", to)
+	fmt.Fprintf(body, "Subject: [Gasper] [%s] %s on %s\r\n",
+		headerValue(strings.ToUpper(notification.State)), headerValue(notification.Rule), headerValue(notification.instance()))
+	fmt.Fprintf(body, "Date: %s\r\n", notification.Timestamp.Format(time.RFC1123Z))
+	fmt.Fprintf(body, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(body, "%s\r\n\r\n", notification.Message)
+	if notification.RuleID != "" {
+		fmt.Fprintf(body, "Rule ID: %s\r\n", notification.RuleID)
+	}
+	fmt.Fprintf(body, "Time: %s\r\n", notification.Timestamp.Format(time.RFC3339))
+
+	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	return smtp.SendMail(address, auth, config.From, []string{to}, body.Bytes())
+}
+
+// postJSON posts a JSON encoded payload to a webhook
+func postJSON(endpoint string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	res, err := webhookClient.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", endpoint, res.StatusCode)
+	}
+	return nil
+}
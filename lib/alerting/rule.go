@@ -0,0 +1,206 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sdslabs/gasper/types"
+)
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// metricExtractors extract the value of the metrics on which alert rules can be defined
+var metricExtractors = map[string]func(*types.Metrics) float64{
+	"alive":         func(m *types.Metrics) float64 { return boolToFloat(m.Alive) },
+	"cpu_usage":     func(m *types.Metrics) float64 { return m.CPUUsage },
+	"cpu_percent":   func(m *types.Metrics) float64 { return m.CPUPercent },
+	"memory_usage":  func(m *types.Metrics) float64 { return m.MemoryUsage },
+	"network_rx":    func(m *types.Metrics) float64 { return m.NetworkRx },
+	"network_tx":    func(m *types.Metrics) float64 { return m.NetworkTx },
+	"block_read":    func(m *types.Metrics) float64 { return m.BlockRead },
+	"block_write":   func(m *types.Metrics) float64 { return m.BlockWrite },
+	"pids":          func(m *types.Metrics) float64 { return float64(m.PIDs) },
+	"restart_count": func(m *types.Metrics) float64 { return float64(m.RestartCount) },
+	"oom_killed":    func(m *types.Metrics) float64 { return boolToFloat(m.OOMKilled) },
+}
+
+// comparators compare the value of a metric with the threshold of an alert rule
+var comparators = map[string]func(value, threshold float64) bool{
+	">":  func(value, threshold float64) bool { return value > threshold },
+	">=": func(value, threshold float64) bool { return value >= threshold },
+	"<":  func(value, threshold float64) bool { return value < threshold },
+	"<=": func(value, threshold float64) bool { return value <= threshold },
+	"==": func(value, threshold float64) bool { return value == threshold },
+	"!=": func(value, threshold float64) bool { return value != threshold },
+}
+
+func supported(keys []string) string {
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// ValidateRule checks whether an alert rule can be evaluated
+func ValidateRule(rule *types.AlertRule) error {
+	if _, ok := metricExtractors[rule.Metric]; !ok {
+		metrics := make([]string, 0, len(metricExtractors))
+		for metric := range metricExtractors {
+			metrics = append(metrics, metric)
+		}
+		return fmt.Errorf("metric `%s` is not supported, must be one of %s", rule.Metric, supported(metrics))
+	}
+	if _, ok := comparators[rule.Operator]; !ok && rule.Operator != types.IncreasedOperator {
+		operators := []string{types.IncreasedOperator}
+		for operator := range comparators {
+			operators = append(operators, operator)
+		}
+		return fmt.Errorf("operator `%s` is not supported, must be one of %s", rule.Operator, supported(operators))
+	}
+	if rule.For != "" && rule.Intervals != 0 {
+		return fmt.Errorf("only one of `for` and `intervals` can be provided")
+	}
+	if rule.For != "" {
+		duration, err := time.ParseDuration(rule.For)
+		if err != nil {
+			return fmt.Errorf("`for` must be a duration like 10m: %s", err.Error())
+		}
+		if duration <= 0 {
+			return fmt.Errorf("`for` must be a positive duration")
+		}
+	}
+	if rule.Intervals < 0 {
+		return fmt.Errorf("`intervals` cannot be negative")
+	}
+	if len(rule.Channels) == 0 {
+		return fmt.Errorf("at least one notification channel must be provided")
+	}
+	return nil
+}
+
+// samplesRequired returns the number of consecutive samples evaluated by a rule without a duration
+func samplesRequired(rule *types.AlertRule) int {
+	count := rule.Intervals
+	if count < 1 {
+		count = 1
+	}
+	if rule.Operator == types.IncreasedOperator {
+		// The first sample is the baseline for the increase
+		count++
+	}
+	return count
+}
+
+// Window returns the period whose metrics are required to evaluate an alert rule
+// given the interval in which the metrics are collected
+func Window(rule *types.AlertRule, interval time.Duration) time.Duration {
+	if duration, err := time.ParseDuration(rule.For); err == nil && rule.For != "" {
+		return duration + 2*interval
+	}
+	return time.Duration(samplesRequired(rule)+1) * interval
+}
+
+// fillMissing adds samples of a dead container for the intervals in which no metrics were collected
+// AppMaker stops collecting the metrics of a container once it stops hence their absence means downtime,
+// a window without any samples means that the container has been down for the entire window
+func fillMissing(app string, samples []*types.Metrics, now time.Time, window, interval time.Duration) []*types.Metrics {
+	step := int64(interval / time.Second)
+	if step <= 0 {
+		return samples
+	}
+	from, hostIP := now.Add(-window).Unix(), ""
+	if len(samples) > 0 {
+		latest := samples[0]
+		if now.Unix()-latest.ReadTime < 2*step {
+			return samples
+		}
+		from, hostIP = latest.ReadTime+step, latest.HostIP
+	}
+	missing := []*types.Metrics{}
+	for timestamp := from; timestamp <= now.Unix(); timestamp += step {
+		missing = append([]*types.Metrics{{
+			Name:     app,
+			ReadTime: timestamp,
+			Alive:    false,
+			HostIP:   hostIP,
+		}}, missing...)
+	}
+	return append(missing, samples...)
+}
+
+// Evaluate checks whether the condition of an alert rule holds for the metrics of an application
+// The samples must be sorted from the latest to the oldest and the value returned is the
+// latest value of the metric or its increase. The condition cannot be evaluated if there
+// are no recent samples which is denoted by the last value returned, except for the alive
+// metric whose missing samples denote that the application is down
+func Evaluate(rule *types.AlertRule, samples []*types.Metrics, now time.Time, interval time.Duration) (bool, float64, bool) {
+	if rule.Metric == "alive" {
+		samples = fillMissing(rule.App, samples, now, Window(rule, interval), interval)
+	} else if len(samples) == 0 || now.Unix()-samples[0].ReadTime >= int64(2*interval/time.Second) {
+		return false, 0, false
+	}
+	if len(samples) == 0 {
+		return false, 0, false
+	}
+
+	extract := metricExtractors[rule.Metric]
+	latest := extract(samples[0])
+
+	var selected []*types.Metrics
+	if rule.For != "" {
+		duration, err := time.ParseDuration(rule.For)
+		if err != nil {
+			return false, 0, false
+		}
+		since := now.Add(-duration).Unix()
+		// The samples must span the entire duration for the condition to hold
+		if samples[len(samples)-1].ReadTime > since {
+			return false, latest, true
+		}
+		for _, sample := range samples {
+			if sample.ReadTime < since {
+				break
+			}
+			selected = append(selected, sample)
+		}
+	} else {
+		count := samplesRequired(rule)
+		if len(samples) < count {
+			return false, latest, true
+		}
+		selected = samples[:count]
+	}
+
+	if rule.Operator == types.IncreasedOperator {
+		increase := latest - extract(selected[len(selected)-1])
+		return increase > 0, increase, true
+	}
+
+	compare := comparators[rule.Operator]
+	for _, sample := range selected {
+		if !compare(extract(sample), rule.Threshold) {
+			return false, latest, true
+		}
+	}
+	return len(selected) > 0, latest, true
+}
+
+// Describe returns a human readable description of the condition of an alert rule
+func Describe(rule *types.AlertRule) string {
+	condition := fmt.Sprintf("%s %s %g", rule.Metric, rule.Operator, rule.Threshold)
+	if rule.Operator == types.IncreasedOperator {
+		condition = fmt.Sprintf("%s %s", rule.Metric, rule.Operator)
+	}
+	if rule.For != "" {
+		return fmt.Sprintf("%s for %s", condition, rule.For)
+	}
+	if rule.Intervals > 1 {
+		return fmt.Sprintf("%s for %d intervals", condition, rule.Intervals)
+	}
+	return condition
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/types"
+)
+
+// dumpDir is the directory inside the containers of the database servers where the dumps are written
+const dumpDir = "/tmp"
+
+// redisSaveTimeout is the maximum number of seconds waited for a background save of Redis to complete
+const redisSaveTimeout = 3600
+
+// Dump is a dump of a database written inside the container of its server
+type Dump struct {
+	Container string
+	Path      string
+	Format    string
+}
+
+func newDump(container, id, format string) *Dump {
+	return &Dump{
+		Container: container,
+		Path:      fmt.Sprintf("%s/gasper-backup-%s.%s", dumpDir, id, format),
+		Format:    format,
+	}
+}
+
+// shellQuote quotes a value for using it as a single argument in a shell command
+// The values of the environment variables of the database servers need not be strings
+func shellQuote(value interface{}) string {
+	return "'" + strings.ReplaceAll(fmt.Sprint(value), "'", `'\''`) + "'"
+}
+
+// exec executes a shell command inside the container of the dump
+func (dump *Dump) exec(command string) error {
+	_, err := docker.ExecProcessWthStream(dump.Container, []string{"sh", "-c", command})
+	return err
+}
+
+// run executes the shell command writing the dump inside its container
+func (dump *Dump) run(command string) error {
+	if err := dump.exec(command); err != nil {
+		dump.remove()
+		return fmt.Errorf("Error while dumping the database : %s", err)
+	}
+	return nil
+}
+
+// remove deletes the dump from its container
+func (dump *Dump) remove() error {
+	_, err := docker.ExecProcessWthStream(dump.Container, []string{"rm", "-f", dump.Path})
+	return err
+}
+
+// dump dumps a database of the server with mysqldump
+// The dump does not name the database so that it can be restored into another one
+func (server *mysqlServer) dump(db types.Database, id string) (*Dump, error) {
+	dump := newDump(server.container, id, "sql")
+	return dump, dump.run(fmt.Sprintf(
+		"MYSQL_PWD=%s mysqldump -u %s --single-transaction --routines --triggers %s > %s",
+		shellQuote(server.rootPassword), mysqlRootUser, db.GetName(), dump.Path))
+}
+
+// dump dumps a database of the server with pg_dump
+func (server *postgresqlServer) dump(db types.Database, id string) (*Dump, error) {
+	dump := newDump(server.container, id, "sql")
+	return dump, dump.run(fmt.Sprintf(
+		"PGPASSWORD=%s pg_dump -h 127.0.0.1 -U %s --clean --if-exists --no-owner --no-privileges -f %s %s",
+		shellQuote(server.password), shellQuote(server.rootUser), dump.Path, db.GetName()))
+}
+
+// dump dumps a database of the server as an archive with mongodump
+func (server *mongoServer) dump(db types.Database, id string) (*Dump, error) {
+	dump := newDump(server.container, id, "archive")
+	return dump, dump.run(fmt.Sprintf(
+		"mongodump --quiet --username %s --password %s --authenticationDatabase admin --db %s --archive=%s",
+		shellQuote(server.rootUser), shellQuote(server.rootPassword), db.GetName(), dump.Path))
+}
+
+// DumpRedisDB dumps a Redis database by waiting for a background save to complete
+// and copying the resulting RDB file
+func DumpRedisDB(db types.Database, id string) (*Dump, error) {
+	dump := newDump(db.GetName(), id, "rdb")
+	return dump, dump.run(fmt.Sprintf(
+		`export REDISCLI_AUTH=%s; before=$(redis-cli LASTSAVE); redis-cli BGSAVE > /dev/null; i=0; `+
+			`while [ "$(redis-cli LASTSAVE)" = "$before" ]; do i=$((i+1)); [ $i -gt %d ] && exit 1; sleep 1; done; `+
+			`cp /data/dump.rdb %s`,
+		shellQuote(db.GetPassword()), redisSaveTimeout, dump.Path))
+}
+
+// ExportDump copies a dump out of its container into the writer compressed with gzip and
+// removes it from the container
+// It returns the size of the dump and of the compressed dump along with the SHA-256 checksum of the latter
+func ExportDump(dump *Dump, writer io.Writer) (rawSize, size int64, checksum string, err error) {
+	defer dump.remove()
+
+	reader, _, err := docker.CopyFromContainer(dump.Container, dump.Path)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	defer reader.Close()
+
+	archive := tar.NewReader(reader)
+	if _, err = archive.Next(); err != nil {
+		return 0, 0, "", fmt.Errorf("Error while reading the dump : %s", err)
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{writer: io.MultiWriter(writer, hash)}
+	compressor := gzip.NewWriter(counter)
+	if rawSize, err = io.Copy(compressor, archive); err != nil {
+		return 0, 0, "", err
+	}
+	if err = compressor.Close(); err != nil {
+		return 0, 0, "", err
+	}
+	return rawSize, counter.count, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// countingWriter counts the number of bytes written through it
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.writer.Write(p)
+	cw.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// The engines without a shared server deploy every database in its own container named after the database,
+// whose data is stored in a directory of the host and whose memory and CPUs are limited by its quota
+
+// databaseStoreDir returns the directory in the host holding the data of a database deployed in its own container
+func databaseStoreDir(engine, databaseName string) string {
+	return filepath.Join(storepath, engine+"-storage", databaseName)
+}
+
+// allocateDatabaseContainer assigns a free port of the host to a database deployed in its own container
+// and creates the directory holding its data
+func allocateDatabaseContainer(engine string, db types.Database) (string, error) {
+	if _, err := docker.InspectContainer(db.GetName()); err == nil {
+		return "", ErrDatabaseExists
+	}
+
+	port, err := utils.GetFreePort()
+	if err != nil {
+		return "", fmt.Errorf("Error while getting free port for container : %s", err)
+	}
+
+	storedir := databaseStoreDir(engine, db.GetName())
+	if err := os.MkdirAll(storedir, 0755); err != nil {
+		return "", fmt.Errorf("Error while creating the directory : %s", err)
+	}
+
+	db.SetContainerPort(port)
+	return storedir, nil
+}
+
+// startDatabaseContainer creates and starts the container of a database deployed in its own container
+func startDatabaseContainer(db types.Database, container types.DatabaseContainer) error {
+	quota := db.GetQuota()
+	container.Name = db.GetName()
+	container.ContainerPort = db.GetContainerPort()
+	container.Memory = quota.GetMemoryLimit()
+	container.CPU = quota.GetCPULimit()
+
+	containerID, err := docker.CreateDatabaseContainer(container)
+	if err != nil {
+		return types.NewResErr(500, "container not created", err)
+	}
+
+	if err := docker.StartContainer(containerID); err != nil {
+		return types.NewResErr(500, "container not started", err)
+	}
+	return nil
+}
+
+// recreateDatabaseContainer replaces the container of a database deployed in its own container
+// with a new one keeping its data
+func recreateDatabaseContainer(db types.Database, container types.DatabaseContainer) error {
+	if err := docker.DeleteContainer(db.GetName()); err != nil {
+		return types.NewResErr(500, "container not deleted", err)
+	}
+	return startDatabaseContainer(db, container)
+}
+
+// deleteDatabaseContainer deletes the container of a database deployed in its own container along with its data
+func deleteDatabaseContainer(engine, databaseName string) error {
+	if err := docker.DeleteContainer(databaseName); err != nil {
+		return types.NewResErr(500, "container not deleted", err)
+	}
+
+	if err := os.RemoveAll(databaseStoreDir(engine, databaseName)); err != nil {
+		return fmt.Errorf("Error while deleting the database directory : %s", err)
+	}
+	return nil
+}
+
+// containerUsage returns the size of the data of a database deployed in its own container
+// along with the memory used by its container
+func containerUsage(engine string, db types.Database) (*types.DatabaseUsage, error) {
+	usage := &types.DatabaseUsage{}
+	err := filepath.Walk(databaseStoreDir(engine, db.GetName()), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			usage.Size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the size of the database : %s", err)
+	}
+
+	if usage.Memory, err = containerMemory(db.GetName()); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// containerMemory returns the memory used by the container of a database
+func containerMemory(databaseName string) (int64, error) {
+	stats, err := docker.ContainerStats(databaseName)
+	if err != nil {
+		return 0, fmt.Errorf("Error while fetching the statistics of the container : %s", err)
+	}
+	return int64(stats.Memory.Usage), nil
+}
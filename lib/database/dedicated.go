@@ -0,0 +1,130 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sdslabs/gasper/types"
+)
+
+// A dedicated database is deployed in its own container running a server of its type instead of sharing
+// the server of its type with other databases, so that it has its own port, data directory, resource limits
+// and version of the server
+// The database is created in the server of its container like in the shared server, and is managed likewise
+
+// dedicatedStartupTimeout is the maximum number of seconds waited for the server of a dedicated database
+// to accept connections
+const dedicatedStartupTimeout = 120
+
+// dedicatedServer deploys the servers of an engine in the containers of dedicated databases
+type dedicatedServer struct {
+	// name is the type of the databases managed by the engine
+	name string
+
+	// container returns the container of the server of a dedicated database
+	container func(types.Database) types.DatabaseContainer
+
+	// bind returns the engine managing a dedicated database in the server of its container along with
+	// a function checking whether the server accepts connections
+	bind func(types.Database) (*Engine, func() error)
+}
+
+// dedicatedImage returns the image of an engine tagged with the version of a dedicated database
+// The image itself is returned if no version is given
+func dedicatedImage(image, version string) string {
+	if version == "" {
+		return image
+	}
+	// The registry of the image might have a port which is not to be mistaken for its tag
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		image = image[:index]
+	}
+	return image + ":" + version
+}
+
+// storage returns the name of the directory holding the data of the dedicated databases of the engine
+// which is kept apart from the data of the shared server
+func (server *dedicatedServer) storage() string {
+	return server.name + "-dedicated"
+}
+
+// engine returns the engine managing a dedicated database
+func (server *dedicatedServer) engine(db types.Database) *Engine {
+	tenant, _ := server.bind(db)
+	engine := *tenant
+	engine.Image = server.container(db).Image
+	engine.Server = nil
+	engine.Dedicated = nil
+	engine.Create = server.create
+	engine.Delete = server.delete
+	if tenant.Usage != nil {
+		engine.Usage = server.usage
+	}
+	engine.ApplyQuota = server.applyQuota
+	return &engine
+}
+
+// start starts the container of the server of a dedicated database with the given function and waits
+// for the server to accept connections
+func (server *dedicatedServer) start(db types.Database, start func(types.Database, types.DatabaseContainer) error) error {
+	container := server.container(db)
+	container.StoreDir = databaseStoreDir(server.storage(), db.GetName())
+	if err := start(db, container); err != nil {
+		return err
+	}
+
+	_, ping := server.bind(db)
+	var err error
+	for i := 0; i < dedicatedStartupTimeout; i++ {
+		if err = ping(); err == nil {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("Error while starting the server of the database : %s", err)
+}
+
+// create deploys the server of a dedicated database in its own container and creates the database in it
+func (server *dedicatedServer) create(db types.Database) error {
+	if _, err := allocateDatabaseContainer(server.storage(), db); err != nil {
+		return err
+	}
+	if err := server.start(db, startDatabaseContainer); err != nil {
+		return err
+	}
+	tenant, _ := server.bind(db)
+	return tenant.Create(db)
+}
+
+// delete deletes the container of a dedicated database along with its data
+func (server *dedicatedServer) delete(databaseName string) error {
+	return deleteDatabaseContainer(server.storage(), databaseName)
+}
+
+// usage returns the resources consumed by a dedicated database in its server along with the memory
+// used by its container
+func (server *dedicatedServer) usage(db types.Database) (*types.DatabaseUsage, error) {
+	tenant, _ := server.bind(db)
+	usage, err := tenant.Usage(db)
+	if err != nil {
+		return nil, err
+	}
+	if usage.Memory, err = containerMemory(db.GetName()); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// applyQuota recreates the container of a dedicated database with the limits of its quota and applies
+// the rest of the quota to its server
+func (server *dedicatedServer) applyQuota(db types.Database, users []*types.DatabaseUser) error {
+	if err := server.start(db, recreateDatabaseContainer); err != nil {
+		return err
+	}
+	tenant, _ := server.bind(db)
+	if tenant.ApplyQuota == nil {
+		return nil
+	}
+	return tenant.ApplyQuota(db, users)
+}
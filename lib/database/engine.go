@@ -0,0 +1,107 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/types"
+)
+
+// ErrDatabaseExists is returned by the engines when a database cannot be created since a database or a
+// container with its name already exists, which is left untouched as nothing was created for the database
+var ErrDatabaseExists = errors.New("Error while creating the database : Database Already Exists")
+
+// Engine manages the databases of a type (eg:- MySQL, Redis etc) deployed by DbMaker
+// The operations left nil are not supported by the engine
+type Engine struct {
+	// Name is the type of the databases managed by the engine
+	Name string
+
+	// Image is the docker image of the containers of the engine
+	Image string
+
+	// Server is the container of the database server shared by all databases of the engine
+	// It is nil for the engines deploying every database in its own container
+	Server *types.DatabaseContainer
+
+	// Dedicated returns the engine managing a database deployed in its own container running a server
+	// of the engine, see dedicated.go
+	// It is nil for the engines not supporting dedicated databases
+	Dedicated func(types.Database) *Engine
+
+	Create         func(types.Database) error
+	Delete         func(string) error
+	Dump           func(types.Database, string) (*Dump, error)
+	Restore        func(types.Database, *types.Backup, io.Reader) error
+	RotatePassword func(types.Database, string, string) error
+	CreateUser     func(types.Database, *types.DatabaseUser) error
+	DeleteUser     func(types.Database, *types.DatabaseUser) error
+	Usage          func(types.Database) (*types.DatabaseUsage, error)
+	ApplyQuota     func(types.Database, []*types.DatabaseUser) error
+	RestrictWrites func(types.Database, []*types.DatabaseUser, bool) error
+	Metrics        func(types.Database) (*types.DatabaseMetrics, error)
+
+	// Query runs an ad hoc query of the query console on a database, see query.go
+	// It is nil for the engines not supporting the query console
+	Query func(types.Database, *types.DatabaseQuery) (*types.QueryResult, error)
+}
+
+// Config returns the configuration of the engine in DbMaker
+func (engine *Engine) Config() configs.DatabaseService {
+	return configs.ServiceConfig.DbMaker.Engines()[engine.Name]
+}
+
+// Images returns the docker images of the containers of the engine, which include the images of
+// the versions of the server allowed for dedicated databases
+func (engine *Engine) Images() []string {
+	images := []string{engine.Image}
+	if engine.Dedicated == nil {
+		return images
+	}
+	for _, version := range engine.Config().Versions {
+		images = append(images, dedicatedImage(engine.Image, version))
+	}
+	return images
+}
+
+// engines maps the type of database to the engine managing it
+var engines = make(map[string]*Engine)
+
+// RegisterEngine makes an engine available for managing the databases of its type
+// The engine must have a configuration in DbMaker and it panics if the type is already registered
+func RegisterEngine(engine *Engine) {
+	if _, ok := configs.ServiceConfig.DbMaker.Engines()[engine.Name]; !ok {
+		panic(fmt.Sprintf("database: no configuration for engine %s", engine.Name))
+	}
+	if _, ok := engines[engine.Name]; ok {
+		panic(fmt.Sprintf("database: engine %s registered twice", engine.Name))
+	}
+	if engine.Create == nil || engine.Delete == nil {
+		panic(fmt.Sprintf("database: engine %s cannot create or delete databases", engine.Name))
+	}
+	engines[engine.Name] = engine
+	if engine.Server != nil {
+		databaseMap[engine.Name] = *engine.Server
+	}
+}
+
+// LookupEngine returns the engine managing the databases of a type
+func LookupEngine(name string) (*Engine, bool) {
+	engine, ok := engines[name]
+	return engine, ok
+}
+
+// Engines returns the registered engines sorted by their type
+func Engines() []*Engine {
+	list := make([]*Engine, 0, len(engines))
+	for _, engine := range engines {
+		list = append(list, engine)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -12,17 +12,9 @@ import (
 
 var storepath, _ = os.Getwd()
 
-// Maps database name with its appropriate configuration
+// databaseMap maps the database servers to the configuration of their containers
+// The servers of the registered engines are added to it on their registration
 var databaseMap = map[string]types.DatabaseContainer{
-	types.MongoDB: {
-		Image:         configs.ImageConfig.Mongodb,
-		ContainerPort: configs.ServiceConfig.DbMaker.MongoDB.ContainerPort,
-		DatabasePort:  27017,
-		Env:           configs.ServiceConfig.DbMaker.MongoDB.Env,
-		WorkDir:       "/data/db",
-		StoreDir:      filepath.Join(storepath, "mongodb-storage"),
-		Name:          types.MongoDB,
-	},
 	types.MongoDBGasper: {
 		Image:         configs.ImageConfig.Mongodb,
 		ContainerPort: configs.ServiceConfig.Master.MongoDB.ContainerPort,
@@ -32,15 +24,6 @@ var databaseMap = map[string]types.DatabaseContainer{
 		StoreDir:      filepath.Join(storepath, "gasper-mongodb-storage"),
 		Name:          types.MongoDBGasper,
 	},
-	types.MySQL: {
-		Image:         configs.ImageConfig.Mysql,
-		ContainerPort: configs.ServiceConfig.DbMaker.MySQL.ContainerPort,
-		DatabasePort:  3306,
-		Env:           configs.ServiceConfig.DbMaker.MySQL.Env,
-		WorkDir:       "/app",
-		StoreDir:      filepath.Join(storepath, "mysql-storage"),
-		Name:          types.MySQL,
-	},
 	types.RedisGasper: {
 		Image:         configs.ImageConfig.Redis,
 		ContainerPort: configs.ServiceConfig.Master.Redis.ContainerPort,
@@ -50,15 +33,6 @@ var databaseMap = map[string]types.DatabaseContainer{
 		Name:          types.RedisGasper,
 		Cmd:           []string{"redis-server", "--requirepass", configs.ServiceConfig.Master.Redis.Password},
 	},
-	types.PostgreSQL: {
-		Image:         configs.ImageConfig.Postgresql,
-		ContainerPort: configs.ServiceConfig.DbMaker.PostgreSQL.ContainerPort,
-		DatabasePort:  5432,
-		Env:           configs.ServiceConfig.DbMaker.PostgreSQL.Env,
-		WorkDir:       "/var/lib/postgresql/data",
-		StoreDir:      filepath.Join(storepath, "postgresql-storage"),
-		Name:          types.PostgreSQL,
-	},
 }
 
 // SetupDBInstance sets up containers for database
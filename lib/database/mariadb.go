@@ -0,0 +1,52 @@
+package database
+
+import (
+	"path/filepath"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/types"
+)
+
+// mariaDB is the MariaDB server which speaks the MySQL protocol and is hence managed like the MySQL server
+var mariaDB = &mysqlServer{
+	container:    types.MariaDB,
+	port:         configs.ServiceConfig.DbMaker.MariaDB.ContainerPort,
+	rootPassword: configs.ServiceConfig.DbMaker.MariaDB.Env["MARIADB_ROOT_PASSWORD"],
+}
+
+// mariaDBDedicated deploys the MariaDB servers of dedicated databases
+var mariaDBDedicated = &dedicatedServer{
+	name: types.MariaDB,
+	container: func(db types.Database) types.DatabaseContainer {
+		return types.DatabaseContainer{
+			Image:        dedicatedImage(configs.ImageConfig.Mariadb, db.GetVersion()),
+			DatabasePort: 3306,
+			Env:          types.M{"MARIADB_ROOT_PASSWORD": db.GetRootPassword()},
+			WorkDir:      "/var/lib/mysql",
+		}
+	},
+	bind: func(db types.Database) (*Engine, func() error) {
+		server := &mysqlServer{
+			container:    db.GetName(),
+			port:         db.GetContainerPort(),
+			rootPassword: db.GetRootPassword(),
+		}
+		return server.engine(types.MariaDB), server.ping
+	},
+}
+
+func init() {
+	engine := mariaDB.engine(types.MariaDB)
+	engine.Image = configs.ImageConfig.Mariadb
+	engine.Server = &types.DatabaseContainer{
+		Image:         configs.ImageConfig.Mariadb,
+		ContainerPort: mariaDB.port,
+		DatabasePort:  3306,
+		Env:           configs.ServiceConfig.DbMaker.MariaDB.Env,
+		WorkDir:       "/var/lib/mysql",
+		StoreDir:      filepath.Join(storepath, "mariadb-storage"),
+		Name:          types.MariaDB,
+	}
+	engine.Dedicated = mariaDBDedicated.engine
+	RegisterEngine(engine)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// memcachedPasswordFile is the name of the file holding the SASL credentials of a Memcached database in its directory
+	memcachedPasswordFile = "memcached-sasl-pwdb"
+
+	// memcachedSASLConfig is the name of the SASL configuration of a Memcached database in its directory
+	memcachedSASLConfig = "memcached.conf"
+)
+
+func init() {
+	RegisterEngine(&Engine{
+		Name:           types.Memcached,
+		Image:          configs.ImageConfig.Memcached,
+		Create:         CreateMemcachedDB,
+		Delete:         DeleteMemcachedDB,
+		RotatePassword: RotateMemcachedPassword,
+		Usage:          MemcachedUsage,
+		ApplyQuota:     ApplyMemcachedQuota,
+	})
+}
+
+// memcachedContainer returns the container of a Memcached database which authenticates its clients with SASL
+// The size quota of the database is the memory used by the server for storing items
+func memcachedContainer(db types.Database) types.DatabaseContainer {
+	quota := db.GetQuota()
+	cmd := []string{"memcached", "-S"}
+	if quota.Size > 0 {
+		cmd = append(cmd, "-m", fmt.Sprint(quota.Size))
+	}
+	if quota.Connections > 0 {
+		cmd = append(cmd, "-c", fmt.Sprint(quota.Connections))
+	}
+
+	return types.DatabaseContainer{
+		Image:        configs.ImageConfig.Memcached,
+		DatabasePort: 11211,
+		WorkDir:      "/data",
+		StoreDir:     databaseStoreDir(types.Memcached, db.GetName()),
+		Cmd:          cmd,
+		Env: types.M{
+			"MEMCACHED_SASL_PWDB": "/data/" + memcachedPasswordFile,
+			"SASL_CONF_PATH":      "/data",
+		},
+	}
+}
+
+// writeMemcachedPassword replaces the SASL credentials of a Memcached database
+// The files are readable by everyone since the server does not run as root
+func writeMemcachedPassword(db types.Database, password string) error {
+	storedir := databaseStoreDir(types.Memcached, db.GetName())
+	err := ioutil.WriteFile(filepath.Join(storedir, memcachedSASLConfig), []byte("mech_list: plain\n"), 0644)
+	if err != nil {
+		return err
+	}
+	credentials := fmt.Sprintf("%s:%s\n", db.GetUser(), password)
+	return ioutil.WriteFile(filepath.Join(storedir, memcachedPasswordFile), []byte(credentials), 0644)
+}
+
+// CreateMemcachedDB creates a Memcached container
+func CreateMemcachedDB(db types.Database) error {
+	if _, err := allocateDatabaseContainer(types.Memcached, db); err != nil {
+		return err
+	}
+	if err := writeMemcachedPassword(db, db.GetPassword()); err != nil {
+		return fmt.Errorf("Error while writing the credentials of the database : %s", err)
+	}
+	return startDatabaseContainer(db, memcachedContainer(db))
+}
+
+// DeleteMemcachedDB deletes a Memcached container
+func DeleteMemcachedDB(databaseName string) error {
+	return deleteDatabaseContainer(types.Memcached, databaseName)
+}
+
+// RotateMemcachedPassword changes the password of a Memcached database
+// The server loads the credentials only on startup hence it is restarted, which empties the cache
+func RotateMemcachedPassword(db types.Database, user, password string) error {
+	if user != db.GetUser() {
+		return fmt.Errorf("User %s does not exist", user)
+	}
+	if err := writeMemcachedPassword(db, password); err != nil {
+		writeMemcachedPassword(db, db.GetPassword())
+		return fmt.Errorf("Error while writing the credentials of the database : %s", err)
+	}
+	if err := docker.ContainerRestart(db.GetName()); err != nil {
+		return types.NewResErr(500, "container not restarted", err)
+	}
+	return nil
+}
+
+// MemcachedUsage returns the memory used by the container of a Memcached database which is also
+// the size of the database since the items are only held in memory
+func MemcachedUsage(db types.Database) (*types.DatabaseUsage, error) {
+	usage, err := containerUsage(types.Memcached, db)
+	if err != nil {
+		return nil, err
+	}
+	usage.Size = usage.Memory
+	return usage, nil
+}
+
+// ApplyMemcachedQuota recreates the container of a Memcached database with the limits of its quota
+func ApplyMemcachedQuota(db types.Database, users []*types.DatabaseUser) error {
+	return recreateDatabaseContainer(db, memcachedContainer(db))
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sdslabs/gasper/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The metrics of a database are read from the catalog of its server
+// The number of queries is a counter kept by the server from which the query rate is derived
+
+// metrics returns the metrics of a database of the server
+// The queries are counted from the statement digests summarized by the performance schema
+func (server *mysqlServer) metrics(db types.Database) (*types.DatabaseMetrics, error) {
+	usage, err := server.usage(db)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := server.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	metrics := &types.DatabaseMetrics{
+		Size:        usage.Size,
+		Connections: int64(usage.Connections),
+	}
+	row := conn.QueryRow("SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?", db.GetName())
+	if err := row.Scan(&metrics.Tables); err != nil {
+		return nil, fmt.Errorf("Error while fetching the tables of the database : %s", err)
+	}
+	row = conn.QueryRow(
+		"SELECT COALESCE(SUM(COUNT_STAR), 0) FROM performance_schema.events_statements_summary_by_digest WHERE SCHEMA_NAME = ?",
+		db.GetName())
+	if err := row.Scan(&metrics.Queries); err != nil {
+		return nil, fmt.Errorf("Error while fetching the queries of the database : %s", err)
+	}
+	return metrics, nil
+}
+
+// metrics returns the metrics of a database of the server
+// The queries are counted as the transactions committed or rolled back in the database
+func (server *postgresqlServer) metrics(db types.Database) (*types.DatabaseMetrics, error) {
+	usage, err := server.usage(db)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	conn, err := server.connect(ctx, db.GetName())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	metrics := &types.DatabaseMetrics{
+		Size:        usage.Size,
+		Connections: int64(usage.Connections),
+	}
+	if err := conn.QueryRow(ctx, "SELECT count(*) FROM pg_stat_user_tables").Scan(&metrics.Tables); err != nil {
+		return nil, fmt.Errorf("Error while fetching the tables of the database : %s", err)
+	}
+	err = conn.QueryRow(ctx,
+		"SELECT xact_commit + xact_rollback FROM pg_stat_database WHERE datname = $1",
+		db.GetName()).Scan(&metrics.Queries)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the queries of the database : %s", err)
+	}
+	return metrics, nil
+}
+
+// metrics returns the metrics of a database of the server
+// The queries are counted from the operations on the collections of the database reported by `top`
+func (server *mongoServer) metrics(db types.Database) (*types.DatabaseMetrics, error) {
+	usage, err := server.usage(db)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect(ctx)
+
+	collections, err := client.Database(db.GetName()).ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the collections of the database : %s", err)
+	}
+	top := bson.M{}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "top", Value: 1}}).Decode(&top); err != nil {
+		return nil, fmt.Errorf("Error while fetching the queries of the database : %s", err)
+	}
+
+	metrics := &types.DatabaseMetrics{
+		Size:        usage.Size,
+		Tables:      int64(len(collections)),
+		Connections: int64(usage.Connections),
+	}
+	totals, _ := top["totals"].(bson.M)
+	for namespace, operations := range totals {
+		if !strings.HasPrefix(namespace, db.GetName()+".") {
+			continue
+		}
+		if operations, ok := operations.(bson.M); ok {
+			if total, ok := operations["total"].(bson.M); ok {
+				metrics.Queries += bsonInt(total["count"])
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// RedisMetrics returns the metrics of a Redis database where the tables are the keys of all its
+// logical databases and the queries are the commands processed by its server
+func RedisMetrics(db types.Database) (*types.DatabaseMetrics, error) {
+	info, err := redisInfo(db)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the metrics of the database : %s", err)
+	}
+	metrics := &types.DatabaseMetrics{}
+	metrics.Size, _ = strconv.ParseInt(info["used_memory"], 10, 64)
+	metrics.Connections, _ = strconv.ParseInt(info["connected_clients"], 10, 64)
+	metrics.Queries, _ = strconv.ParseInt(info["total_commands_processed"], 10, 64)
+	for field, value := range info {
+		// The keyspace of every logical database is reported as `db0:keys=1,expires=0,avg_ttl=0`
+		if !strings.HasPrefix(field, "db") {
+			continue
+		}
+		for _, stat := range strings.Split(value, ",") {
+			if strings.HasPrefix(stat, "keys=") {
+				keys, _ := strconv.ParseInt(strings.TrimPrefix(stat, "keys="), 10, 64)
+				metrics.Tables += keys
+			}
+		}
+	}
+	return metrics, nil
+}
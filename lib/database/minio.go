@@ -0,0 +1,81 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// minioMinUserLength is the minimum length of the access key of a MinIO database
+	minioMinUserLength = 3
+
+	// minioMinPasswordLength is the minimum length of the secret key of a MinIO database
+	minioMinPasswordLength = 8
+)
+
+func init() {
+	RegisterEngine(&Engine{
+		Name:           types.MinIO,
+		Image:          configs.ImageConfig.Minio,
+		Create:         CreateMinIODB,
+		Delete:         DeleteMinIODB,
+		RotatePassword: RotateMinIOPassword,
+		Usage:          MinIOUsage,
+		ApplyQuota:     ApplyMinIOQuota,
+	})
+}
+
+// minioContainer returns the container of a MinIO object store whose root user is the user of the database
+func minioContainer(db types.Database, password string) types.DatabaseContainer {
+	return types.DatabaseContainer{
+		Image:        configs.ImageConfig.Minio,
+		DatabasePort: 9000,
+		WorkDir:      "/data",
+		StoreDir:     databaseStoreDir(types.MinIO, db.GetName()),
+		Cmd:          []string{"server", "/data"},
+		Env: types.M{
+			"MINIO_ROOT_USER":     db.GetUser(),
+			"MINIO_ROOT_PASSWORD": password,
+		},
+	}
+}
+
+// CreateMinIODB creates a MinIO container where the user and the password of the database
+// are the access key and the secret key of the object store
+func CreateMinIODB(db types.Database) error {
+	if len(db.GetUser()) < minioMinUserLength {
+		return fmt.Errorf("Name of a %s database should have at least %d characters", types.MinIO, minioMinUserLength)
+	}
+	if len(db.GetPassword()) < minioMinPasswordLength {
+		return fmt.Errorf("Password of a %s database should have at least %d characters", types.MinIO, minioMinPasswordLength)
+	}
+	if _, err := allocateDatabaseContainer(types.MinIO, db); err != nil {
+		return err
+	}
+	return startDatabaseContainer(db, minioContainer(db, db.GetPassword()))
+}
+
+// DeleteMinIODB deletes a MinIO container along with its objects
+func DeleteMinIODB(databaseName string) error {
+	return deleteDatabaseContainer(types.MinIO, databaseName)
+}
+
+// RotateMinIOPassword changes the secret key of a MinIO database by recreating its container
+func RotateMinIOPassword(db types.Database, user, password string) error {
+	if user != db.GetUser() {
+		return fmt.Errorf("User %s does not exist", user)
+	}
+	return recreateDatabaseContainer(db, minioContainer(db, password))
+}
+
+// MinIOUsage returns the size of the objects stored in a MinIO database and the memory used by its container
+func MinIOUsage(db types.Database) (*types.DatabaseUsage, error) {
+	return containerUsage(types.MinIO, db)
+}
+
+// ApplyMinIOQuota recreates the container of a MinIO database with the limits of its quota
+func ApplyMinIOQuota(db types.Database, users []*types.DatabaseUser) error {
+	return recreateDatabaseContainer(db, minioContainer(db, db.GetPassword()))
+}
@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/utils"
@@ -12,14 +14,86 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var (
-	mongoRootUser     = configs.ServiceConfig.DbMaker.MongoDB.Env["MONGO_INITDB_ROOT_USERNAME"]
-	mongoRootPassword = configs.ServiceConfig.DbMaker.MongoDB.Env["MONGO_INITDB_ROOT_PASSWORD"]
-	mongoPort         = configs.ServiceConfig.DbMaker.MongoDB.ContainerPort
-)
+// mongoDedicatedRootUser is the root user of the servers of dedicated databases
+const mongoDedicatedRootUser = "root"
+
+// mongoServer is a MongoDB server whose databases are managed by DbMaker
+type mongoServer struct {
+	// container is the name of the container of the server
+	container    string
+	port         int
+	rootUser     interface{}
+	rootPassword interface{}
+}
+
+var mongoDB = &mongoServer{
+	container:    types.MongoDB,
+	port:         configs.ServiceConfig.DbMaker.MongoDB.ContainerPort,
+	rootUser:     configs.ServiceConfig.DbMaker.MongoDB.Env["MONGO_INITDB_ROOT_USERNAME"],
+	rootPassword: configs.ServiceConfig.DbMaker.MongoDB.Env["MONGO_INITDB_ROOT_PASSWORD"],
+}
+
+// mongoDedicated deploys the MongoDB servers of dedicated databases
+var mongoDedicated = &dedicatedServer{
+	name: types.MongoDB,
+	container: func(db types.Database) types.DatabaseContainer {
+		return types.DatabaseContainer{
+			Image:        dedicatedImage(configs.ImageConfig.Mongodb, db.GetVersion()),
+			DatabasePort: 27017,
+			Env: types.M{
+				"MONGO_INITDB_ROOT_USERNAME": mongoDedicatedRootUser,
+				"MONGO_INITDB_ROOT_PASSWORD": db.GetRootPassword(),
+			},
+			WorkDir: "/data/db",
+		}
+	},
+	bind: func(db types.Database) (*Engine, func() error) {
+		server := &mongoServer{
+			container:    db.GetName(),
+			port:         db.GetContainerPort(),
+			rootUser:     mongoDedicatedRootUser,
+			rootPassword: db.GetRootPassword(),
+		}
+		return server.engine(), server.ping
+	},
+}
+
+func init() {
+	engine := mongoDB.engine()
+	engine.Image = configs.ImageConfig.Mongodb
+	engine.Server = &types.DatabaseContainer{
+		Image:         configs.ImageConfig.Mongodb,
+		ContainerPort: mongoDB.port,
+		DatabasePort:  27017,
+		Env:           configs.ServiceConfig.DbMaker.MongoDB.Env,
+		WorkDir:       "/data/db",
+		StoreDir:      filepath.Join(storepath, "mongodb-storage"),
+		Name:          types.MongoDB,
+	}
+	engine.Dedicated = mongoDedicated.engine
+	RegisterEngine(engine)
+}
+
+// engine returns the engine managing the databases of the server
+func (server *mongoServer) engine() *Engine {
+	return &Engine{
+		Name:           types.MongoDB,
+		Create:         server.create,
+		Delete:         server.delete,
+		Dump:           server.dump,
+		Restore:        server.restore,
+		RotatePassword: server.rotatePassword,
+		CreateUser:     server.createUser,
+		DeleteUser:     server.deleteUser,
+		Usage:          server.usage,
+		RestrictWrites: server.restrictWrites,
+		Metrics:        server.metrics,
+		Query:          server.query,
+	}
+}
 
-func createConnection(ctx context.Context) (*mongo.Client, error) {
-	connectionURI := fmt.Sprintf("mongodb://%v:%v@127.0.0.1:%d/admin", mongoRootUser, mongoRootPassword, mongoPort)
+func (server *mongoServer) connect(ctx context.Context) (*mongo.Client, error) {
+	connectionURI := fmt.Sprintf("mongodb://%v:%v@127.0.0.1:%d/admin", server.rootUser, server.rootPassword, server.port)
 	client, err := mongo.NewClient(options.Client().ApplyURI(connectionURI))
 	if err != nil {
 		return nil, fmt.Errorf("couldn't connect to mongo: %s", err.Error())
@@ -31,6 +105,19 @@ func createConnection(ctx context.Context) (*mongo.Client, error) {
 	return client, nil
 }
 
+// ping checks whether the server accepts connections
+func (server *mongoServer) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	return client.Ping(ctx, nil)
+}
+
 func exec(ctx context.Context, conn *mongo.Database, command interface{}) error {
 	v := &(mongo.SingleResult{})
 	result := conn.RunCommand(ctx, command)
@@ -74,13 +161,13 @@ func refreshMongoDBUser(ctx context.Context, conn *mongo.Database, db types.Data
 	return nil
 }
 
-// CreateMongoDB creates a database in the mongodb instance with the given database name, user and password
-func CreateMongoDB(db types.Database) error {
+// create creates a database in the server with the given database name, user and password
+func (server *mongoServer) create(db types.Database) error {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	client, err := createConnection(ctx)
+	client, err := server.connect(ctx)
 	if err != nil {
 		return err
 	}
@@ -91,7 +178,7 @@ func CreateMongoDB(db types.Database) error {
 	}
 
 	if utils.Contains(databases, db.GetName()) {
-		return fmt.Errorf("Error while creating the database : Database already Exists")
+		return ErrDatabaseExists
 	}
 
 	conn := client.Database(db.GetName())
@@ -106,13 +193,13 @@ func CreateMongoDB(db types.Database) error {
 	return nil
 }
 
-// DeleteMongoDB deletes a mongo database
-func DeleteMongoDB(databaseName string) error {
+// delete deletes the database given by the database name and username from the server
+func (server *mongoServer) delete(databaseName string) error {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	client, err := createConnection(ctx)
+	client, err := server.connect(ctx)
 	if err != nil {
 		return err
 	}
@@ -128,3 +215,78 @@ func DeleteMongoDB(databaseName string) error {
 	}
 	return nil
 }
+
+// mongoRoles maps the roles of the additional users of a database to the built-in roles of MongoDB
+var mongoRoles = map[string]string{
+	types.ReadOnlyRole:  "read",
+	types.ReadWriteRole: "readWrite",
+}
+
+// rotatePassword changes the password of a user of a database of the server
+func (server *mongoServer) rotatePassword(db types.Database, user, password string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	err = exec(ctx, client.Database(db.GetName()), bson.D{
+		{Key: "updateUser", Value: user},
+		{Key: "pwd", Value: password},
+	})
+	if err != nil {
+		return fmt.Errorf("Error while changing the password : %s", err.Error())
+	}
+	return nil
+}
+
+// createUser creates an additional user of a database of the server with the built-in role matching its role
+func (server *mongoServer) createUser(db types.Database, user *types.DatabaseUser) error {
+	role, ok := mongoRoles[user.Role]
+	if !ok {
+		return fmt.Errorf("Invalid role %s", user.Role)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	err = exec(ctx, client.Database(db.GetName()), bson.D{
+		{Key: "createUser", Value: user.GetUser()},
+		{Key: "pwd", Value: user.Password},
+		{Key: "roles", Value: bson.A{
+			bson.D{
+				{Key: "role", Value: role},
+				{Key: "db", Value: db.GetName()},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("Error while creating the user : %s", err.Error())
+	}
+	return nil
+}
+
+// deleteUser deletes an additional user of a database of the server
+func (server *mongoServer) deleteUser(db types.Database, user *types.DatabaseUser) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	if err = dropUser(ctx, client.Database(db.GetName()), user.GetUser()); err != nil {
+		return fmt.Errorf("Error while deleting the user : %s", err.Error())
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"path/filepath"
 
 	_ "github.com/go-sql-driver/mysql" // MySQL driver
 	"github.com/sdslabs/gasper/configs"
@@ -10,29 +11,96 @@ import (
 )
 
 var (
-	mysqlDriver       = "mysql"
-	mysqlHost         = `%`
-	mysqlRootUser     = "root"
-	mysqlPort         = configs.ServiceConfig.DbMaker.MySQL.ContainerPort
-	mysqlRootPassword = configs.ServiceConfig.DbMaker.MySQL.Env["MYSQL_ROOT_PASSWORD"]
+	mysqlDriver   = "mysql"
+	mysqlHost     = `%`
+	mysqlRootUser = "root"
 )
 
-// CreateMysqlDB creates a database in the Mysql instance with the given database name, user and password
-func CreateMysqlDB(db types.Database) error {
-	agentAddress := fmt.Sprintf("tcp(127.0.0.1:%d)", mysqlPort)
-	connection := fmt.Sprintf("%s:%v@%s/", mysqlRootUser, mysqlRootPassword, agentAddress)
+// mysqlServer is a database server speaking the MySQL protocol, like MySQL and MariaDB,
+// whose databases are managed by DbMaker
+type mysqlServer struct {
+	// container is the name of the container of the server
+	container    string
+	port         int
+	rootPassword interface{}
+}
 
-	conn, err := sql.Open(mysqlDriver, connection)
+var mysqlDB = &mysqlServer{
+	container:    types.MySQL,
+	port:         configs.ServiceConfig.DbMaker.MySQL.ContainerPort,
+	rootPassword: configs.ServiceConfig.DbMaker.MySQL.Env["MYSQL_ROOT_PASSWORD"],
+}
+
+// mysqlDedicated deploys the MySQL servers of dedicated databases
+var mysqlDedicated = &dedicatedServer{
+	name: types.MySQL,
+	container: func(db types.Database) types.DatabaseContainer {
+		return types.DatabaseContainer{
+			Image:        dedicatedImage(configs.ImageConfig.Mysql, db.GetVersion()),
+			DatabasePort: 3306,
+			Env:          types.M{"MYSQL_ROOT_PASSWORD": db.GetRootPassword()},
+			WorkDir:      "/var/lib/mysql",
+		}
+	},
+	bind: func(db types.Database) (*Engine, func() error) {
+		server := &mysqlServer{
+			container:    db.GetName(),
+			port:         db.GetContainerPort(),
+			rootPassword: db.GetRootPassword(),
+		}
+		return server.engine(types.MySQL), server.ping
+	},
+}
+
+func init() {
+	engine := mysqlDB.engine(types.MySQL)
+	engine.Image = configs.ImageConfig.Mysql
+	engine.Server = &types.DatabaseContainer{
+		Image:         configs.ImageConfig.Mysql,
+		ContainerPort: mysqlDB.port,
+		DatabasePort:  3306,
+		Env:           configs.ServiceConfig.DbMaker.MySQL.Env,
+		WorkDir:       "/app",
+		StoreDir:      filepath.Join(storepath, "mysql-storage"),
+		Name:          types.MySQL,
+	}
+	engine.Dedicated = mysqlDedicated.engine
+	RegisterEngine(engine)
+}
+
+// engine returns the engine managing the databases of the server
+func (server *mysqlServer) engine(name string) *Engine {
+	return &Engine{
+		Name:           name,
+		Create:         server.create,
+		Delete:         server.delete,
+		Dump:           server.dump,
+		Restore:        server.restore,
+		RotatePassword: server.rotatePassword,
+		CreateUser:     server.createUser,
+		DeleteUser:     server.deleteUser,
+		Usage:          server.usage,
+		ApplyQuota:     server.applyQuota,
+		RestrictWrites: server.restrictWrites,
+		Metrics:        server.metrics,
+		Query:          server.query,
+	}
+}
+
+// create creates a database in the server with the given database name, user and password
+func (server *mysqlServer) create(db types.Database) error {
+	conn, err := server.connect()
 	if err != nil {
 		return fmt.Errorf("Error while creating the database : %s", err)
 	}
 	defer conn.Close()
 
 	if _, err = conn.Exec("CREATE DATABASE " + db.GetName()); err != nil {
-		return fmt.Errorf("Error while creating the database : Database Already Exists")
+		return ErrDatabaseExists
 	}
 
-	query := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED BY '%s'", db.GetUser(), mysqlHost, db.GetPassword())
+	query := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED BY '%s' WITH MAX_USER_CONNECTIONS %d",
+		db.GetUser(), mysqlHost, db.GetPassword(), db.GetQuota().Connections)
 	if _, err = conn.Exec(query); err != nil {
 		if err = refreshDBUser(db, conn); err != nil {
 			return fmt.Errorf("Error while creating the database : %s", err)
@@ -51,16 +119,13 @@ func CreateMysqlDB(db types.Database) error {
 	return nil
 }
 
-// DeleteMysqlDB deletes the database given by the database name and username
-func DeleteMysqlDB(databaseName string) error {
+// delete deletes the database given by the database name and username from the server
+func (server *mysqlServer) delete(databaseName string) error {
 	username := databaseName
 
-	agentAddress := fmt.Sprintf("tcp(127.0.0.1:%d)", mysqlPort)
-	connection := fmt.Sprintf("%s:%s@%s/", mysqlRootUser, mysqlRootPassword, agentAddress)
-
-	conn, err := sql.Open(mysqlDriver, connection)
+	conn, err := server.connect()
 	if err != nil {
-		return fmt.Errorf("Error while connecting to database : %s", err)
+		return err
 	}
 	defer conn.Close()
 
@@ -80,9 +145,95 @@ func refreshDBUser(db types.Database, conn *sql.DB) error {
 		return fmt.Errorf("Error while deleting the user : %s", err)
 	}
 
-	query := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED BY '%s'", db.GetUser(), mysqlHost, db.GetPassword())
+	query := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED BY '%s' WITH MAX_USER_CONNECTIONS %d",
+		db.GetUser(), mysqlHost, db.GetPassword(), db.GetQuota().Connections)
 	if _, err = conn.Exec(query); err != nil {
 		return fmt.Errorf("Error while creating the database : %s", err)
 	}
 	return nil
 }
+
+// mysqlPrivileges maps the roles of the additional users of a database to their privileges on it
+var mysqlPrivileges = map[string]string{
+	types.ReadOnlyRole:  "SELECT, SHOW VIEW",
+	types.ReadWriteRole: "SELECT, INSERT, UPDATE, DELETE, EXECUTE, SHOW VIEW, CREATE TEMPORARY TABLES, LOCK TABLES",
+}
+
+func (server *mysqlServer) connect() (*sql.DB, error) {
+	agentAddress := fmt.Sprintf("tcp(127.0.0.1:%d)", server.port)
+	connection := fmt.Sprintf("%s:%v@%s/", mysqlRootUser, server.rootPassword, agentAddress)
+	conn, err := sql.Open(mysqlDriver, connection)
+	if err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	return conn, nil
+}
+
+// ping checks whether the server accepts connections
+func (server *mysqlServer) ping() error {
+	conn, err := server.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Ping()
+}
+
+// rotatePassword changes the password of a user of a database in the server
+func (server *mysqlServer) rotatePassword(db types.Database, user, password string) error {
+	conn, err := server.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	query := fmt.Sprintf("ALTER USER '%s'@'%s' IDENTIFIED BY '%s'", user, mysqlHost, password)
+	if _, err = conn.Exec(query); err != nil {
+		return fmt.Errorf("Error while changing the password : %s", err)
+	}
+	return nil
+}
+
+// createUser creates an additional user of a database in the server with the privileges of its role
+func (server *mysqlServer) createUser(db types.Database, user *types.DatabaseUser) error {
+	privileges, ok := mysqlPrivileges[user.Role]
+	if !ok {
+		return fmt.Errorf("Invalid role %s", user.Role)
+	}
+	conn, err := server.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	query := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED BY '%s' WITH MAX_USER_CONNECTIONS %d",
+		user.GetUser(), mysqlHost, user.Password, db.GetQuota().Connections)
+	if _, err = conn.Exec(query); err != nil {
+		return fmt.Errorf("Error while creating the user : %s", err)
+	}
+
+	query = fmt.Sprintf("GRANT %s ON %s.* TO '%s'@'%s'", privileges, db.GetName(), user.GetUser(), mysqlHost)
+	if _, err = conn.Exec(query); err != nil {
+		conn.Exec(fmt.Sprintf("DROP USER IF EXISTS '%s'@'%s'", user.GetUser(), mysqlHost))
+		return fmt.Errorf("Error while granting privileges to the user : %s", err)
+	}
+
+	if _, err = conn.Exec("FLUSH PRIVILEGES"); err != nil {
+		return fmt.Errorf("Error while flushing user privileges : %s", err)
+	}
+	return nil
+}
+
+// deleteUser deletes an additional user of a database in the server
+func (server *mysqlServer) deleteUser(db types.Database, user *types.DatabaseUser) error {
+	conn, err := server.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Exec(fmt.Sprintf("DROP USER IF EXISTS '%s'@'%s'", user.GetUser(), mysqlHost)); err != nil {
+		return fmt.Errorf("Error while deleting the user : %s", err)
+	}
+	return nil
+}
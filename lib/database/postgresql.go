@@ -3,35 +3,110 @@ package database
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"github.com/jackc/pgx/v4" // PostgrerSQL driver
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/types"
 )
 
-var (
-	postgresqlHost         = `%`
-	postgresqlRootUser     = configs.ServiceConfig.DbMaker.PostgreSQL.Env["POSTGRES_USER"]
-	postgresqlPassword     = configs.ServiceConfig.DbMaker.PostgreSQL.Env["POSTGRES_PASSWORD"]
-	postgresqlDatabaseName = configs.ServiceConfig.DbMaker.PostgreSQL.Env["POSTGRES_DB"]
-	postgresqlPort         = configs.ServiceConfig.DbMaker.PostgreSQL.ContainerPort
-)
+var postgresqlHost = `%`
+
+// postgresqlDedicatedRootUser is the root user of the servers of dedicated databases
+const postgresqlDedicatedRootUser = "postgres"
+
+// postgresqlServer is a PostgreSQL server whose databases are managed by DbMaker
+type postgresqlServer struct {
+	// container is the name of the container of the server
+	container string
+	port      int
+	rootUser  interface{}
+	password  interface{}
+}
+
+var postgresqlDB = &postgresqlServer{
+	container: types.PostgreSQL,
+	port:      configs.ServiceConfig.DbMaker.PostgreSQL.ContainerPort,
+	rootUser:  configs.ServiceConfig.DbMaker.PostgreSQL.Env["POSTGRES_USER"],
+	password:  configs.ServiceConfig.DbMaker.PostgreSQL.Env["POSTGRES_PASSWORD"],
+}
 
-// CreatePostgresqlDB creates a postgre database
-func CreatePostgresqlDB(db types.Database) error {
+// postgresqlDedicated deploys the PostgreSQL servers of dedicated databases
+var postgresqlDedicated = &dedicatedServer{
+	name: types.PostgreSQL,
+	container: func(db types.Database) types.DatabaseContainer {
+		return types.DatabaseContainer{
+			Image:        dedicatedImage(configs.ImageConfig.Postgresql, db.GetVersion()),
+			DatabasePort: 5432,
+			Env: types.M{
+				"POSTGRES_USER":     postgresqlDedicatedRootUser,
+				"POSTGRES_PASSWORD": db.GetRootPassword(),
+			},
+			WorkDir: "/var/lib/postgresql/data",
+		}
+	},
+	bind: func(db types.Database) (*Engine, func() error) {
+		server := &postgresqlServer{
+			container: db.GetName(),
+			port:      db.GetContainerPort(),
+			rootUser:  postgresqlDedicatedRootUser,
+			password:  db.GetRootPassword(),
+		}
+		return server.engine(), server.ping
+	},
+}
+
+func init() {
+	engine := postgresqlDB.engine()
+	engine.Image = configs.ImageConfig.Postgresql
+	engine.Server = &types.DatabaseContainer{
+		Image:         configs.ImageConfig.Postgresql,
+		ContainerPort: postgresqlDB.port,
+		DatabasePort:  5432,
+		Env:           configs.ServiceConfig.DbMaker.PostgreSQL.Env,
+		WorkDir:       "/var/lib/postgresql/data",
+		StoreDir:      filepath.Join(storepath, "postgresql-storage"),
+		Name:          types.PostgreSQL,
+	}
+	engine.Dedicated = postgresqlDedicated.engine
+	RegisterEngine(engine)
+}
+
+// engine returns the engine managing the databases of the server
+func (server *postgresqlServer) engine() *Engine {
+	return &Engine{
+		Name:           types.PostgreSQL,
+		Create:         server.create,
+		Delete:         server.delete,
+		Dump:           server.dump,
+		Restore:        server.restore,
+		RotatePassword: server.rotatePassword,
+		CreateUser:     server.createUser,
+		DeleteUser:     server.deleteUser,
+		Usage:          server.usage,
+		ApplyQuota:     server.applyQuota,
+		RestrictWrites: server.restrictWrites,
+		Metrics:        server.metrics,
+		Query:          server.query,
+	}
+}
+
+// create creates a database in the server with the given database name, user and password
+func (server *postgresqlServer) create(db types.Database) error {
 	ctx := context.Background()
-	connection := fmt.Sprintf("postgres://%v:%v@localhost:%d/%v", postgresqlRootUser, postgresqlPassword, postgresqlPort, postgresqlRootUser)
+	connection := fmt.Sprintf("postgres://%v:%v@localhost:%d/%v", server.rootUser, server.password, server.port, server.rootUser)
 	conn, err := pgx.Connect(ctx, connection)
 	if err != nil {
 		return fmt.Errorf("Error while creating the database : %s", err)
 	}
 	defer conn.Close(ctx)
 
-	if _, err = conn.Exec(ctx, fmt.Sprintf("CREATE DATABASE %s", db.GetName())); err != nil {
-		return fmt.Errorf("Error while creating the database : Database Already Exists")
+	query := fmt.Sprintf("CREATE DATABASE %s CONNECTION LIMIT %d", db.GetName(), postgresqlConnectionLimit(db))
+	if _, err = conn.Exec(ctx, query); err != nil {
+		return ErrDatabaseExists
 	}
 
-	query := fmt.Sprintf("CREATE USER %s WITH PASSWORD '%s'", db.GetUser(), db.GetPassword())
+	query = fmt.Sprintf("CREATE USER %s WITH PASSWORD '%s'", db.GetUser(), db.GetPassword())
 	if _, err = conn.Exec(ctx, query); err != nil {
 		if err = refreshPostgresqlUser(db, conn); err != nil {
 			return fmt.Errorf("Error while creating the database : %s", err)
@@ -47,15 +122,32 @@ func CreatePostgresqlDB(db types.Database) error {
 	if _, err = conn.Exec(ctx, query); err != nil {
 		return fmt.Errorf("Error while revoking permissions : %s", err)
 	}
+
+	// Only the owner of the database creates objects in its public schema so that they can be handed
+	// over while the writes to the database are revoked
+	dbConn, err := server.connect(ctx, db.GetName())
+	if err != nil {
+		return err
+	}
+	defer dbConn.Close(ctx)
+	queries := []string{
+		"REVOKE CREATE ON SCHEMA public FROM PUBLIC",
+		fmt.Sprintf("GRANT CREATE ON SCHEMA public TO %s", db.GetUser()),
+	}
+	for _, query := range queries {
+		if _, err = dbConn.Exec(ctx, query); err != nil {
+			return fmt.Errorf("Error while granting permissions : %s", err)
+		}
+	}
 	return nil
 }
 
-// DeletePostgresqlDB deletes the database given by the database name and username
-func DeletePostgresqlDB(databaseName string) error {
+// delete deletes the database given by the database name and username from the server
+func (server *postgresqlServer) delete(databaseName string) error {
 	username := databaseName
 	ctx := context.Background()
 
-	connection := fmt.Sprintf("postgres://%v:%v@localhost:%d/%v", postgresqlRootUser, postgresqlPassword, postgresqlPort, postgresqlRootUser)
+	connection := fmt.Sprintf("postgres://%v:%v@localhost:%d/%v", server.rootUser, server.password, server.port, server.rootUser)
 	conn, err := pgx.Connect(ctx, connection)
 	if err != nil {
 		return fmt.Errorf("Error while creating the database : %s", err)
@@ -69,6 +161,10 @@ func DeletePostgresqlDB(databaseName string) error {
 	if _, err = conn.Exec(ctx, fmt.Sprintf("DROP USER IF EXISTS %s", username)); err != nil {
 		return fmt.Errorf("Error while deleting the user : %s", err)
 	}
+
+	if _, err = conn.Exec(ctx, fmt.Sprintf("DROP ROLE IF EXISTS %s", postgresqlFrozenRole(databaseName))); err != nil {
+		return fmt.Errorf("Error while deleting the user : %s", err)
+	}
 	return nil
 }
 
@@ -85,3 +181,120 @@ func refreshPostgresqlUser(db types.Database, conn *pgx.Conn) error {
 	}
 	return nil
 }
+
+// postgresqlPrivileges maps the roles of the additional users of a database to their privileges
+// on the tables and the sequences of its public schema
+var postgresqlPrivileges = map[string][2]string{
+	types.ReadOnlyRole:  {"SELECT", "SELECT"},
+	types.ReadWriteRole: {"SELECT, INSERT, UPDATE, DELETE", "USAGE, SELECT, UPDATE"},
+}
+
+// connect connects to a database of the server as the root user
+func (server *postgresqlServer) connect(ctx context.Context, databaseName string) (*pgx.Conn, error) {
+	connection := fmt.Sprintf("postgres://%v:%v@localhost:%d/%v", server.rootUser, server.password, server.port, databaseName)
+	conn, err := pgx.Connect(ctx, connection)
+	if err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	return conn, nil
+}
+
+// ping checks whether the server accepts connections
+func (server *postgresqlServer) ping() error {
+	ctx := context.Background()
+	conn, err := server.connect(ctx, fmt.Sprint(server.rootUser))
+	if err != nil {
+		return err
+	}
+	return conn.Close(ctx)
+}
+
+// rotatePassword changes the password of a user of a database of the server
+func (server *postgresqlServer) rotatePassword(db types.Database, user, password string) error {
+	ctx := context.Background()
+	conn, err := server.connect(ctx, fmt.Sprint(server.rootUser))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	if _, err = conn.Exec(ctx, fmt.Sprintf("ALTER USER %s WITH PASSWORD '%s'", user, password)); err != nil {
+		return fmt.Errorf("Error while changing the password : %s", err)
+	}
+	return nil
+}
+
+// createUser creates an additional user of a database of the server with the privileges of its role
+// The privileges also apply to the tables and the sequences created later by the owner of the database
+func (server *postgresqlServer) createUser(db types.Database, user *types.DatabaseUser) error {
+	privileges, ok := postgresqlPrivileges[user.Role]
+	if !ok {
+		return fmt.Errorf("Invalid role %s", user.Role)
+	}
+	ctx := context.Background()
+	conn, err := server.connect(ctx, fmt.Sprint(server.rootUser))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	if _, err = conn.Exec(ctx, fmt.Sprintf("CREATE USER %s WITH PASSWORD '%s'", user.GetUser(), user.Password)); err != nil {
+		return fmt.Errorf("Error while creating the user : %s", err)
+	}
+	if err = server.grantPrivileges(ctx, db, user, privileges); err != nil {
+		server.deleteUser(db, user)
+		return fmt.Errorf("Error while granting privileges to the user : %s", err)
+	}
+	return nil
+}
+
+func (server *postgresqlServer) grantPrivileges(ctx context.Context, db types.Database, user *types.DatabaseUser, privileges [2]string) error {
+	conn, err := server.connect(ctx, db.GetName())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	queries := []string{
+		fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", db.GetName(), user.GetUser()),
+		fmt.Sprintf("GRANT USAGE ON SCHEMA public TO %s", user.GetUser()),
+		fmt.Sprintf("GRANT %s ON ALL TABLES IN SCHEMA public TO %s", privileges[0], user.GetUser()),
+		fmt.Sprintf("GRANT %s ON ALL SEQUENCES IN SCHEMA public TO %s", privileges[1], user.GetUser()),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA public GRANT %s ON TABLES TO %s", db.GetUser(), privileges[0], user.GetUser()),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA public GRANT %s ON SEQUENCES TO %s", db.GetUser(), privileges[1], user.GetUser()),
+	}
+	for _, query := range queries {
+		if _, err := conn.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUser deletes an additional user of a database of the server
+// The objects it owns are handed over to the owner of the database before its privileges are dropped
+func (server *postgresqlServer) deleteUser(db types.Database, user *types.DatabaseUser) error {
+	ctx := context.Background()
+	conn, err := server.connect(ctx, db.GetName())
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(ctx, fmt.Sprintf("REASSIGN OWNED BY %s TO %s", user.GetUser(), db.GetUser()))
+	if err == nil {
+		_, err = conn.Exec(ctx, fmt.Sprintf("DROP OWNED BY %s", user.GetUser()))
+	}
+	conn.Close(ctx)
+	if err != nil {
+		return fmt.Errorf("Error while revoking the privileges of the user : %s", err)
+	}
+
+	if conn, err = server.connect(ctx, fmt.Sprint(server.rootUser)); err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	if _, err = conn.Exec(ctx, fmt.Sprintf("DROP USER IF EXISTS %s", user.GetUser())); err != nil {
+		return fmt.Errorf("Error while deleting the user : %s", err)
+	}
+	return nil
+}
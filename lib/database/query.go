@@ -0,0 +1,391 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v4"
+	"github.com/sdslabs/gasper/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The ad hoc queries of the query console are run with the credentials of the database, so that they
+// are bound by its privileges and quota like the queries of its clients
+// The timeout of a query is enforced by the server wherever it allows it, besides cancelling the query
+// from DbMaker, and the rows returned beyond the limit of the query are dropped
+
+// mongoReadCommands holds the MongoDB commands which can be run by the read-only queries
+var mongoReadCommands = map[string]bool{
+	"find":            true,
+	"aggregate":       true,
+	"count":           true,
+	"distinct":        true,
+	"listCollections": true,
+	"listIndexes":     true,
+	"dbStats":         true,
+	"collStats":       true,
+	"ping":            true,
+}
+
+// mongoCursorCommands holds the MongoDB commands returning their documents through a cursor
+var mongoCursorCommands = map[string]bool{
+	"find":            true,
+	"aggregate":       true,
+	"listCollections": true,
+	"listIndexes":     true,
+}
+
+// sqlValue converts a value scanned from a row into one which can be encoded as JSON
+func sqlValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case []byte:
+		return string(value)
+	case driver.Valuer:
+		if converted, err := value.Value(); err == nil {
+			return sqlValue(converted)
+		}
+	}
+	return value
+}
+
+// query runs an ad hoc SQL statement on a database of the server as the user of the database
+// The rows affected by a statement returning no rows are counted with ROW_COUNT()
+// The statement is killed once the timeout expires since the server keeps running it after the connection
+// is dropped by the client
+func (server *mysqlServer) query(db types.Database, query *types.DatabaseQuery) (*types.QueryResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), query.GetTimeout())
+	defer cancel()
+
+	config := mysql.NewConfig()
+	config.User = db.GetUser()
+	config.Passwd = db.GetPassword()
+	config.Net = "tcp"
+	config.Addr = fmt.Sprintf("127.0.0.1:%d", server.port)
+	config.DBName = db.GetName()
+	conn, err := sql.Open(mysqlDriver, config.FormatDSN())
+	if err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	defer conn.Close()
+
+	session, err := conn.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	defer session.Close()
+
+	var connectionID int64
+	if err := session.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&connectionID); err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				server.killQuery(connectionID)
+			}
+		case <-done:
+		}
+	}()
+
+	// MySQL and MariaDB name the limit on the execution time of the statements of a session differently
+	// and ignore the one of the other, MySQL only limits SELECT statements
+	session.ExecContext(ctx, fmt.Sprintf("SET SESSION MAX_EXECUTION_TIME = %d", query.GetTimeout().Milliseconds()))
+	session.ExecContext(ctx, fmt.Sprintf("SET SESSION max_statement_time = %d", query.Timeout))
+
+	tx, err := session.BeginTx(ctx, &sql.TxOptions{ReadOnly: query.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query.Query)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.QueryResult{}
+	if result.Columns, err = rows.Columns(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	for rows.Next() {
+		if len(result.Rows) == query.Limit {
+			result.Truncated = true
+			break
+		}
+		values := make([]interface{}, len(result.Columns))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		for i := range values {
+			values[i] = sqlValue(values[i])
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Columns) == 0 {
+		if err := tx.QueryRowContext(ctx, "SELECT ROW_COUNT()").Scan(&result.RowsAffected); err != nil {
+			return nil, err
+		}
+	}
+	return result, tx.Commit()
+}
+
+// killQuery kills the statement being run by a connection to the server
+func (server *mysqlServer) killQuery(connectionID int64) {
+	conn, err := server.connect()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	// The statement might have completed in the meantime
+	conn.Exec(fmt.Sprintf("KILL QUERY %d", connectionID))
+}
+
+// query runs an ad hoc SQL statement on a database of the server as the user of the database
+func (server *postgresqlServer) query(db types.Database, query *types.DatabaseQuery) (*types.QueryResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), query.GetTimeout())
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, fmt.Sprintf("host=localhost port=%d user=%s password='%s' dbname=%s",
+		server.port, db.GetUser(), db.GetPassword(), db.GetName()))
+	if err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	defer conn.Close(context.Background())
+
+	options := pgx.TxOptions{AccessMode: pgx.ReadWrite}
+	if query.ReadOnly {
+		options.AccessMode = pgx.ReadOnly
+	}
+	tx, err := conn.BeginTx(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", query.GetTimeout().Milliseconds())); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, query.Query)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.QueryResult{}
+	for _, field := range rows.FieldDescriptions() {
+		result.Columns = append(result.Columns, string(field.Name))
+	}
+	for rows.Next() {
+		if len(result.Rows) == query.Limit {
+			result.Truncated = true
+			break
+		}
+		values, err := rows.Values()
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		for i := range values {
+			values[i] = sqlValue(values[i])
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Columns) == 0 {
+		result.RowsAffected = rows.CommandTag().RowsAffected()
+	}
+	return result, tx.Commit(ctx)
+}
+
+// query runs an ad hoc command on a database of the server as the user of the database
+// The command is a document in extended JSON whose first field names the command, like
+// `{"find": "users", "filter": {"age": {"$gt": 18}}}`
+func (server *mongoServer) query(db types.Database, query *types.DatabaseQuery) (*types.QueryResult, error) {
+	command := bson.D{}
+	if err := bson.UnmarshalExtJSON([]byte(query.Query), false, &command); err != nil {
+		return nil, fmt.Errorf("Invalid command : %s", err)
+	}
+	if len(command) == 0 {
+		return nil, errors.New("Invalid command : the command is empty")
+	}
+	name := command[0].Key
+	if query.ReadOnly {
+		if !mongoReadCommands[name] {
+			return nil, fmt.Errorf("Command `%s` cannot be run by read-only queries", name)
+		}
+		for _, field := range command {
+			if field.Key != "pipeline" {
+				continue
+			}
+			// The results of an aggregation can be written to a collection by its last stage
+			output, _ := bson.Marshal(bson.D{field})
+			if strings.Contains(string(output), "$out") || strings.Contains(string(output), "$merge") {
+				return nil, errors.New("Aggregations writing to collections cannot be run by read-only queries")
+			}
+		}
+	}
+	command = append(command, bson.E{Key: "maxTimeMS", Value: query.GetTimeout().Milliseconds()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), query.GetTimeout())
+	defer cancel()
+
+	client, err := mongo.NewClient(options.Client().
+		ApplyURI(fmt.Sprintf("mongodb://127.0.0.1:%d", server.port)).
+		SetAuth(options.Credential{
+			Username:   db.GetUser(),
+			Password:   db.GetPassword(),
+			AuthSource: db.GetName(),
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	if err = client.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("Error while connecting to database : %s", err)
+	}
+	defer client.Disconnect(context.Background())
+	conn := client.Database(db.GetName())
+
+	result := &types.QueryResult{}
+	if !mongoCursorCommands[name] {
+		document, err := conn.RunCommand(ctx, command).DecodeBytes()
+		if err != nil {
+			return nil, err
+		}
+		output, err := bson.MarshalExtJSON(document, false, false)
+		if err != nil {
+			return nil, err
+		}
+		result.Documents = append(result.Documents, output)
+		return result, nil
+	}
+
+	cursor, err := conn.RunCommandCursor(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+	for cursor.Next(ctx) {
+		if len(result.Documents) == query.Limit {
+			result.Truncated = true
+			break
+		}
+		output, err := bson.MarshalExtJSON(cursor.Current, false, false)
+		if err != nil {
+			return nil, err
+		}
+		result.Documents = append(result.Documents, output)
+	}
+	return result, cursor.Err()
+}
+
+// splitRedisCommand splits a Redis command into its arguments like `redis-cli`, where the arguments
+// having whitespaces are enclosed in double quotes, with backslash escapes, or in single quotes
+func splitRedisCommand(command string) ([]interface{}, error) {
+	args := []interface{}{}
+	var arg strings.Builder
+	inArg, quote, escaped := false, rune(0), false
+	for _, char := range command {
+		switch {
+		case escaped:
+			switch char {
+			case 'n':
+				arg.WriteRune('\n')
+			case 't':
+				arg.WriteRune('\t')
+			default:
+				arg.WriteRune(char)
+			}
+			escaped = false
+		case quote == '"' && char == '\\':
+			escaped = true
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(char)
+		case char == '"' || char == '\'':
+			inArg, quote = true, char
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			inArg = true
+			arg.WriteRune(char)
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("Invalid command : unbalanced quotes")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("Invalid command : the command is empty")
+	}
+	return args, nil
+}
+
+// QueryRedisDB runs an ad hoc command on a Redis database as its default user
+// The read-only queries are limited to the commands flagged as read-only by the server
+func QueryRedisDB(db types.Database, query *types.DatabaseQuery) (*types.QueryResult, error) {
+	args, err := splitRedisCommand(query.Query)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:         fmt.Sprintf("127.0.0.1:%d", db.GetContainerPort()),
+		Password:     db.GetPassword(),
+		DialTimeout:  query.GetTimeout(),
+		ReadTimeout:  query.GetTimeout(),
+		WriteTimeout: query.GetTimeout(),
+		MaxRetries:   -1,
+	})
+	defer client.Close()
+
+	if query.ReadOnly {
+		name := strings.ToLower(args[0].(string))
+		commands, err := client.Command().Result()
+		if err != nil {
+			return nil, err
+		}
+		if info, ok := commands[name]; !ok || !info.ReadOnly {
+			return nil, fmt.Errorf("Command `%s` cannot be run by read-only queries", name)
+		}
+	}
+
+	reply, err := client.Do(args...).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	result := &types.QueryResult{Reply: reply}
+	if elements, ok := reply.([]interface{}); ok && len(elements) > query.Limit {
+		result.Reply = elements[:query.Limit]
+		result.Truncated = true
+	}
+	return result, nil
+}
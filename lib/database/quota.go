@@ -0,0 +1,482 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The size quota of a database is enforced by revoking the writes to it once it is exceeded, after which
+// its users can only read and, wherever the database server allows it, remove its contents
+// The writes are granted back once the database is within its quota again
+
+// mysqlRestrictedPrivileges maps the roles of the additional users of a database to the privileges left
+// to them while the database exceeds its size quota
+var mysqlRestrictedPrivileges = map[string]string{
+	types.ReadWriteRole: "SELECT, SHOW VIEW, DELETE",
+}
+
+// mysqlUserFilter matches the connections opened by the users of a MySQL database in its process list
+const mysqlUserFilter = "USER = ? OR USER LIKE CONCAT(?, '\\_%')"
+
+// usage returns the size of a database of the server and the number of connections opened by its users
+func (server *mysqlServer) usage(db types.Database) (*types.DatabaseUsage, error) {
+	conn, err := server.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	usage := &types.DatabaseUsage{}
+	row := conn.QueryRow(
+		"SELECT COALESCE(SUM(DATA_LENGTH + INDEX_LENGTH), 0) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?",
+		db.GetName())
+	if err := row.Scan(&usage.Size); err != nil {
+		return nil, fmt.Errorf("Error while fetching the size of the database : %s", err)
+	}
+	row = conn.QueryRow("SELECT COUNT(*) FROM information_schema.PROCESSLIST WHERE "+mysqlUserFilter, db.GetUser(), db.GetName())
+	if err := row.Scan(&usage.Connections); err != nil {
+		return nil, fmt.Errorf("Error while fetching the connections of the database : %s", err)
+	}
+	return usage, nil
+}
+
+// applyQuota limits the number of concurrent connections of each user of a database of the server
+// since MySQL does not limit the connections to a database
+func (server *mysqlServer) applyQuota(db types.Database, users []*types.DatabaseUser) error {
+	conn, err := server.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	names := []string{db.GetUser()}
+	for _, user := range users {
+		names = append(names, user.GetUser())
+	}
+	for _, name := range names {
+		query := fmt.Sprintf("ALTER USER '%s'@'%s' WITH MAX_USER_CONNECTIONS %d", name, mysqlHost, db.GetQuota().Connections)
+		if _, err := conn.Exec(query); err != nil {
+			return fmt.Errorf("Error while limiting the connections of user %s : %s", name, err)
+		}
+	}
+	return nil
+}
+
+// restrictWrites revokes or grants back the writes of the users of a database of the server
+// The open connections of the users are closed when the writes are revoked since the privileges
+// of a database are checked by MySQL only when it is selected
+func (server *mysqlServer) restrictWrites(db types.Database, users []*types.DatabaseUser, restricted bool) error {
+	conn, err := server.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	privileges := map[string]string{db.GetUser(): "ALL"}
+	if restricted {
+		privileges[db.GetUser()] = "SELECT, SHOW VIEW, DELETE, DROP"
+	}
+	for _, user := range users {
+		if user.IsReadOnly() {
+			continue
+		}
+		privileges[user.GetUser()] = mysqlPrivileges[user.Role]
+		if restricted {
+			privileges[user.GetUser()] = mysqlRestrictedPrivileges[user.Role]
+		}
+	}
+	for name, privilege := range privileges {
+		queries := []string{
+			fmt.Sprintf("REVOKE ALL ON %s.* FROM '%s'@'%s'", db.GetName(), name, mysqlHost),
+			fmt.Sprintf("GRANT %s ON %s.* TO '%s'@'%s'", privilege, db.GetName(), name, mysqlHost),
+		}
+		for _, query := range queries {
+			if _, err := conn.Exec(query); err != nil {
+				return fmt.Errorf("Error while changing the privileges of user %s : %s", name, err)
+			}
+		}
+	}
+	if _, err = conn.Exec("FLUSH PRIVILEGES"); err != nil {
+		return fmt.Errorf("Error while flushing user privileges : %s", err)
+	}
+	if restricted {
+		return killMysqlConnections(conn, db)
+	}
+	return nil
+}
+
+// killMysqlConnections closes the connections opened by the users of a MySQL database
+func killMysqlConnections(conn *sql.DB, db types.Database) error {
+	rows, err := conn.Query("SELECT ID FROM information_schema.PROCESSLIST WHERE "+mysqlUserFilter, db.GetUser(), db.GetName())
+	if err != nil {
+		return fmt.Errorf("Error while fetching the connections of the database : %s", err)
+	}
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		// The connection might have been closed in the meantime
+		conn.Exec(fmt.Sprintf("KILL %d", id))
+	}
+	return nil
+}
+
+// usage returns the size of a database of the server and the number of connections to it
+func (server *postgresqlServer) usage(db types.Database) (*types.DatabaseUsage, error) {
+	ctx := context.Background()
+	conn, err := server.connect(ctx, fmt.Sprint(server.rootUser))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	usage := &types.DatabaseUsage{}
+	if err := conn.QueryRow(ctx, "SELECT pg_database_size($1)", db.GetName()).Scan(&usage.Size); err != nil {
+		return nil, fmt.Errorf("Error while fetching the size of the database : %s", err)
+	}
+	var connections int64
+	if err := conn.QueryRow(ctx, "SELECT count(*) FROM pg_stat_activity WHERE datname = $1", db.GetName()).Scan(&connections); err != nil {
+		return nil, fmt.Errorf("Error while fetching the connections of the database : %s", err)
+	}
+	usage.Connections = int(connections)
+	return usage, nil
+}
+
+// postgresqlConnectionLimit returns the connection limit of a PostgreSQL database, -1 if it is not limited
+func postgresqlConnectionLimit(db types.Database) int {
+	if db.GetQuota().Connections == 0 {
+		return -1
+	}
+	return db.GetQuota().Connections
+}
+
+// applyQuota limits the number of concurrent connections to a database of the server
+func (server *postgresqlServer) applyQuota(db types.Database, users []*types.DatabaseUser) error {
+	ctx := context.Background()
+	conn, err := server.connect(ctx, fmt.Sprint(server.rootUser))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	query := fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d", db.GetName(), postgresqlConnectionLimit(db))
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("Error while limiting the connections of the database : %s", err)
+	}
+	return nil
+}
+
+// postgresqlFrozenRole returns the role owning the objects of a PostgreSQL database while its writes are
+// revoked, its name cannot be taken by the users of the database since their names are alphanumeric
+func postgresqlFrozenRole(databaseName string) string {
+	return databaseName + "__frozen"
+}
+
+// restrictWrites revokes or grants back the writes of the users of a database of the server
+// The objects of the database are handed over to a role without login while the writes are revoked
+// since their owners could grant the privileges back to themselves, and they are handed back to the
+// owner of the database afterwards
+// The transactions are also read-only by default and the open connections are closed so that the
+// restriction applies to all sessions
+func (server *postgresqlServer) restrictWrites(db types.Database, users []*types.DatabaseUser, restricted bool) error {
+	ctx := context.Background()
+	conn, err := server.connect(ctx, fmt.Sprint(server.rootUser))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	frozenRole := postgresqlFrozenRole(db.GetName())
+	var frozen bool
+	if err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = $1)", frozenRole).Scan(&frozen); err != nil {
+		return fmt.Errorf("Error while fetching the roles of the database : %s", err)
+	}
+	queries := []string{
+		fmt.Sprintf("ALTER DATABASE %s RESET default_transaction_read_only", db.GetName()),
+		fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE %s TO %s", db.GetName(), db.GetUser()),
+	}
+	if restricted {
+		queries = []string{
+			fmt.Sprintf("ALTER DATABASE %s SET default_transaction_read_only = on", db.GetName()),
+			fmt.Sprintf("REVOKE CREATE ON DATABASE %s FROM %s", db.GetName(), db.GetUser()),
+		}
+		if !frozen {
+			queries = append(queries, fmt.Sprintf("CREATE ROLE %s NOLOGIN", frozenRole))
+		}
+	}
+	for _, query := range queries {
+		if _, err := conn.Exec(ctx, query); err != nil {
+			return fmt.Errorf("Error while changing the writes of the database : %s", err)
+		}
+	}
+
+	if restricted {
+		err = server.freezeObjects(ctx, db, users, frozenRole)
+	} else {
+		err = server.thawObjects(ctx, db, users, frozenRole, frozen)
+	}
+	if err != nil {
+		return fmt.Errorf("Error while changing the writes of the database : %s", err)
+	}
+	if !restricted && frozen {
+		if _, err := conn.Exec(ctx, fmt.Sprintf("DROP ROLE IF EXISTS %s", frozenRole)); err != nil {
+			return fmt.Errorf("Error while changing the writes of the database : %s", err)
+		}
+	}
+
+	_, err = conn.Exec(ctx,
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = $1 AND pid <> pg_backend_pid()",
+		db.GetName())
+	if err != nil {
+		return fmt.Errorf("Error while closing the connections of the database : %s", err)
+	}
+	return nil
+}
+
+// freezeObjects hands the objects of a database of the server over to the frozen role, after which the owner
+// of the database can only read, delete and truncate its tables and the other users can no longer modify them
+func (server *postgresqlServer) freezeObjects(ctx context.Context, db types.Database, users []*types.DatabaseUser, frozenRole string) error {
+	conn, err := server.connect(ctx, db.GetName())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	queries := []string{
+		fmt.Sprintf("REASSIGN OWNED BY %s TO %s", db.GetUser(), frozenRole),
+		"REVOKE CREATE ON SCHEMA public FROM PUBLIC",
+		fmt.Sprintf("REVOKE CREATE ON SCHEMA public FROM %s", db.GetUser()),
+	}
+	for _, user := range users {
+		if user.IsReadOnly() {
+			continue
+		}
+		queries = append(queries,
+			fmt.Sprintf("REASSIGN OWNED BY %s TO %s", user.GetUser(), frozenRole),
+			fmt.Sprintf("REVOKE INSERT, UPDATE ON ALL TABLES IN SCHEMA public FROM %s", user.GetUser()),
+			fmt.Sprintf("REVOKE USAGE, UPDATE ON ALL SEQUENCES IN SCHEMA public FROM %s", user.GetUser()),
+		)
+	}
+	for _, query := range queries {
+		if _, err := conn.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	// The owner keeps reading the schemas it created along with the public schema
+	schemas := []string{"public"}
+	rows, err := conn.Query(ctx,
+		"SELECT nspname FROM pg_namespace WHERE nspowner = (SELECT oid FROM pg_roles WHERE rolname = $1)", frozenRole)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			rows.Close()
+			return err
+		}
+		schemas = append(schemas, schema)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, schema := range schemas {
+		queries := []string{
+			fmt.Sprintf("GRANT USAGE ON SCHEMA \"%s\" TO %s", schema, db.GetUser()),
+			fmt.Sprintf("GRANT SELECT, DELETE, TRUNCATE ON ALL TABLES IN SCHEMA \"%s\" TO %s", schema, db.GetUser()),
+			fmt.Sprintf("GRANT SELECT ON ALL SEQUENCES IN SCHEMA \"%s\" TO %s", schema, db.GetUser()),
+		}
+		for _, query := range queries {
+			if _, err := conn.Exec(ctx, query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// thawObjects hands the objects of a database of the server held by the frozen role back to the owner of the
+// database and grants the writes back to the other users
+func (server *postgresqlServer) thawObjects(ctx context.Context, db types.Database, users []*types.DatabaseUser, frozenRole string, frozen bool) error {
+	conn, err := server.connect(ctx, db.GetName())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	queries := []string{fmt.Sprintf("GRANT CREATE ON SCHEMA public TO %s", db.GetUser())}
+	if frozen {
+		queries = append(queries,
+			fmt.Sprintf("REASSIGN OWNED BY %s TO %s", frozenRole, db.GetUser()),
+			fmt.Sprintf("DROP OWNED BY %s", frozenRole),
+		)
+	}
+	for _, user := range users {
+		privileges, ok := postgresqlPrivileges[user.Role]
+		if !ok || user.IsReadOnly() {
+			continue
+		}
+		queries = append(queries,
+			fmt.Sprintf("GRANT %s ON ALL TABLES IN SCHEMA public TO %s", privileges[0], user.GetUser()),
+			fmt.Sprintf("GRANT %s ON ALL SEQUENCES IN SCHEMA public TO %s", privileges[1], user.GetUser()),
+		)
+	}
+	for _, query := range queries {
+		if _, err := conn.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usage returns the size of a database of the server on disk and the number of connections opened by its users
+func (server *mongoServer) usage(db types.Database) (*types.DatabaseUsage, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect(ctx)
+
+	stats := bson.M{}
+	err = client.Database(db.GetName()).RunCommand(ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&stats)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the size of the database : %s", err)
+	}
+
+	// The users of a database are authenticated against it, and the idle connections are reported too
+	cursor, err := client.Database("admin").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$currentOp", Value: bson.D{{Key: "allUsers", Value: true}, {Key: "idleConnections", Value: true}}}},
+		{{Key: "$match", Value: bson.D{{Key: "effectiveUsers.db", Value: db.GetName()}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$connectionId"}}}},
+		{{Key: "$count", Value: "connections"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the connections of the database : %s", err)
+	}
+	defer cursor.Close(ctx)
+	count := bson.M{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&count); err != nil {
+			return nil, fmt.Errorf("Error while fetching the connections of the database : %s", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("Error while fetching the connections of the database : %s", err)
+	}
+
+	return &types.DatabaseUsage{
+		Size:        bsonInt(stats["storageSize"]) + bsonInt(stats["indexSize"]),
+		Connections: int(bsonInt(count["connections"])),
+	}, nil
+}
+
+// bsonInt converts a number decoded from BSON into an integer
+func bsonInt(value interface{}) int64 {
+	switch number := value.(type) {
+	case int32:
+		return int64(number)
+	case int64:
+		return number
+	case float64:
+		return int64(number)
+	}
+	return 0
+}
+
+// restrictWrites replaces the roles of the users of a database of the server which can modify it with
+// the read role, or grants their roles back
+func (server *mongoServer) restrictWrites(db types.Database, users []*types.DatabaseUser, restricted bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := server.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	conn := client.Database(db.GetName())
+
+	roles := map[string]bson.A{db.GetUser(): {"dbOwner", "readWrite"}}
+	for _, user := range users {
+		if !user.IsReadOnly() {
+			roles[user.GetUser()] = bson.A{mongoRoles[user.Role]}
+		}
+	}
+	for name, writeRoles := range roles {
+		revoked, granted := bson.A{"read"}, writeRoles
+		if restricted {
+			revoked, granted = writeRoles, bson.A{"read"}
+		}
+		if err := exec(ctx, conn, bson.D{{Key: "grantRolesToUser", Value: name}, {Key: "roles", Value: granted}}); err != nil {
+			return fmt.Errorf("Error while changing the roles of user %s : %s", name, err)
+		}
+		if err := exec(ctx, conn, bson.D{{Key: "revokeRolesFromUser", Value: name}, {Key: "roles", Value: revoked}}); err != nil {
+			return fmt.Errorf("Error while changing the roles of user %s : %s", name, err)
+		}
+	}
+	return nil
+}
+
+// RedisUsage returns the memory used by the dataset and the container of a Redis database along with
+// the number of clients connected to it
+func RedisUsage(db types.Database) (*types.DatabaseUsage, error) {
+	info, err := redisInfo(db)
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the usage of the database : %s", err)
+	}
+	usage := &types.DatabaseUsage{}
+	usage.Size, _ = strconv.ParseInt(info["used_memory"], 10, 64)
+	usage.Connections, _ = strconv.Atoi(info["connected_clients"])
+
+	stats, err := docker.ContainerStats(db.GetName())
+	if err != nil {
+		return nil, fmt.Errorf("Error while fetching the statistics of the container : %s", err)
+	}
+	usage.Memory = int64(stats.Memory.Usage)
+	return usage, nil
+}
+
+// redisInfo returns the fields of the information and statistics of the server of a Redis database
+func redisInfo(db types.Database) (map[string]string, error) {
+	output, err := docker.ExecProcessWthStream(db.GetName(), []string{"sh", "-c",
+		fmt.Sprintf("REDISCLI_AUTH=%s redis-cli INFO", shellQuote(db.GetPassword()))})
+	if err != nil {
+		return nil, err
+	}
+	info := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(fields) == 2 {
+			info[fields[0]] = fields[1]
+		}
+	}
+	return info, nil
+}
+
+// ApplyRedisQuota recreates the container of a Redis database with the limits of its quota
+// The dataset is saved by the server when the container is stopped and loaded again on startup
+func ApplyRedisQuota(db types.Database, users []*types.DatabaseUser) error {
+	return recreateDatabaseContainer(db, redisContainer(db))
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// rabbitmqVhost is the virtual host of a RabbitMQ database holding all its exchanges and queues
+	rabbitmqVhost = "/"
+
+	// rabbitmqStartupTimeout is the maximum number of seconds waited for the server of a RabbitMQ database to boot
+	// It is the same as the wait for dedicated databases which the deadline of the calls creating databases covers
+	rabbitmqStartupTimeout = dedicatedStartupTimeout
+)
+
+// rabbitmqPermissions maps the roles of the additional users of a database to the
+// configure, write and read permissions of RabbitMQ on its resources
+// The read permission lets a read-only user consume the messages of the queues as RabbitMQ
+// cannot read messages without consuming them
+var rabbitmqPermissions = map[string][]string{
+	types.ReadOnlyRole:  {"^$", "^$", ".*"},
+	types.ReadWriteRole: {".*", ".*", ".*"},
+}
+
+func init() {
+	RegisterEngine(&Engine{
+		Name:           types.RabbitMQ,
+		Image:          configs.ImageConfig.Rabbitmq,
+		Create:         CreateRabbitMQDB,
+		Delete:         DeleteRabbitMQDB,
+		RotatePassword: RotateRabbitMQPassword,
+		CreateUser:     CreateRabbitMQUser,
+		DeleteUser:     DeleteRabbitMQUser,
+		Usage:          RabbitMQUsage,
+		ApplyQuota:     ApplyRabbitMQQuota,
+		Metrics:        RabbitMQMetrics,
+	})
+}
+
+// rabbitmqContainer returns the container of a RabbitMQ database
+// The hostname of the container is fixed since the server stores its data in a directory named after it
+func rabbitmqContainer(db types.Database) types.DatabaseContainer {
+	return types.DatabaseContainer{
+		Image:        configs.ImageConfig.Rabbitmq,
+		DatabasePort: 5672,
+		WorkDir:      "/var/lib/rabbitmq",
+		StoreDir:     databaseStoreDir(types.RabbitMQ, db.GetName()),
+		Hostname:     db.GetName(),
+		Env: types.M{
+			"RABBITMQ_DEFAULT_USER": db.GetUser(),
+			"RABBITMQ_DEFAULT_PASS": db.GetPassword(),
+		},
+	}
+}
+
+// rabbitmqctl executes a command of rabbitmqctl in the container of a RabbitMQ database
+func rabbitmqctl(db types.Database, args ...string) (string, error) {
+	output, err := docker.ExecProcessWthStream(db.GetName(), append([]string{"rabbitmqctl", "--quiet"}, args...))
+	if err != nil {
+		return "", fmt.Errorf("Error while executing rabbitmqctl %s : %s", args[0], err)
+	}
+	return output, nil
+}
+
+// checkRabbitMQPassword checks whether a password can be passed to rabbitmqctl
+// which parses the arguments starting with a dash as options
+func checkRabbitMQPassword(password string) error {
+	if strings.HasPrefix(password, "-") {
+		return fmt.Errorf("Password of a RabbitMQ user cannot start with -")
+	}
+	return nil
+}
+
+// rabbitmqList returns the rows listed by a command of rabbitmqctl
+func rabbitmqList(db types.Database, args ...string) ([]string, error) {
+	output, err := rabbitmqctl(db, append(args, "--no-table-headers")...)
+	if err != nil {
+		return nil, err
+	}
+	rows := []string{}
+	for _, row := range strings.Split(output, "\n") {
+		if row = strings.TrimSpace(row); row != "" {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// startRabbitMQ starts the container of a RabbitMQ database and waits for its server to boot
+// The number of connections to the database is limited once the server is up
+func startRabbitMQ(db types.Database, start func(types.Database, types.DatabaseContainer) error) error {
+	if err := start(db, rabbitmqContainer(db)); err != nil {
+		return err
+	}
+	// Every attempt is bounded as well so that the whole wait stays within the startup timeout
+	_, err := docker.ExecProcessWthStream(db.GetName(), []string{"sh", "-c", fmt.Sprintf(
+		"end=$(($(date +%%s)+%d)); until rabbitmqctl --quiet --timeout 5 await_startup > /dev/null 2>&1; do [ $(date +%%s) -ge $end ] && exit 1; sleep 1; done",
+		rabbitmqStartupTimeout)})
+	if err != nil {
+		return fmt.Errorf("Error while waiting for the server to start : %s", err)
+	}
+
+	limits := "{}"
+	if connections := db.GetQuota().Connections; connections > 0 {
+		limits = fmt.Sprintf(`{"max-connections": %d}`, connections)
+	}
+	_, err = rabbitmqctl(db, "set_vhost_limits", "-p", rabbitmqVhost, limits)
+	return err
+}
+
+// CreateRabbitMQDB creates a RabbitMQ container whose default user is the user of the database
+func CreateRabbitMQDB(db types.Database) error {
+	if _, err := allocateDatabaseContainer(types.RabbitMQ, db); err != nil {
+		return err
+	}
+	return startRabbitMQ(db, startDatabaseContainer)
+}
+
+// DeleteRabbitMQDB deletes a RabbitMQ container
+func DeleteRabbitMQDB(databaseName string) error {
+	return deleteDatabaseContainer(types.RabbitMQ, databaseName)
+}
+
+// RotateRabbitMQPassword changes the password of a user of a RabbitMQ database
+func RotateRabbitMQPassword(db types.Database, user, password string) error {
+	if err := checkRabbitMQPassword(password); err != nil {
+		return err
+	}
+	_, err := rabbitmqctl(db, "change_password", user, password)
+	return err
+}
+
+// CreateRabbitMQUser creates an additional user of a RabbitMQ database with the permissions of its role
+func CreateRabbitMQUser(db types.Database, user *types.DatabaseUser) error {
+	permissions, ok := rabbitmqPermissions[user.Role]
+	if !ok {
+		return fmt.Errorf("Invalid role %s", user.Role)
+	}
+	if err := checkRabbitMQPassword(user.Password); err != nil {
+		return err
+	}
+	if _, err := rabbitmqctl(db, "add_user", user.GetUser(), user.Password); err != nil {
+		return err
+	}
+	args := append([]string{"set_permissions", "-p", rabbitmqVhost, user.GetUser()}, permissions...)
+	if _, err := rabbitmqctl(db, args...); err != nil {
+		rabbitmqctl(db, "delete_user", user.GetUser())
+		return err
+	}
+	return nil
+}
+
+// DeleteRabbitMQUser deletes an additional user of a RabbitMQ database
+func DeleteRabbitMQUser(db types.Database, user *types.DatabaseUser) error {
+	_, err := rabbitmqctl(db, "delete_user", user.GetUser())
+	return err
+}
+
+// RabbitMQUsage returns the size of the data stored by a RabbitMQ database along with the
+// number of connections to it and the memory used by its container
+func RabbitMQUsage(db types.Database) (*types.DatabaseUsage, error) {
+	usage, err := containerUsage(types.RabbitMQ, db)
+	if err != nil {
+		return nil, err
+	}
+	connections, err := rabbitmqList(db, "list_connections", "name")
+	if err != nil {
+		return nil, err
+	}
+	usage.Connections = len(connections)
+	return usage, nil
+}
+
+// ApplyRabbitMQQuota recreates the container of a RabbitMQ database with the limits of its quota
+func ApplyRabbitMQQuota(db types.Database, users []*types.DatabaseUser) error {
+	return startRabbitMQ(db, recreateDatabaseContainer)
+}
+
+// RabbitMQMetrics returns the metrics of a RabbitMQ database where the tables are its queues
+// The queries are not tracked by the server
+func RabbitMQMetrics(db types.Database) (*types.DatabaseMetrics, error) {
+	usage, err := RabbitMQUsage(db)
+	if err != nil {
+		return nil, err
+	}
+	queues, err := rabbitmqList(db, "list_queues", "-p", rabbitmqVhost, "name")
+	if err != nil {
+		return nil, err
+	}
+	return &types.DatabaseMetrics{
+		Size:        usage.Size,
+		Tables:      int64(len(queues)),
+		Connections: int64(usage.Connections),
+	}, nil
+}
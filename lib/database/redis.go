@@ -1,64 +1,113 @@
 package database
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
-
-	"github.com/sdslabs/gasper/lib/utils"
+	"strings"
 
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/docker"
 	"github.com/sdslabs/gasper/types"
 )
 
-// CreateRedisDB  creates a RedisDB container
-func CreateRedisDB(db types.Database) error {
-	port, err := utils.GetFreePort()
-	if err != nil {
-		return fmt.Errorf("Error while getting free port for container : %s", err)
-	}
+const (
+	// redisACLFile is the name of the file holding the users of a Redis database in its directory
+	redisACLFile = "users.acl"
 
-	storedir := filepath.Join(storepath, "redis-storage", db.GetName())
+	// redisDefaultUser is the user of a Redis database corresponding to the user of the database
+	redisDefaultUser = "default"
+)
 
-	if err := os.MkdirAll(storedir, 0755); err != nil {
-		return fmt.Errorf("Error while creating the directory : %s", err)
-	}
+// redisRoles maps the roles of the additional users of a database to the ACL rules of Redis
+var redisRoles = map[string][]string{
+	types.ReadOnlyRole:  {"~*", "+@read", "+@connection", "-@dangerous"},
+	types.ReadWriteRole: {"~*", "+@all", "-@admin", "-@dangerous"},
+}
 
-	containerID, err := docker.CreateDatabaseContainer(types.DatabaseContainer{
-		Image:         configs.ImageConfig.Redis,
-		ContainerPort: port,
-		DatabasePort:  6379,
-		WorkDir:       "/data/",
-		StoreDir:      filepath.Join(storepath, "redis-storage", db.GetName()),
-		Name:          db.GetName(),
-		Cmd:           []string{"redis-server", "--logfile", "/data/redis-server.log", "--requirepass", db.GetPassword()},
+// redisUser is a user in the ACL file of a Redis database
+type redisUser struct {
+	name  string
+	rules []string
+}
+
+func init() {
+	RegisterEngine(&Engine{
+		Name:           types.Redis,
+		Image:          configs.ImageConfig.Redis,
+		Create:         CreateRedisDB,
+		Delete:         DeleteRedisDB,
+		Dump:           DumpRedisDB,
+		Restore:        RestoreRedisDB,
+		RotatePassword: RotateRedisPassword,
+		CreateUser:     CreateRedisUser,
+		DeleteUser:     DeleteRedisUser,
+		Usage:          RedisUsage,
+		ApplyQuota:     ApplyRedisQuota,
+		Metrics:        RedisMetrics,
+		Query:          QueryRedisDB,
 	})
+}
+
+func redisStoreDir(databaseName string) string {
+	return databaseStoreDir(types.Redis, databaseName)
+}
+
+// redisPassword returns the ACL rule setting the password of a user
+// The password is stored as its SHA-256 hash so that it is not kept in plain text
+func redisPassword(password string) string {
+	hash := sha256.Sum256([]byte(password))
+	return "#" + hex.EncodeToString(hash[:])
+}
+
+// redisContainer returns the container of a Redis database whose users are loaded from its ACL file
+// Writes are rejected by the server once the dataset exceeds the size quota, which cannot be lifted by the
+// users since the CONFIG command is disabled
+func redisContainer(db types.Database) types.DatabaseContainer {
+	quota := db.GetQuota()
+	cmd := []string{"redis-server", "--logfile", "/data/redis-server.log", "--aclfile", "/data/" + redisACLFile,
+		"--rename-command", "CONFIG", ""}
+	if quota.Size > 0 {
+		cmd = append(cmd, "--maxmemory", fmt.Sprintf("%dmb", quota.Size), "--maxmemory-policy", "noeviction")
+	}
+	if quota.Connections > 0 {
+		cmd = append(cmd, "--maxclients", fmt.Sprint(quota.Connections))
+	}
+
+	return types.DatabaseContainer{
+		Image:        configs.ImageConfig.Redis,
+		DatabasePort: 6379,
+		WorkDir:      "/data/",
+		StoreDir:     redisStoreDir(db.GetName()),
+		Cmd:          cmd,
+	}
+}
 
+// CreateRedisDB  creates a RedisDB container
+func CreateRedisDB(db types.Database) error {
+	storedir, err := allocateDatabaseContainer(types.Redis, db)
 	if err != nil {
-		return types.NewResErr(500, "container not created", err)
+		return err
 	}
 
-	if err := docker.StartContainer(containerID); err != nil {
-		return types.NewResErr(500, "container not started", err)
+	err = writeRedisACL(filepath.Join(storedir, redisACLFile), []*redisUser{{
+		name:  redisDefaultUser,
+		rules: []string{"on", redisPassword(db.GetPassword()), "~*", "+@all"},
+	}})
+	if err != nil {
+		return fmt.Errorf("Error while writing the users of the database : %s", err)
 	}
 
-	db.SetContainerPort(port)
-	return nil
+	return startDatabaseContainer(db, redisContainer(db))
 }
 
 // DeleteRedisDB deletes RedisDB container
 func DeleteRedisDB(databaseName string) error {
-	if err := docker.DeleteContainer(databaseName); err != nil {
-		return types.NewResErr(500, "container not deleted", err)
-	}
-
-	storedir := filepath.Join(storepath, "redis-storage", databaseName)
-
-	if err := os.RemoveAll(storedir); err != nil {
-		return fmt.Errorf("Error while deleting the database directory : %s", err)
-	}
-	return nil
+	return deleteDatabaseContainer(types.Redis, databaseName)
 }
 
 // GetLogs returns logs of a RedisDB container
@@ -69,3 +118,140 @@ func GetLogs(databaseName string) (string, error) {
 	}
 	return logs, nil
 }
+
+func readRedisACL(path string) ([]*redisUser, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	users := []*redisUser{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "user" {
+			continue
+		}
+		users = append(users, &redisUser{name: fields[1], rules: fields[2:]})
+	}
+	return users, nil
+}
+
+// writeRedisACL replaces the ACL file of a Redis database with the given users
+// The file is readable by everyone since the server does not run as root
+func writeRedisACL(path string, users []*redisUser) error {
+	var acl strings.Builder
+	for _, user := range users {
+		fmt.Fprintf(&acl, "user %s %s\n", user.name, strings.Join(user.rules, " "))
+	}
+	temp := path + ".tmp"
+	if err := ioutil.WriteFile(temp, []byte(acl.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
+
+// reloadRedisACL makes the server of a Redis database load the users from its ACL file
+func reloadRedisACL(db types.Database) error {
+	output, err := docker.ExecProcessWthStream(db.GetName(), []string{"sh", "-c",
+		fmt.Sprintf("REDISCLI_AUTH=%s redis-cli ACL LOAD", shellQuote(db.GetPassword()))})
+	if err == nil && strings.TrimSpace(output) != "OK" {
+		err = errors.New(strings.TrimSpace(output))
+	}
+	return err
+}
+
+// updateRedisACL applies a change to the users of a Redis database and reloads them
+// The ACL file of databases created before additional users were supported is built from the password
+// of the database, and their container is recreated since their server was started without the file
+func updateRedisACL(db types.Database, update func([]*redisUser) ([]*redisUser, error)) error {
+	path := filepath.Join(redisStoreDir(db.GetName()), redisACLFile)
+	previous, err := ioutil.ReadFile(path)
+	legacy := os.IsNotExist(err)
+	if err != nil && !legacy {
+		return err
+	}
+
+	users := []*redisUser{{
+		name:  redisDefaultUser,
+		rules: []string{"on", redisPassword(db.GetPassword()), "~*", "+@all"},
+	}}
+	if !legacy {
+		if users, err = readRedisACL(path); err != nil {
+			return err
+		}
+	}
+	if users, err = update(users); err != nil {
+		return err
+	}
+	if err := writeRedisACL(path, users); err != nil {
+		return fmt.Errorf("Error while writing the users of the database : %s", err)
+	}
+
+	if legacy {
+		if err := recreateDatabaseContainer(db, redisContainer(db)); err != nil {
+			os.Remove(path)
+			return err
+		}
+		return nil
+	}
+	if err := reloadRedisACL(db); err != nil {
+		ioutil.WriteFile(path, previous, 0644)
+		return fmt.Errorf("Error while loading the users of the database : %s", err)
+	}
+	return nil
+}
+
+// RotateRedisPassword changes the password of a user of a Redis database
+func RotateRedisPassword(db types.Database, user, password string) error {
+	if user == db.GetUser() {
+		user = redisDefaultUser
+	}
+	return updateRedisACL(db, func(users []*redisUser) ([]*redisUser, error) {
+		for _, current := range users {
+			if current.name != user {
+				continue
+			}
+			rules := []string{}
+			for _, rule := range current.rules {
+				// Drop the current passwords of the user whether they are in plain text or hashed
+				if !strings.HasPrefix(rule, ">") && !strings.HasPrefix(rule, "#") && rule != "nopass" {
+					rules = append(rules, rule)
+				}
+			}
+			current.rules = append(rules, redisPassword(password))
+			return users, nil
+		}
+		return nil, fmt.Errorf("User %s does not exist", user)
+	})
+}
+
+// CreateRedisUser creates an additional user of a Redis database with the ACL rules of its role
+func CreateRedisUser(db types.Database, user *types.DatabaseUser) error {
+	rules, ok := redisRoles[user.Role]
+	if !ok {
+		return fmt.Errorf("Invalid role %s", user.Role)
+	}
+	return updateRedisACL(db, func(users []*redisUser) ([]*redisUser, error) {
+		for _, current := range users {
+			if current.name == user.GetUser() {
+				return nil, fmt.Errorf("User %s already exists", user.Username)
+			}
+		}
+		return append(users, &redisUser{
+			name:  user.GetUser(),
+			rules: append([]string{"on", redisPassword(user.Password)}, rules...),
+		}), nil
+	})
+}
+
+// DeleteRedisUser deletes an additional user of a Redis database
+func DeleteRedisUser(db types.Database, user *types.DatabaseUser) error {
+	return updateRedisACL(db, func(users []*redisUser) ([]*redisUser, error) {
+		remaining := []*redisUser{}
+		for _, current := range users {
+			if current.name != user.GetUser() {
+				remaining = append(remaining, current)
+			}
+		}
+		return remaining, nil
+	})
+}
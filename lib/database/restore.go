@@ -0,0 +1,171 @@
+package database
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/types"
+)
+
+// decompressBackup decompresses a backup into the writer and verifies its SHA-256 checksum
+// The checksum is not verified if it is empty
+func decompressBackup(source io.Reader, checksum string, writer io.Writer) error {
+	hash := sha256.New()
+	decompressor, err := gzip.NewReader(io.TeeReader(source, hash))
+	if err != nil {
+		return fmt.Errorf("Error while reading the backup : %s", err)
+	}
+	defer decompressor.Close()
+
+	if _, err := io.Copy(writer, decompressor); err != nil {
+		return fmt.Errorf("Error while reading the backup : %s", err)
+	}
+	// Drain the trailing bytes of the backup, if any, so that all of it is hashed
+	if _, err := io.Copy(ioutil.Discard, source); err != nil {
+		return err
+	}
+	if checksum != "" && hex.EncodeToString(hash.Sum(nil)) != checksum {
+		return fmt.Errorf("Checksum of the backup does not match, it might be corrupted")
+	}
+	return nil
+}
+
+// ImportDump decompresses a backup and copies the dump inside the container of a database server
+// The dump must be removed from the container once it is restored
+func ImportDump(source io.Reader, backup *types.Backup, container string) (*Dump, error) {
+	temp, err := ioutil.TempFile("", "gasper-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		temp.Close()
+		os.Remove(temp.Name())
+	}()
+
+	if err := decompressBackup(source, backup.Checksum, temp); err != nil {
+		return nil, err
+	}
+	info, err := temp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := temp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	dump := &Dump{
+		Container: container,
+		Path:      fmt.Sprintf("%s/gasper-restore-%s.%s", dumpDir, backup.ID, backup.Format),
+		Format:    backup.Format,
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		archive := tar.NewWriter(writer)
+		err := archive.WriteHeader(&tar.Header{
+			Name: filepath.Base(dump.Path),
+			Mode: 0644,
+			Size: info.Size(),
+		})
+		if err == nil {
+			_, err = io.Copy(archive, temp)
+		}
+		if err == nil {
+			err = archive.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	if err := docker.CopyToContainer(container, dumpDir, reader); err != nil {
+		reader.CloseWithError(err)
+		return nil, fmt.Errorf("Error while copying the dump : %s", err)
+	}
+	return dump, nil
+}
+
+// restore executes the shell command restoring the dump inside its container and removes the dump
+func (dump *Dump) restore(command string) error {
+	defer dump.remove()
+	if err := dump.exec(command); err != nil {
+		return fmt.Errorf("Error while restoring the database : %s", err)
+	}
+	return nil
+}
+
+// restore replaces the contents of a database of the server with a backup
+func (server *mysqlServer) restore(db types.Database, backup *types.Backup, source io.Reader) error {
+	dump, err := ImportDump(source, backup, server.container)
+	if err != nil {
+		return err
+	}
+	recreate := fmt.Sprintf("DROP DATABASE IF EXISTS `%s`; CREATE DATABASE `%s`", db.GetName(), db.GetName())
+	return dump.restore(fmt.Sprintf(
+		"export MYSQL_PWD=%s; mysql -u %s -e %s && mysql -u %s %s < %s",
+		shellQuote(server.rootPassword), mysqlRootUser, shellQuote(recreate), mysqlRootUser, db.GetName(), dump.Path))
+}
+
+// restore replaces the contents of a database of the server with a backup
+// The dump is restored as the user of the database so that it owns the restored objects, and
+// its transactions are allowed to write even if the database exceeds its size quota
+func (server *postgresqlServer) restore(db types.Database, backup *types.Backup, source io.Reader) error {
+	dump, err := ImportDump(source, backup, server.container)
+	if err != nil {
+		return err
+	}
+	return dump.restore(fmt.Sprintf(
+		"PGOPTIONS='-c default_transaction_read_only=off' PGPASSWORD=%s psql -q -v ON_ERROR_STOP=1 -h 127.0.0.1 -U %s -d %s -f %s",
+		shellQuote(db.GetPassword()), shellQuote(db.GetUser()), db.GetName(), dump.Path))
+}
+
+// restore replaces the contents of a database of the server with a backup
+// The collections are renamed from the database the backup was taken of
+func (server *mongoServer) restore(db types.Database, backup *types.Backup, source io.Reader) error {
+	dump, err := ImportDump(source, backup, server.container)
+	if err != nil {
+		return err
+	}
+	return dump.restore(fmt.Sprintf(
+		"mongorestore --quiet --username %s --password %s --authenticationDatabase admin --drop --archive=%s --nsFrom=%s --nsTo=%s",
+		shellQuote(server.rootUser), shellQuote(server.rootPassword), dump.Path,
+		shellQuote(backup.Database+".*"), shellQuote(db.GetName()+".*")))
+}
+
+// RestoreRedisDB replaces the contents of a Redis database with a backup
+// The server is stopped while its RDB file is replaced since it saves the dataset on shutdown
+func RestoreRedisDB(db types.Database, backup *types.Backup, source io.Reader) error {
+	storedir := redisStoreDir(db.GetName())
+	temp, err := ioutil.TempFile(storedir, "restore-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	err = decompressBackup(source, backup.Checksum, temp)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if err := docker.StopContainer(db.GetName()); err != nil {
+		return types.NewResErr(500, "container not stopped", err)
+	}
+	err = os.Rename(temp.Name(), filepath.Join(storedir, "dump.rdb"))
+	if startErr := docker.StartContainer(db.GetName()); startErr != nil {
+		return types.NewResErr(500, "container not started", startErr)
+	}
+	if err != nil {
+		return fmt.Errorf("Error while restoring the database : %s", err)
+	}
+	return nil
+}
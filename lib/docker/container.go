@@ -85,7 +85,8 @@ func CreateDatabaseContainer(containerCfg types.DatabaseContainer) (string, erro
 		ExposedPorts: nat.PortSet{
 			containerPortRule: struct{}{},
 		},
-		Env: envArr,
+		Env:      envArr,
+		Hostname: containerCfg.Hostname,
 		Volumes: map[string]struct{}{
 			volume: {},
 		},
@@ -104,6 +105,10 @@ func CreateDatabaseContainer(containerCfg types.DatabaseContainer) (string, erro
 				HostIP:   "0.0.0.0",
 				HostPort: fmt.Sprintf("%d", containerCfg.ContainerPort)}},
 		},
+		Resources: container.Resources{
+			NanoCPUs: containerCfg.CPU,
+			Memory:   containerCfg.Memory,
+		},
 	}
 
 	createdConf, err := cli.ContainerCreate(ctx, containerConfig, hostConfig, nil, containerCfg.Name)
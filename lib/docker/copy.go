@@ -20,3 +20,16 @@ func CopyToContainer(containerID, destination string, reader io.Reader) error {
 	}
 	return nil
 }
+
+// CopyFromContainer returns a tar archive of the file or directory at the source path
+// inside the container along with the source path's stat information
+func CopyFromContainer(containerID, source string) (io.ReadCloser, types.ContainerPathStat, error) {
+	ctx := context.Background()
+	return cli.CopyFromContainer(ctx, containerID, source)
+}
+
+// StatContainerPath returns the stat information of a path inside the container
+func StatContainerPath(containerID, path string) (types.ContainerPathStat, error) {
+	ctx := context.Background()
+	return cli.ContainerStatPath(ctx, containerID, path)
+}
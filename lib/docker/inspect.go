@@ -10,6 +10,16 @@ const (
 	Container_Healthy = "healthy"
 	Container_Unhealthy = "unhealthy"
 )
+// InspectContainer returns the low-level information of the container using the containerID
+func InspectContainer(containerID string) (*dockerTypes.ContainerJSON, error) {
+	ctx := context.Background()
+	container, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
 // InspectContainerState returns the state of the container using the containerID
 func InspectContainerState(containerID string) (*dockerTypes.ContainerState, error) {
 	ctx := context.Background()
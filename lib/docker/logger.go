@@ -1,12 +1,29 @@
 package docker
 
 import (
+	"bufio"
 	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
 	"golang.org/x/net/context"
 )
 
+const (
+	// StdoutStream is the standard output stream of a container
+	StdoutStream = "stdout"
+
+	// StderrStream is the standard error stream of a container
+	StderrStream = "stderr"
+)
+
 // ReadLogs returns the logs from a docker container
 func ReadLogs(containerID, tail string) ([]string, error) {
 	ctx := context.Background()
@@ -43,3 +60,168 @@ func ReadLogs(containerID, tail string) ([]string, error) {
 		}
 	}
 }
+
+// LogLine is a single line of a container's logs along with the stream it was written to
+type LogLine struct {
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+}
+
+// LogFilter filters the lines of a container's logs streamed by FollowLogs
+type LogFilter struct {
+	Tail   string
+	Since  time.Time
+	Until  time.Time
+	Stream string
+	Grep   *regexp.Regexp
+}
+
+// NewLogFilter returns a filter for a container's logs
+// since and until are unix timestamps and are ignored if zero, stream is either
+// stdout or stderr with both the streams selected if empty and grep is a regular
+// expression matched against every line
+func NewLogFilter(tail string, since, until int64, stream, grep string) (*LogFilter, error) {
+	filter := &LogFilter{Tail: tail, Stream: stream}
+	if filter.Tail == "" {
+		filter.Tail = "all"
+	}
+	if since > 0 {
+		filter.Since = time.Unix(since, 0)
+	}
+	if until > 0 {
+		filter.Until = time.Unix(until, 0)
+	}
+	if stream != "" && stream != StdoutStream && stream != StderrStream {
+		return nil, fmt.Errorf("stream `%s` is invalid, must be either %s or %s", stream, StdoutStream, StderrStream)
+	}
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, fmt.Errorf("grep pattern is invalid: %s", err.Error())
+		}
+		filter.Grep = re
+	}
+	return filter, nil
+}
+
+// SplitTimestamp splits a line of a container's logs into its timestamp and message
+func SplitTimestamp(line string) (time.Time, string, error) {
+	idx := strings.IndexByte(line, ' ')
+	if idx < 0 {
+		idx = len(line)
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, line[:idx])
+	if err != nil {
+		return time.Time{}, line, err
+	}
+	if idx == len(line) {
+		return timestamp, "", nil
+	}
+	return timestamp, line[idx+1:], nil
+}
+
+// match checks whether a timestamped line passes the grep filter
+func (filter *LogFilter) match(line string) bool {
+	if filter.Grep == nil {
+		return true
+	}
+	_, message, _ := SplitTimestamp(line)
+	return filter.Grep.MatchString(message)
+}
+
+// expired checks whether a timestamped line was written after the until filter
+func (filter *LogFilter) expired(line string) bool {
+	if filter.Until.IsZero() {
+		return false
+	}
+	timestamp, _, err := SplitTimestamp(line)
+	if err != nil {
+		return false
+	}
+	return timestamp.After(filter.Until)
+}
+
+// followStream scans the lines written to a stream of a container's logs and sends them to the channel
+func followStream(ctx context.Context, stream string, reader *io.PipeReader, lines chan<- LogLine, filter *LogFilter) {
+	// Closing the reader unblocks the demultiplexer if the context is done
+	defer reader.Close()
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if filter.expired(line) {
+			// Both streams are demultiplexed from the same connection, hence the rest of this
+			// stream is drained instead of closing its reader which would end the other stream too
+			io.Copy(ioutil.Discard, reader)
+			return
+		}
+		if !filter.match(line) {
+			continue
+		}
+		select {
+		case lines <- LogLine{Stream: stream, Line: line}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// FollowLogs streams the logs of a container line by line until the context is done
+// Each line is prefixed with its timestamp and the channel is closed once the stream ends
+// The stream ends by itself once the filter's until timestamp has passed
+func FollowLogs(ctx context.Context, containerID string, filter *LogFilter) (<-chan LogLine, error) {
+	options := types.ContainerLogsOptions{
+		ShowStdout: filter.Stream != StderrStream,
+		ShowStderr: filter.Stream != StdoutStream,
+		Timestamps: true,
+		Follow:     true,
+		Tail:       filter.Tail,
+	}
+	if !filter.Since.IsZero() {
+		options.Since = fmt.Sprintf("%d", filter.Since.Unix())
+	}
+
+	cancel := func() {}
+	if !filter.Until.IsZero() {
+		if filter.Until.Before(time.Now()) {
+			// The logs are complete hence there is nothing to follow
+			options.Follow = false
+		} else {
+			ctx, cancel = context.WithDeadline(ctx, filter.Until)
+		}
+	}
+
+	reader, err := cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	lines := make(chan LogLine, 100)
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+
+	go func() {
+		defer reader.Close()
+		_, err := stdcopy.StdCopy(stdoutWriter, stderrWriter, reader)
+		stdoutWriter.CloseWithError(err)
+		stderrWriter.CloseWithError(err)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		followStream(ctx, StdoutStream, stdoutReader, lines, filter)
+	}()
+	go func() {
+		defer wg.Done()
+		followStream(ctx, StderrStream, stderrReader, lines, filter)
+	}()
+	go func() {
+		wg.Wait()
+		cancel()
+		close(lines)
+	}()
+	return lines, nil
+}
@@ -2,10 +2,12 @@ package factory
 
 import (
 	"context"
+	"io"
 
 	"github.com/google/go-github/v41/github"
 	"github.com/sdslabs/gasper/configs"
 	pb "github.com/sdslabs/gasper/lib/factory/protos/application"
+	"github.com/sdslabs/gasper/lib/metrics"
 	"github.com/sdslabs/gasper/types"
 	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
@@ -17,6 +19,8 @@ func CreateApplication(language, owner, instanceURL string, data []byte) ([]byte
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -45,6 +49,8 @@ func RebuildApplication(name, instanceURL string) ([]byte, error) {
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -69,6 +75,8 @@ func DeleteApplication(name, instanceURL string) (*pb.DeletionResponse, error) {
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -93,6 +101,8 @@ func FetchApplicationLogs(name, tail, instanceURL string) (*pb.LogResponse, erro
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -114,6 +124,50 @@ func FetchApplicationLogs(name, tail, instanceURL string) (*pb.LogResponse, erro
 	return res, nil
 }
 
+// FollowApplicationLogs is a remote procedure call for following the logs of an application in a worker node
+// The handler is invoked for every line until it returns false, the stream ends or the context is done
+func FollowApplicationLogs(ctx context.Context, name string, query *types.LogQuery, instanceURL string, handler func(stream, line string) bool) error {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := pb.NewApplicationFactoryClient(conn)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.FollowLogs(ctx, &pb.FollowLogRequest{
+		Name:   name,
+		Tail:   query.Tail,
+		Since:  query.Since,
+		Until:  query.Until,
+		Stream: query.Stream,
+		Grep:   query.Grep,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !handler(line.GetStream(), line.GetLine()) {
+			return nil
+		}
+	}
+}
+
 // NewApplicationFactory returns a new GRPC server for creating applications
 func NewApplicationFactory(bindings pb.ApplicationFactoryServer) *grpc.Server {
 	srv := grpc.NewServer(
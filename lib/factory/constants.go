@@ -8,4 +8,8 @@ import (
 
 const timeout = 30 * time.Second
 
+// startupTimeout is the timeout of the calls which start the server of a database and wait for it to boot
+// It must stay above the time waited for the servers of dedicated and RabbitMQ databases to start
+const startupTimeout = 3 * time.Minute
+
 var authCredentials = &credentials{Secret: configs.GasperConfig.Secret}
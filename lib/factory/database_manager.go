@@ -2,8 +2,12 @@ package factory
 
 import (
 	"context"
+	"io"
 
+	"github.com/sdslabs/gasper/configs"
 	pb "github.com/sdslabs/gasper/lib/factory/protos/database"
+	"github.com/sdslabs/gasper/lib/metrics"
+	"github.com/sdslabs/gasper/types"
 	"google.golang.org/grpc"
 )
 
@@ -13,6 +17,8 @@ func CreateDatabase(language, owner, instanceURL string, data []byte) ([]byte, e
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -20,7 +26,7 @@ func CreateDatabase(language, owner, instanceURL string, data []byte) ([]byte, e
 	defer conn.Close()
 	client := pb.NewDatabaseFactoryClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
 	defer cancel()
 
 	res, err := client.Create(ctx, &pb.RequestBody{
@@ -41,6 +47,8 @@ func DeleteDatabase(name, instanceURL string) (*pb.GenericResponse, error) {
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -65,6 +73,8 @@ func FetchDatabaseServerLogs(language, tail, instanceURL string) (*pb.LogRespons
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -86,12 +96,58 @@ func FetchDatabaseServerLogs(language, tail, instanceURL string) (*pb.LogRespons
 	return res, nil
 }
 
+// FollowDatabaseServerLogs is a remote procedure call for following the logs of a database server in a worker node
+// The handler is invoked for every line until it returns false, the stream ends or the context is done
+func FollowDatabaseServerLogs(ctx context.Context, language string, query *types.LogQuery, instanceURL string, handler func(stream, line string) bool) error {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.FollowLogs(ctx, &pb.FollowLogRequest{
+		Language: language,
+		Tail:     query.Tail,
+		Since:    query.Since,
+		Until:    query.Until,
+		Stream:   query.Stream,
+		Grep:     query.Grep,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !handler(line.GetStream(), line.GetLine()) {
+			return nil
+		}
+	}
+}
+
 // ReloadDatabaseServer is a remote procedure call for restarting a database server in a worker node
 func ReloadDatabaseServer(language, instanceURL string) (*pb.GenericResponse, error) {
 	conn, err := grpc.Dial(
 		instanceURL,
 		grpc.WithInsecure(),
 		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
 	)
 	if err != nil {
 		return nil, err
@@ -110,6 +166,320 @@ func ReloadDatabaseServer(language, instanceURL string) (*pb.GenericResponse, er
 	return res, nil
 }
 
+// BackupDatabase is a remote procedure call for starting a backup of a database in a worker node
+// It returns the metadata of the backup which is taken in the background
+func BackupDatabase(name, trigger, instanceURL string) ([]byte, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.Backup(ctx, &pb.BackupRequest{
+		Name:    name,
+		Trigger: trigger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetData(), nil
+}
+
+// DeleteDatabaseBackup is a remote procedure call for deleting a backup of a database stored in a worker node
+func DeleteDatabaseBackup(name, id, instanceURL string) (*pb.GenericResponse, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.DeleteBackup(ctx, &pb.BackupHolder{
+		Name: name,
+		Id:   id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DownloadDatabaseBackup is a remote procedure call for downloading a backup of a database stored in a worker node
+// The compressed backup is written to the writer as it is received
+func DownloadDatabaseBackup(name, id, instanceURL string, writer io.Writer) error {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.FetchBackup(ctx, &pb.BackupHolder{
+		Name: name,
+		Id:   id,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+}
+
+// RestoreDatabase is a remote procedure call for restoring a backup into a database in a worker node
+// It returns the state of the restore which runs in the background
+func RestoreDatabase(name, backup, instanceURL string) ([]byte, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.Restore(ctx, &pb.RestoreRequest{
+		Name:   name,
+		Backup: backup,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetData(), nil
+}
+
+// CloneDatabase is a remote procedure call for creating a database in a worker node from a backup of the source
+// database, or from its live state if no backup is given
+// It returns the new database along with the state of the restore which runs in the background
+func CloneDatabase(language, owner, source, backup, instanceURL string, data []byte) ([]byte, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	res, err := client.Clone(ctx, &pb.CloneRequest{
+		Language: language,
+		Owner:    owner,
+		Data:     data,
+		Source:   source,
+		Backup:   backup,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetData(), nil
+}
+
+// RotateDatabasePassword is a remote procedure call for changing the password of a user of a database
+// in a worker node, or of the user of the database itself if no username is given
+func RotateDatabasePassword(name, username, password, instanceURL string) (*pb.GenericResponse, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.RotatePassword(ctx, &pb.CredentialRequest{
+		Name:     name,
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CreateDatabaseUser is a remote procedure call for creating an additional user of a database in a worker node
+func CreateDatabaseUser(name, username, password, role, instanceURL string) (*pb.GenericResponse, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.CreateUser(ctx, &pb.CredentialRequest{
+		Name:     name,
+		Username: username,
+		Password: password,
+		Role:     role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteDatabaseUser is a remote procedure call for deleting an additional user of a database in a worker node
+func DeleteDatabaseUser(name, username, instanceURL string) (*pb.GenericResponse, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.DeleteUser(ctx, &pb.CredentialRequest{
+		Name:     name,
+		Username: username,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// MigrateDatabase is a remote procedure call for moving a database to a worker node from the source node
+// A snapshot of the database is moved and removed from the source node if it is given, otherwise the latest
+// backup of the database is restored as the node it was deployed in is lost
+// It returns the state of the migration which runs in the background
+func MigrateDatabase(name, source, instanceURL string) ([]byte, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.Migrate(ctx, &pb.MigrateRequest{
+		Name:   name,
+		Source: source,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetData(), nil
+}
+
+// EvictDatabase is a remote procedure call for removing a database from a worker node it was moved away from
+func EvictDatabase(name, instanceURL string) (*pb.GenericResponse, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.Evict(ctx, &pb.NameHolder{
+		Name: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // NewDatabaseFactory returns a new GRPC server for creating databases
 func NewDatabaseFactory(bindings pb.DatabaseFactoryServer) *grpc.Server {
 	srv := grpc.NewServer(
@@ -119,3 +489,94 @@ func NewDatabaseFactory(bindings pb.DatabaseFactoryServer) *grpc.Server {
 	pb.RegisterDatabaseFactoryServer(srv, bindings)
 	return srv
 }
+
+// FetchDatabaseUsage is a remote procedure call for fetching the resources consumed by a database
+// along with its quota from the worker node it is deployed in
+func FetchDatabaseUsage(name, instanceURL string) ([]byte, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.Usage(ctx, &pb.NameHolder{
+		Name: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetData(), nil
+}
+
+// UpdateDatabaseQuota is a remote procedure call for changing the quota of a database
+// in the worker node it is deployed in
+func UpdateDatabaseQuota(name string, data []byte, instanceURL string) (*pb.GenericResponse, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	res, err := client.UpdateQuota(ctx, &pb.QuotaRequest{
+		Name: name,
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// QueryDatabase is a remote procedure call for running an ad hoc query on a database through its
+// query console in the worker node it is deployed in
+// The deadline of the call covers the timeout of the query along with the usual timeout
+func QueryDatabase(name, user string, data []byte, instanceURL string) ([]byte, error) {
+	conn, err := grpc.Dial(
+		instanceURL,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(authCredentials),
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(metrics.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := pb.NewDatabaseFactoryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+configs.ServiceConfig.DbMaker.Query.GetTimeout())
+	defer cancel()
+
+	res, err := client.Query(ctx, &pb.QueryRequest{
+		Name: name,
+		User: user,
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.GetData(), nil
+}
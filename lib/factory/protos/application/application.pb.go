@@ -290,6 +290,132 @@ func (m *LogResponse) GetData() []string {
 	return nil
 }
 
+type FollowLogRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Tail                 string   `protobuf:"bytes,2,opt,name=tail,proto3" json:"tail,omitempty"`
+	Since                int64    `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,4,opt,name=until,proto3" json:"until,omitempty"`
+	Stream               string   `protobuf:"bytes,5,opt,name=stream,proto3" json:"stream,omitempty"`
+	Grep                 string   `protobuf:"bytes,6,opt,name=grep,proto3" json:"grep,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FollowLogRequest) Reset()         { *m = FollowLogRequest{} }
+func (m *FollowLogRequest) String() string { return proto.CompactTextString(m) }
+func (*FollowLogRequest) ProtoMessage()    {}
+func (*FollowLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fc846aced8fe6ea6, []int{6}
+}
+
+func (m *FollowLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FollowLogRequest.Unmarshal(m, b)
+}
+func (m *FollowLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FollowLogRequest.Marshal(b, m, deterministic)
+}
+func (m *FollowLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FollowLogRequest.Merge(m, src)
+}
+func (m *FollowLogRequest) XXX_Size() int {
+	return xxx_messageInfo_FollowLogRequest.Size(m)
+}
+func (m *FollowLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FollowLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FollowLogRequest proto.InternalMessageInfo
+
+func (m *FollowLogRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FollowLogRequest) GetTail() string {
+	if m != nil {
+		return m.Tail
+	}
+	return ""
+}
+
+func (m *FollowLogRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *FollowLogRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *FollowLogRequest) GetStream() string {
+	if m != nil {
+		return m.Stream
+	}
+	return ""
+}
+
+func (m *FollowLogRequest) GetGrep() string {
+	if m != nil {
+		return m.Grep
+	}
+	return ""
+}
+
+type LogLine struct {
+	Stream               string   `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
+	Line                 string   `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogLine) Reset()         { *m = LogLine{} }
+func (m *LogLine) String() string { return proto.CompactTextString(m) }
+func (*LogLine) ProtoMessage()    {}
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fc846aced8fe6ea6, []int{7}
+}
+
+func (m *LogLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogLine.Unmarshal(m, b)
+}
+func (m *LogLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogLine.Marshal(b, m, deterministic)
+}
+func (m *LogLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogLine.Merge(m, src)
+}
+func (m *LogLine) XXX_Size() int {
+	return xxx_messageInfo_LogLine.Size(m)
+}
+func (m *LogLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogLine proto.InternalMessageInfo
+
+func (m *LogLine) GetStream() string {
+	if m != nil {
+		return m.Stream
+	}
+	return ""
+}
+
+func (m *LogLine) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*RequestBody)(nil), "application.RequestBody")
 	proto.RegisterType((*ResponseBody)(nil), "application.ResponseBody")
@@ -297,32 +423,40 @@ func init() {
 	proto.RegisterType((*DeletionResponse)(nil), "application.DeletionResponse")
 	proto.RegisterType((*LogRequest)(nil), "application.LogRequest")
 	proto.RegisterType((*LogResponse)(nil), "application.LogResponse")
+	proto.RegisterType((*FollowLogRequest)(nil), "application.FollowLogRequest")
+	proto.RegisterType((*LogLine)(nil), "application.LogLine")
 }
 
 func init() { proto.RegisterFile("application.proto", fileDescriptor_fc846aced8fe6ea6) }
 
 var fileDescriptor_fc846aced8fe6ea6 = []byte{
-	// 314 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0xcb, 0x4e, 0xc3, 0x30,
-	0x10, 0x6c, 0x5a, 0xe8, 0x63, 0xdb, 0x03, 0xac, 0x90, 0x30, 0x91, 0x90, 0x22, 0x9f, 0x7a, 0x40,
-	0x3d, 0x00, 0x37, 0x0e, 0x85, 0x82, 0x2a, 0x0e, 0x15, 0x07, 0xf3, 0x05, 0x6e, 0xb2, 0x0a, 0x91,
-	0xdc, 0x38, 0xc4, 0x8e, 0x50, 0xff, 0x85, 0x8f, 0x45, 0x71, 0x9b, 0x26, 0x2d, 0xaf, 0xdb, 0xac,
-	0x67, 0x76, 0x32, 0x3b, 0x0a, 0x9c, 0xca, 0x2c, 0x53, 0x49, 0x28, 0x6d, 0xa2, 0xd3, 0x49, 0x96,
-	0x6b, 0xab, 0x71, 0xd8, 0x78, 0xe2, 0xaf, 0x30, 0x14, 0xf4, 0x5e, 0x90, 0xb1, 0x33, 0x1d, 0xad,
-	0xd1, 0x87, 0xbe, 0x92, 0x69, 0x5c, 0xc8, 0x98, 0x98, 0x17, 0x78, 0xe3, 0x81, 0xd8, 0xcd, 0x78,
-	0x06, 0xc7, 0xfa, 0x23, 0xa5, 0x9c, 0xb5, 0x1d, 0xb1, 0x19, 0x10, 0xe1, 0x28, 0x92, 0x56, 0xb2,
-	0x4e, 0xe0, 0x8d, 0x47, 0xc2, 0x61, 0xce, 0x61, 0x24, 0xc8, 0x64, 0x3a, 0x35, 0xe4, 0x5c, 0x2b,
-	0x8d, 0xd7, 0xd0, 0x04, 0x00, 0x2f, 0x72, 0x45, 0xcf, 0x5a, 0x45, 0x1b, 0x97, 0x54, 0xae, 0xaa,
-	0x6f, 0x3a, 0xcc, 0xaf, 0xe0, 0xe4, 0x89, 0x14, 0x95, 0x31, 0x2b, 0x37, 0x64, 0xd0, 0x33, 0x45,
-	0x18, 0x92, 0x31, 0x4e, 0xda, 0x17, 0xd5, 0xc8, 0x6f, 0x01, 0x16, 0x3a, 0xde, 0xde, 0xf2, 0x93,
-	0x5f, 0xf9, 0x66, 0x65, 0xa2, 0xb6, 0xf1, 0x1d, 0xe6, 0x77, 0x30, 0x74, 0x5b, 0xff, 0xd9, 0xef,
-	0x4e, 0x68, 0x07, 0x9d, 0x72, 0xb9, 0xc4, 0xd7, 0x9f, 0x6d, 0xc0, 0x87, 0xba, 0xcb, 0xb9, 0x0c,
-	0xad, 0xce, 0xd7, 0x38, 0x85, 0xee, 0x63, 0x4e, 0xd2, 0x12, 0xb2, 0x49, 0xb3, 0xfd, 0x46, 0xcf,
-	0xfe, 0xc5, 0x01, 0x53, 0x97, 0xc5, 0x5b, 0x38, 0x83, 0xae, 0x3b, 0x9c, 0xf0, 0x7c, 0x4f, 0x56,
-	0xf7, 0xe5, 0x5f, 0xee, 0x11, 0x87, 0x35, 0xf1, 0x16, 0x4e, 0xa1, 0x27, 0x68, 0x59, 0x24, 0x2a,
-	0xfa, 0xdd, 0xe4, 0xcf, 0x10, 0xf7, 0x30, 0x98, 0x93, 0x0d, 0xdf, 0x16, 0x3a, 0x36, 0x07, 0x16,
-	0x75, 0xcf, 0x3e, 0xfb, 0x4e, 0x54, 0x11, 0x96, 0x5d, 0xf7, 0xbb, 0xdd, 0x7c, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0xd0, 0x7b, 0xe0, 0x62, 0x83, 0x02, 0x00, 0x00,
+	// 408 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xcb, 0x8e, 0xd3, 0x30,
+	0x14, 0x6d, 0xda, 0x99, 0x74, 0xe6, 0x76, 0x16, 0x83, 0x55, 0x81, 0x89, 0x84, 0x14, 0x79, 0xd5,
+	0x05, 0xaa, 0x10, 0x8f, 0x15, 0x8b, 0x42, 0x81, 0x8a, 0x45, 0xc4, 0x22, 0x7c, 0x81, 0x9b, 0x5c,
+	0x05, 0x4b, 0xae, 0x1d, 0x62, 0x47, 0x55, 0x7f, 0x83, 0x5f, 0xe4, 0x47, 0x90, 0x9d, 0xe6, 0xd1,
+	0xf0, 0xd2, 0xec, 0xee, 0xb9, 0x8f, 0x93, 0x73, 0x7d, 0x6e, 0xe0, 0x11, 0x2f, 0x4b, 0x29, 0x32,
+	0x6e, 0x85, 0x56, 0xeb, 0xb2, 0xd2, 0x56, 0x93, 0xc5, 0x20, 0xc5, 0xbe, 0xc2, 0x22, 0xc5, 0xef,
+	0x35, 0x1a, 0xbb, 0xd5, 0xf9, 0x89, 0x44, 0x70, 0x23, 0xb9, 0x2a, 0x6a, 0x5e, 0x20, 0x0d, 0xe2,
+	0x60, 0x75, 0x9b, 0x76, 0x98, 0x2c, 0xe1, 0x5a, 0x1f, 0x15, 0x56, 0x74, 0xea, 0x0b, 0x0d, 0x20,
+	0x04, 0xae, 0x72, 0x6e, 0x39, 0x9d, 0xc5, 0xc1, 0xea, 0x2e, 0xf5, 0x31, 0x63, 0x70, 0x97, 0xa2,
+	0x29, 0xb5, 0x32, 0xe8, 0x59, 0xdb, 0x9e, 0x60, 0xd0, 0x13, 0x03, 0x7c, 0xe1, 0x07, 0xfc, 0xac,
+	0x65, 0xde, 0xb0, 0x28, 0x7e, 0x68, 0xbf, 0xe9, 0x63, 0xf6, 0x1c, 0xee, 0x3f, 0xa2, 0x44, 0x27,
+	0xb3, 0x65, 0x23, 0x14, 0xe6, 0xa6, 0xce, 0x32, 0x34, 0xc6, 0xb7, 0xde, 0xa4, 0x2d, 0x64, 0xaf,
+	0x01, 0x12, 0x5d, 0x9c, 0x77, 0xf9, 0x13, 0x9f, 0xcb, 0x59, 0x2e, 0xe4, 0x59, 0xbe, 0x8f, 0xd9,
+	0x5b, 0x58, 0xf8, 0xa9, 0xff, 0xd1, 0x77, 0x2b, 0x4c, 0xe3, 0x99, 0x1b, 0xf6, 0x2b, 0xfc, 0x08,
+	0xe0, 0x7e, 0xa7, 0xa5, 0xd4, 0xc7, 0x87, 0x7f, 0xd9, 0xbd, 0xa6, 0x11, 0x2a, 0x43, 0xff, 0x70,
+	0xb3, 0xb4, 0x01, 0x2e, 0x5b, 0x2b, 0x2b, 0x24, 0xbd, 0x6a, 0xb2, 0x1e, 0x90, 0xc7, 0x10, 0x1a,
+	0x5b, 0x21, 0x3f, 0xd0, 0x6b, 0xcf, 0x70, 0x46, 0x8e, 0xb7, 0xa8, 0xb0, 0xa4, 0x61, 0xc3, 0xeb,
+	0x62, 0xf6, 0x06, 0xe6, 0x89, 0x2e, 0x12, 0xa1, 0x70, 0x30, 0x16, 0x8c, 0xc7, 0xa4, 0x50, 0xd8,
+	0xca, 0x71, 0xf1, 0xcb, 0x9f, 0x53, 0x20, 0xef, 0xfb, 0xbb, 0xd8, 0xf1, 0xcc, 0xea, 0xea, 0x44,
+	0x36, 0x10, 0x7e, 0xa8, 0x90, 0x5b, 0x24, 0x74, 0x3d, 0xbc, 0xa4, 0xc1, 0xcd, 0x44, 0x4f, 0x47,
+	0x95, 0xde, 0x78, 0x36, 0x21, 0x5b, 0x08, 0xbd, 0x89, 0x48, 0x9e, 0x5c, 0xb4, 0xf5, 0xde, 0x47,
+	0xcf, 0x2e, 0x0a, 0x63, 0xcb, 0xd9, 0x84, 0x6c, 0x60, 0x9e, 0xe2, 0xbe, 0x16, 0x32, 0xff, 0x3b,
+	0xc9, 0x3f, 0x45, 0xbc, 0x83, 0xdb, 0x1d, 0xda, 0xec, 0x5b, 0xa2, 0x0b, 0x33, 0xa2, 0xe8, 0x9d,
+	0x8b, 0xe8, 0xef, 0x85, 0x4e, 0xc2, 0x27, 0x80, 0xce, 0x69, 0x43, 0x2e, 0x15, 0x8f, 0x4f, 0x20,
+	0x5a, 0x8e, 0x89, 0x9c, 0x1b, 0x6c, 0xf2, 0x22, 0xd8, 0x87, 0xfe, 0x0f, 0x7c, 0xf5, 0x6b, 0x00,
+	0x89, 0x42, 0x7e, 0x72, 0x96, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -341,6 +475,7 @@ type ApplicationFactoryClient interface {
 	Delete(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*DeletionResponse, error)
 	Rebuild(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*ResponseBody, error)
 	FetchLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (*LogResponse, error)
+	FollowLogs(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (ApplicationFactory_FollowLogsClient, error)
 }
 
 type applicationFactoryClient struct {
@@ -387,12 +522,45 @@ func (c *applicationFactoryClient) FetchLogs(ctx context.Context, in *LogRequest
 	return out, nil
 }
 
+func (c *applicationFactoryClient) FollowLogs(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (ApplicationFactory_FollowLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ApplicationFactory_serviceDesc.Streams[0], "/application.ApplicationFactory/FollowLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &applicationFactoryFollowLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApplicationFactory_FollowLogsClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type applicationFactoryFollowLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *applicationFactoryFollowLogsClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ApplicationFactoryServer is the server API for ApplicationFactory service.
 type ApplicationFactoryServer interface {
 	Create(context.Context, *RequestBody) (*ResponseBody, error)
 	Delete(context.Context, *NameHolder) (*DeletionResponse, error)
 	Rebuild(context.Context, *NameHolder) (*ResponseBody, error)
 	FetchLogs(context.Context, *LogRequest) (*LogResponse, error)
+	FollowLogs(*FollowLogRequest, ApplicationFactory_FollowLogsServer) error
 }
 
 // UnimplementedApplicationFactoryServer can be embedded to have forward compatible implementations.
@@ -411,6 +579,9 @@ func (*UnimplementedApplicationFactoryServer) Rebuild(ctx context.Context, req *
 func (*UnimplementedApplicationFactoryServer) FetchLogs(ctx context.Context, req *LogRequest) (*LogResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchLogs not implemented")
 }
+func (*UnimplementedApplicationFactoryServer) FollowLogs(req *FollowLogRequest, srv ApplicationFactory_FollowLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method FollowLogs not implemented")
+}
 
 func RegisterApplicationFactoryServer(s *grpc.Server, srv ApplicationFactoryServer) {
 	s.RegisterService(&_ApplicationFactory_serviceDesc, srv)
@@ -488,6 +659,27 @@ func _ApplicationFactory_FetchLogs_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ApplicationFactory_FollowLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FollowLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApplicationFactoryServer).FollowLogs(m, &applicationFactoryFollowLogsServer{stream})
+}
+
+type ApplicationFactory_FollowLogsServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type applicationFactoryFollowLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *applicationFactoryFollowLogsServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _ApplicationFactory_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "application.ApplicationFactory",
 	HandlerType: (*ApplicationFactoryServer)(nil),
@@ -509,6 +701,12 @@ var _ApplicationFactory_serviceDesc = grpc.ServiceDesc{
 			Handler:    _ApplicationFactory_FetchLogs_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FollowLogs",
+			Handler:       _ApplicationFactory_FollowLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "application.proto",
 }
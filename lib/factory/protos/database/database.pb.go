@@ -329,6 +329,595 @@ func (m *LogResponse) GetData() []string {
 	return nil
 }
 
+type FollowLogRequest struct {
+	Language             string   `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	Tail                 string   `protobuf:"bytes,2,opt,name=tail,proto3" json:"tail,omitempty"`
+	Since                int64    `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,4,opt,name=until,proto3" json:"until,omitempty"`
+	Stream               string   `protobuf:"bytes,5,opt,name=stream,proto3" json:"stream,omitempty"`
+	Grep                 string   `protobuf:"bytes,6,opt,name=grep,proto3" json:"grep,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FollowLogRequest) Reset()         { *m = FollowLogRequest{} }
+func (m *FollowLogRequest) String() string { return proto.CompactTextString(m) }
+func (*FollowLogRequest) ProtoMessage()    {}
+func (*FollowLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{7}
+}
+
+func (m *FollowLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FollowLogRequest.Unmarshal(m, b)
+}
+func (m *FollowLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FollowLogRequest.Marshal(b, m, deterministic)
+}
+func (m *FollowLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FollowLogRequest.Merge(m, src)
+}
+func (m *FollowLogRequest) XXX_Size() int {
+	return xxx_messageInfo_FollowLogRequest.Size(m)
+}
+func (m *FollowLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FollowLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FollowLogRequest proto.InternalMessageInfo
+
+func (m *FollowLogRequest) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *FollowLogRequest) GetTail() string {
+	if m != nil {
+		return m.Tail
+	}
+	return ""
+}
+
+func (m *FollowLogRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *FollowLogRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *FollowLogRequest) GetStream() string {
+	if m != nil {
+		return m.Stream
+	}
+	return ""
+}
+
+func (m *FollowLogRequest) GetGrep() string {
+	if m != nil {
+		return m.Grep
+	}
+	return ""
+}
+
+type LogLine struct {
+	Stream               string   `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
+	Line                 string   `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogLine) Reset()         { *m = LogLine{} }
+func (m *LogLine) String() string { return proto.CompactTextString(m) }
+func (*LogLine) ProtoMessage()    {}
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{8}
+}
+
+func (m *LogLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogLine.Unmarshal(m, b)
+}
+func (m *LogLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogLine.Marshal(b, m, deterministic)
+}
+func (m *LogLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogLine.Merge(m, src)
+}
+func (m *LogLine) XXX_Size() int {
+	return xxx_messageInfo_LogLine.Size(m)
+}
+func (m *LogLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogLine proto.InternalMessageInfo
+
+func (m *LogLine) GetStream() string {
+	if m != nil {
+		return m.Stream
+	}
+	return ""
+}
+
+func (m *LogLine) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+type BackupRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Trigger              string   `protobuf:"bytes,2,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupRequest) Reset()         { *m = BackupRequest{} }
+func (m *BackupRequest) String() string { return proto.CompactTextString(m) }
+func (*BackupRequest) ProtoMessage()    {}
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{9}
+}
+
+func (m *BackupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupRequest.Unmarshal(m, b)
+}
+func (m *BackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupRequest.Marshal(b, m, deterministic)
+}
+func (m *BackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupRequest.Merge(m, src)
+}
+func (m *BackupRequest) XXX_Size() int {
+	return xxx_messageInfo_BackupRequest.Size(m)
+}
+func (m *BackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupRequest proto.InternalMessageInfo
+
+func (m *BackupRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BackupRequest) GetTrigger() string {
+	if m != nil {
+		return m.Trigger
+	}
+	return ""
+}
+
+type BackupHolder struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupHolder) Reset()         { *m = BackupHolder{} }
+func (m *BackupHolder) String() string { return proto.CompactTextString(m) }
+func (*BackupHolder) ProtoMessage()    {}
+func (*BackupHolder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{10}
+}
+
+func (m *BackupHolder) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupHolder.Unmarshal(m, b)
+}
+func (m *BackupHolder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupHolder.Marshal(b, m, deterministic)
+}
+func (m *BackupHolder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupHolder.Merge(m, src)
+}
+func (m *BackupHolder) XXX_Size() int {
+	return xxx_messageInfo_BackupHolder.Size(m)
+}
+func (m *BackupHolder) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupHolder.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupHolder proto.InternalMessageInfo
+
+func (m *BackupHolder) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BackupHolder) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type BackupChunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupChunk) Reset()         { *m = BackupChunk{} }
+func (m *BackupChunk) String() string { return proto.CompactTextString(m) }
+func (*BackupChunk) ProtoMessage()    {}
+func (*BackupChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{11}
+}
+
+func (m *BackupChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupChunk.Unmarshal(m, b)
+}
+func (m *BackupChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupChunk.Marshal(b, m, deterministic)
+}
+func (m *BackupChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupChunk.Merge(m, src)
+}
+func (m *BackupChunk) XXX_Size() int {
+	return xxx_messageInfo_BackupChunk.Size(m)
+}
+func (m *BackupChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupChunk proto.InternalMessageInfo
+
+func (m *BackupChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type RestoreRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Backup               string   `protobuf:"bytes,2,opt,name=backup,proto3" json:"backup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{12}
+}
+
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestoreRequest) GetBackup() string {
+	if m != nil {
+		return m.Backup
+	}
+	return ""
+}
+
+type CloneRequest struct {
+	Language             string   `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Source               string   `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Backup               string   `protobuf:"bytes,5,opt,name=backup,proto3" json:"backup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloneRequest) Reset()         { *m = CloneRequest{} }
+func (m *CloneRequest) String() string { return proto.CompactTextString(m) }
+func (*CloneRequest) ProtoMessage()    {}
+func (*CloneRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{13}
+}
+
+func (m *CloneRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloneRequest.Unmarshal(m, b)
+}
+func (m *CloneRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloneRequest.Marshal(b, m, deterministic)
+}
+func (m *CloneRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloneRequest.Merge(m, src)
+}
+func (m *CloneRequest) XXX_Size() int {
+	return xxx_messageInfo_CloneRequest.Size(m)
+}
+func (m *CloneRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloneRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloneRequest proto.InternalMessageInfo
+
+func (m *CloneRequest) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *CloneRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *CloneRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *CloneRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *CloneRequest) GetBackup() string {
+	if m != nil {
+		return m.Backup
+	}
+	return ""
+}
+
+type CredentialRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Role                 string   `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CredentialRequest) Reset()         { *m = CredentialRequest{} }
+func (m *CredentialRequest) String() string { return proto.CompactTextString(m) }
+func (*CredentialRequest) ProtoMessage()    {}
+func (*CredentialRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{14}
+}
+
+func (m *CredentialRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CredentialRequest.Unmarshal(m, b)
+}
+func (m *CredentialRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CredentialRequest.Marshal(b, m, deterministic)
+}
+func (m *CredentialRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CredentialRequest.Merge(m, src)
+}
+func (m *CredentialRequest) XXX_Size() int {
+	return xxx_messageInfo_CredentialRequest.Size(m)
+}
+func (m *CredentialRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CredentialRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CredentialRequest proto.InternalMessageInfo
+
+func (m *CredentialRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CredentialRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CredentialRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *CredentialRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type MigrateRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source               string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MigrateRequest) Reset()         { *m = MigrateRequest{} }
+func (m *MigrateRequest) String() string { return proto.CompactTextString(m) }
+func (*MigrateRequest) ProtoMessage()    {}
+func (*MigrateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{15}
+}
+
+func (m *MigrateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MigrateRequest.Unmarshal(m, b)
+}
+func (m *MigrateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MigrateRequest.Marshal(b, m, deterministic)
+}
+func (m *MigrateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrateRequest.Merge(m, src)
+}
+func (m *MigrateRequest) XXX_Size() int {
+	return xxx_messageInfo_MigrateRequest.Size(m)
+}
+func (m *MigrateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MigrateRequest proto.InternalMessageInfo
+
+func (m *MigrateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *MigrateRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+type QuotaRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaRequest) Reset()         { *m = QuotaRequest{} }
+func (m *QuotaRequest) String() string { return proto.CompactTextString(m) }
+func (*QuotaRequest) ProtoMessage()    {}
+func (*QuotaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{16}
+}
+
+func (m *QuotaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaRequest.Unmarshal(m, b)
+}
+func (m *QuotaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaRequest.Marshal(b, m, deterministic)
+}
+func (m *QuotaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaRequest.Merge(m, src)
+}
+func (m *QuotaRequest) XXX_Size() int {
+	return xxx_messageInfo_QuotaRequest.Size(m)
+}
+func (m *QuotaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaRequest proto.InternalMessageInfo
+
+func (m *QuotaRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QuotaRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type QueryRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryRequest) Reset()         { *m = QueryRequest{} }
+func (m *QueryRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRequest) ProtoMessage()    {}
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b90fe3356ea5df07, []int{17}
+}
+
+func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryRequest.Unmarshal(m, b)
+}
+func (m *QueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryRequest.Marshal(b, m, deterministic)
+}
+func (m *QueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRequest.Merge(m, src)
+}
+func (m *QueryRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryRequest.Size(m)
+}
+func (m *QueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRequest proto.InternalMessageInfo
+
+func (m *QueryRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RequestBody)(nil), "database.RequestBody")
 	proto.RegisterType((*ResponseBody)(nil), "database.ResponseBody")
@@ -337,33 +926,71 @@ func init() {
 	proto.RegisterType((*GenericResponse)(nil), "database.GenericResponse")
 	proto.RegisterType((*LogRequest)(nil), "database.LogRequest")
 	proto.RegisterType((*LogResponse)(nil), "database.LogResponse")
+	proto.RegisterType((*FollowLogRequest)(nil), "database.FollowLogRequest")
+	proto.RegisterType((*LogLine)(nil), "database.LogLine")
+	proto.RegisterType((*BackupRequest)(nil), "database.BackupRequest")
+	proto.RegisterType((*BackupHolder)(nil), "database.BackupHolder")
+	proto.RegisterType((*BackupChunk)(nil), "database.BackupChunk")
+	proto.RegisterType((*RestoreRequest)(nil), "database.RestoreRequest")
+	proto.RegisterType((*CloneRequest)(nil), "database.CloneRequest")
+	proto.RegisterType((*CredentialRequest)(nil), "database.CredentialRequest")
+	proto.RegisterType((*MigrateRequest)(nil), "database.MigrateRequest")
+	proto.RegisterType((*QuotaRequest)(nil), "database.QuotaRequest")
+	proto.RegisterType((*QueryRequest)(nil), "database.QueryRequest")
 }
 
 func init() { proto.RegisterFile("database.proto", fileDescriptor_b90fe3356ea5df07) }
 
 var fileDescriptor_b90fe3356ea5df07 = []byte{
-	// 325 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0x4f, 0x4f, 0xfa, 0x40,
-	0x10, 0xa5, 0xc0, 0xaf, 0x3f, 0x18, 0x08, 0x24, 0x13, 0x30, 0xb5, 0xa7, 0x66, 0x4f, 0x24, 0x1a,
-	0x0e, 0x7a, 0x52, 0x4c, 0x4c, 0x94, 0xa0, 0x87, 0xc6, 0xc3, 0xfa, 0x09, 0x96, 0x76, 0x52, 0x49,
-	0x4a, 0x17, 0xbb, 0xdb, 0x18, 0xbe, 0x80, 0x9f, 0xdb, 0x74, 0xe9, 0x3f, 0x39, 0xd4, 0xdb, 0xcc,
-	0xf4, 0xbd, 0x79, 0x7d, 0x6f, 0x16, 0x26, 0xa1, 0xd0, 0x62, 0x2b, 0x14, 0x2d, 0x0f, 0xa9, 0xd4,
-	0x12, 0x07, 0x65, 0xcf, 0xde, 0x61, 0xc4, 0xe9, 0x33, 0x23, 0xa5, 0x9f, 0x64, 0x78, 0x44, 0x17,
-	0x06, 0xb1, 0x48, 0xa2, 0x4c, 0x44, 0xe4, 0x58, 0x9e, 0xb5, 0x18, 0xf2, 0xaa, 0xc7, 0x19, 0xfc,
-	0x93, 0x5f, 0x09, 0xa5, 0x4e, 0xd7, 0x7c, 0x38, 0x35, 0x88, 0xd0, 0xcf, 0x97, 0x39, 0x3d, 0xcf,
-	0x5a, 0x8c, 0xb9, 0xa9, 0x19, 0x83, 0x31, 0x27, 0x75, 0x90, 0x89, 0x22, 0xb3, 0xb5, 0xc4, 0x58,
-	0x0d, 0x8c, 0x07, 0xf0, 0x26, 0xf6, 0xf4, 0x2a, 0xe3, 0xf0, 0xb4, 0x25, 0x11, 0xfb, 0x52, 0xd3,
-	0xd4, 0xec, 0x1a, 0x26, 0x7e, 0xa1, 0x5d, 0xa0, 0x5a, 0xfe, 0x8e, 0x5d, 0xc1, 0xf4, 0x85, 0x12,
-	0x4a, 0x77, 0x41, 0x29, 0x8d, 0x0e, 0xfc, 0x57, 0x59, 0x10, 0x90, 0x52, 0x06, 0x3d, 0xe0, 0x65,
-	0xcb, 0x1e, 0x00, 0x7c, 0x19, 0x15, 0xc6, 0x5b, 0x4d, 0x23, 0xf4, 0xb5, 0xd8, 0xc5, 0x85, 0x67,
-	0x53, 0xb3, 0x15, 0x8c, 0x0c, 0xfb, 0x2f, 0x99, 0xca, 0x77, 0xd7, 0xeb, 0xe5, 0xe4, 0xbc, 0xbe,
-	0xf9, 0xee, 0xc2, 0x74, 0x5d, 0xa4, 0xbf, 0x11, 0x81, 0x96, 0xe9, 0x11, 0xef, 0xc0, 0x7e, 0x4e,
-	0x49, 0x68, 0xc2, 0xf9, 0xb2, 0xba, 0x54, 0xe3, 0x2c, 0xee, 0x45, 0x73, 0x5c, 0x07, 0xcb, 0x3a,
-	0xb8, 0x02, 0x7b, 0x4d, 0x31, 0x69, 0xc2, 0x59, 0x8d, 0xa9, 0x83, 0x75, 0x2f, 0xeb, 0xe9, 0x59,
-	0x3c, 0xac, 0x83, 0xf7, 0x30, 0xdc, 0x90, 0x0e, 0x3e, 0x7c, 0x19, 0xa9, 0x26, 0xbf, 0xce, 0xc6,
-	0x9d, 0x9f, 0x4d, 0x2b, 0xee, 0x23, 0xd8, 0x9c, 0x62, 0x29, 0x42, 0x74, 0x1a, 0x90, 0x5f, 0xf7,
-	0x6a, 0x15, 0xdf, 0xda, 0xe6, 0x29, 0xde, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0xea, 0xa9, 0x3a,
-	0x16, 0x9c, 0x02, 0x00, 0x00,
+	// 767 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x5d, 0x6f, 0xdb, 0x36,
+	0x14, 0x8d, 0x1c, 0x5b, 0xb6, 0xaf, 0x3d, 0x67, 0x21, 0x12, 0x4f, 0xf3, 0x5e, 0x3c, 0x3e, 0x05,
+	0xd8, 0x10, 0x0c, 0x19, 0xb6, 0x22, 0x5f, 0x48, 0x11, 0xa7, 0x6e, 0x11, 0xb8, 0x45, 0xab, 0x22,
+	0x3f, 0x80, 0x91, 0x2e, 0x14, 0x21, 0x8a, 0xe8, 0x92, 0x54, 0x83, 0xbc, 0xf7, 0x27, 0xf4, 0xa9,
+	0xbf, 0xb6, 0x20, 0xf5, 0x69, 0xc3, 0x91, 0x83, 0xe4, 0x8d, 0x97, 0xe2, 0x39, 0xf7, 0xde, 0x43,
+	0xde, 0x03, 0xc1, 0xc0, 0x67, 0x8a, 0x5d, 0x33, 0x89, 0xfb, 0x73, 0xc1, 0x15, 0x27, 0x9d, 0x3c,
+	0xa6, 0x9f, 0xa1, 0xe7, 0xe2, 0x97, 0x04, 0xa5, 0x3a, 0xe7, 0xfe, 0x03, 0x19, 0x41, 0x27, 0x62,
+	0x71, 0x90, 0xb0, 0x00, 0x1d, 0x6b, 0x6c, 0xed, 0x75, 0xdd, 0x22, 0x26, 0x3b, 0xd0, 0xe2, 0xf7,
+	0x31, 0x0a, 0xa7, 0x61, 0x3e, 0xa4, 0x01, 0x21, 0xd0, 0xd4, 0x64, 0xce, 0xe6, 0xd8, 0xda, 0xeb,
+	0xbb, 0x66, 0x4d, 0x29, 0xf4, 0x5d, 0x94, 0x73, 0x1e, 0x4b, 0x34, 0xac, 0xf9, 0x19, 0xab, 0x72,
+	0x66, 0x0c, 0xf0, 0x81, 0xdd, 0xe1, 0x3b, 0x1e, 0xf9, 0x29, 0x4b, 0xcc, 0xee, 0xf2, 0x9c, 0x66,
+	0x4d, 0xff, 0x86, 0xc1, 0x2c, 0xcb, 0x9d, 0x9d, 0xaa, 0xa9, 0x8e, 0xfe, 0x05, 0x5b, 0x6f, 0x31,
+	0x46, 0x11, 0x7a, 0x79, 0x6a, 0xe2, 0x40, 0x5b, 0x26, 0x9e, 0x87, 0x52, 0x9a, 0xd3, 0x1d, 0x37,
+	0x0f, 0xe9, 0x09, 0xc0, 0x8c, 0x07, 0x59, 0xe3, 0xb5, 0x4d, 0x13, 0x68, 0x2a, 0x16, 0x46, 0x59,
+	0xcf, 0x66, 0x4d, 0x8f, 0xa1, 0x67, 0xd0, 0xeb, 0xd2, 0x14, 0x7d, 0x37, 0xc6, 0x9b, 0x1a, 0x6c,
+	0xfa, 0xfe, 0x61, 0xc1, 0xaf, 0x53, 0x1e, 0x45, 0xfc, 0xfe, 0xf9, 0x15, 0xe8, 0xab, 0x90, 0x61,
+	0xec, 0xa1, 0x51, 0x7d, 0xd3, 0x4d, 0x03, 0xbd, 0x9b, 0xc4, 0x2a, 0x8c, 0x9c, 0x66, 0xba, 0x6b,
+	0x02, 0x32, 0x04, 0x5b, 0x2a, 0x81, 0xec, 0xce, 0x69, 0x19, 0x86, 0x2c, 0xd2, 0xbc, 0x81, 0xc0,
+	0xb9, 0x63, 0xa7, 0xbc, 0x7a, 0x4d, 0xff, 0x83, 0xf6, 0x8c, 0x07, 0xb3, 0x30, 0xc6, 0x0a, 0xcc,
+	0x5a, 0x86, 0x45, 0x61, 0x8c, 0x79, 0x39, 0x7a, 0x4d, 0x4f, 0xe1, 0x97, 0x73, 0xe6, 0xdd, 0x26,
+	0xf3, 0xbc, 0x9f, 0x15, 0xd7, 0xa9, 0x65, 0x52, 0x22, 0x0c, 0x82, 0xe2, 0x01, 0xe5, 0x21, 0x3d,
+	0x80, 0x7e, 0x0a, 0x7f, 0xfc, 0x31, 0x90, 0x01, 0x34, 0x42, 0x3f, 0x03, 0x36, 0x42, 0x9f, 0xfe,
+	0x09, 0xbd, 0x14, 0x33, 0xb9, 0x49, 0xe2, 0xdb, 0x95, 0x2f, 0xec, 0x04, 0x06, 0x2e, 0x4a, 0xc5,
+	0x05, 0xd6, 0x95, 0x35, 0x04, 0xfb, 0xda, 0x10, 0x65, 0xe4, 0x59, 0x44, 0xbf, 0x59, 0xd0, 0x9f,
+	0x44, 0x3c, 0xc6, 0xa7, 0xdc, 0xd1, 0x93, 0x47, 0xc3, 0xc8, 0xca, 0x13, 0xe1, 0xa1, 0xd3, 0xcc,
+	0x64, 0x35, 0x51, 0xa5, 0x8c, 0xd6, 0x42, 0x19, 0x12, 0xb6, 0x27, 0x02, 0x7d, 0x8c, 0x55, 0xc8,
+	0xa2, 0xba, 0x3e, 0x46, 0xd0, 0x49, 0x24, 0x0a, 0xb3, 0x9f, 0x56, 0x51, 0xc4, 0xfa, 0xdb, 0x9c,
+	0x49, 0x79, 0xcf, 0x85, 0x6f, 0x8a, 0xe9, 0xba, 0x45, 0xac, 0xb9, 0x04, 0x8f, 0xf2, 0x72, 0xcc,
+	0x5a, 0x2b, 0xf7, 0x3e, 0x0c, 0x04, 0x53, 0xeb, 0x94, 0xcb, 0x5a, 0x69, 0x54, 0x5b, 0xa1, 0xff,
+	0x43, 0xff, 0x53, 0xc2, 0x15, 0xab, 0xc3, 0x96, 0x93, 0x51, 0xde, 0xd7, 0xa5, 0xc6, 0xa1, 0x78,
+	0x58, 0x83, 0xd3, 0x5d, 0xe5, 0xaf, 0x4f, 0xaf, 0x57, 0xc9, 0x7c, 0xf0, 0xbd, 0x0b, 0x5b, 0x17,
+	0x99, 0xc7, 0x4d, 0x99, 0xa7, 0xb8, 0x78, 0x20, 0x87, 0x60, 0x4f, 0x04, 0x32, 0x85, 0x64, 0x77,
+	0xbf, 0xf0, 0xc3, 0x8a, 0xf9, 0x8d, 0x86, 0xd5, 0xed, 0xd2, 0xbe, 0xe8, 0x06, 0x39, 0x06, 0xfb,
+	0x02, 0x23, 0x54, 0x48, 0x76, 0xca, 0x33, 0xa5, 0x7d, 0x8d, 0x7e, 0x2f, 0x77, 0x97, 0x4c, 0x88,
+	0x6e, 0x90, 0x23, 0xe8, 0x4e, 0x51, 0x79, 0x37, 0x33, 0x1e, 0xc8, 0x2a, 0xbe, 0x9c, 0xff, 0xd1,
+	0xee, 0xd2, 0x6e, 0x81, 0x3d, 0x03, 0x28, 0xcc, 0x42, 0x92, 0x51, 0x79, 0x6c, 0xd9, 0x42, 0x46,
+	0xdb, 0x0b, 0x14, 0x7a, 0x84, 0xe9, 0xc6, 0x3f, 0x16, 0x39, 0x03, 0xdb, 0xc5, 0x88, 0x33, 0x9f,
+	0x38, 0x95, 0x03, 0x0b, 0xb6, 0x5a, 0x5f, 0xfd, 0x31, 0xd8, 0xe9, 0xa0, 0x91, 0xdf, 0xca, 0x63,
+	0x0b, 0xd3, 0x5e, 0xa3, 0xdb, 0x04, 0xfa, 0xa9, 0x6e, 0x19, 0xc5, 0x70, 0x99, 0xe2, 0x29, 0x15,
+	0xbc, 0x86, 0x9e, 0xd1, 0x6f, 0x0d, 0xc7, 0xee, 0xf2, 0xbe, 0x71, 0x06, 0x23, 0xc2, 0x29, 0xb4,
+	0x33, 0x27, 0xa8, 0xaa, 0xb0, 0x68, 0x0e, 0x35, 0x5d, 0x1c, 0x42, 0xcb, 0x38, 0x41, 0x35, 0x75,
+	0xd5, 0x1a, 0x6a, 0xa0, 0x97, 0x30, 0x70, 0xb9, 0x62, 0x0a, 0x3f, 0xe6, 0xf3, 0xf6, 0x47, 0x85,
+	0x63, 0x79, 0xb0, 0xeb, 0x75, 0x98, 0x02, 0xa4, 0xef, 0xf7, 0x4a, 0xbf, 0xfa, 0x17, 0xf1, 0xa4,
+	0x97, 0xf2, 0x42, 0x9e, 0x53, 0x68, 0x67, 0x2e, 0x51, 0x55, 0x75, 0xd1, 0x38, 0x6a, 0xa4, 0x39,
+	0x82, 0xd6, 0x9b, 0xaf, 0xa1, 0xa7, 0x9e, 0x33, 0x52, 0xaf, 0xa0, 0x75, 0x25, 0x8d, 0xf1, 0xae,
+	0xc4, 0x3e, 0x9e, 0xf4, 0x1c, 0x7a, 0x57, 0x73, 0x9f, 0x29, 0x34, 0x0e, 0x55, 0xbd, 0xd0, 0xaa,
+	0x65, 0xd5, 0x27, 0x3f, 0x84, 0x96, 0xf1, 0xa9, 0x45, 0x74, 0x69, 0x5c, 0x8f, 0xa7, 0xbf, 0xb6,
+	0xcd, 0xef, 0xd7, 0xbf, 0x3f, 0x07, 0x00, 0x65, 0x3d, 0xb3, 0xb1, 0x90, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -381,47 +1008,233 @@ type DatabaseFactoryClient interface {
 	Create(ctx context.Context, in *RequestBody, opts ...grpc.CallOption) (*ResponseBody, error)
 	Delete(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*GenericResponse, error)
 	FetchLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (*LogResponse, error)
+	FollowLogs(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (DatabaseFactory_FollowLogsClient, error)
 	Reload(ctx context.Context, in *LanguageHolder, opts ...grpc.CallOption) (*GenericResponse, error)
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*ResponseBody, error)
+	DeleteBackup(ctx context.Context, in *BackupHolder, opts ...grpc.CallOption) (*GenericResponse, error)
+	FetchBackup(ctx context.Context, in *BackupHolder, opts ...grpc.CallOption) (DatabaseFactory_FetchBackupClient, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*ResponseBody, error)
+	Clone(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*ResponseBody, error)
+	RotatePassword(ctx context.Context, in *CredentialRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	CreateUser(ctx context.Context, in *CredentialRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	DeleteUser(ctx context.Context, in *CredentialRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	Migrate(ctx context.Context, in *MigrateRequest, opts ...grpc.CallOption) (*ResponseBody, error)
+	Evict(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*GenericResponse, error)
+	Usage(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*ResponseBody, error)
+	UpdateQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*ResponseBody, error)
+}
+
+type databaseFactoryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDatabaseFactoryClient(cc *grpc.ClientConn) DatabaseFactoryClient {
+	return &databaseFactoryClient{cc}
+}
+
+func (c *databaseFactoryClient) Create(ctx context.Context, in *RequestBody, opts ...grpc.CallOption) (*ResponseBody, error) {
+	out := new(ResponseBody)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) Delete(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) FetchLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (*LogResponse, error) {
+	out := new(LogResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/FetchLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) FollowLogs(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (DatabaseFactory_FollowLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DatabaseFactory_serviceDesc.Streams[0], "/database.DatabaseFactory/FollowLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &databaseFactoryFollowLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DatabaseFactory_FollowLogsClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type databaseFactoryFollowLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *databaseFactoryFollowLogsClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *databaseFactoryClient) Reload(ctx context.Context, in *LanguageHolder, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Reload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*ResponseBody, error) {
+	out := new(ResponseBody)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Backup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) DeleteBackup(ctx context.Context, in *BackupHolder, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/DeleteBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) FetchBackup(ctx context.Context, in *BackupHolder, opts ...grpc.CallOption) (DatabaseFactory_FetchBackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DatabaseFactory_serviceDesc.Streams[1], "/database.DatabaseFactory/FetchBackup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &databaseFactoryFetchBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DatabaseFactory_FetchBackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type databaseFactoryFetchBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *databaseFactoryFetchBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *databaseFactoryClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*ResponseBody, error) {
+	out := new(ResponseBody)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) Clone(ctx context.Context, in *CloneRequest, opts ...grpc.CallOption) (*ResponseBody, error) {
+	out := new(ResponseBody)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Clone", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) RotatePassword(ctx context.Context, in *CredentialRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/RotatePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type databaseFactoryClient struct {
-	cc *grpc.ClientConn
+func (c *databaseFactoryClient) CreateUser(ctx context.Context, in *CredentialRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func NewDatabaseFactoryClient(cc *grpc.ClientConn) DatabaseFactoryClient {
-	return &databaseFactoryClient{cc}
+func (c *databaseFactoryClient) DeleteUser(ctx context.Context, in *CredentialRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *databaseFactoryClient) Create(ctx context.Context, in *RequestBody, opts ...grpc.CallOption) (*ResponseBody, error) {
+func (c *databaseFactoryClient) Migrate(ctx context.Context, in *MigrateRequest, opts ...grpc.CallOption) (*ResponseBody, error) {
 	out := new(ResponseBody)
-	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Create", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Migrate", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *databaseFactoryClient) Delete(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*GenericResponse, error) {
+func (c *databaseFactoryClient) Evict(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*GenericResponse, error) {
 	out := new(GenericResponse)
-	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Delete", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Evict", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *databaseFactoryClient) FetchLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (*LogResponse, error) {
-	out := new(LogResponse)
-	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/FetchLogs", in, out, opts...)
+func (c *databaseFactoryClient) Usage(ctx context.Context, in *NameHolder, opts ...grpc.CallOption) (*ResponseBody, error) {
+	out := new(ResponseBody)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Usage", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *databaseFactoryClient) Reload(ctx context.Context, in *LanguageHolder, opts ...grpc.CallOption) (*GenericResponse, error) {
+func (c *databaseFactoryClient) UpdateQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
 	out := new(GenericResponse)
-	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Reload", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/UpdateQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseFactoryClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*ResponseBody, error) {
+	out := new(ResponseBody)
+	err := c.cc.Invoke(ctx, "/database.DatabaseFactory/Query", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -433,7 +1246,21 @@ type DatabaseFactoryServer interface {
 	Create(context.Context, *RequestBody) (*ResponseBody, error)
 	Delete(context.Context, *NameHolder) (*GenericResponse, error)
 	FetchLogs(context.Context, *LogRequest) (*LogResponse, error)
+	FollowLogs(*FollowLogRequest, DatabaseFactory_FollowLogsServer) error
 	Reload(context.Context, *LanguageHolder) (*GenericResponse, error)
+	Backup(context.Context, *BackupRequest) (*ResponseBody, error)
+	DeleteBackup(context.Context, *BackupHolder) (*GenericResponse, error)
+	FetchBackup(*BackupHolder, DatabaseFactory_FetchBackupServer) error
+	Restore(context.Context, *RestoreRequest) (*ResponseBody, error)
+	Clone(context.Context, *CloneRequest) (*ResponseBody, error)
+	RotatePassword(context.Context, *CredentialRequest) (*GenericResponse, error)
+	CreateUser(context.Context, *CredentialRequest) (*GenericResponse, error)
+	DeleteUser(context.Context, *CredentialRequest) (*GenericResponse, error)
+	Migrate(context.Context, *MigrateRequest) (*ResponseBody, error)
+	Evict(context.Context, *NameHolder) (*GenericResponse, error)
+	Usage(context.Context, *NameHolder) (*ResponseBody, error)
+	UpdateQuota(context.Context, *QuotaRequest) (*GenericResponse, error)
+	Query(context.Context, *QueryRequest) (*ResponseBody, error)
 }
 
 // UnimplementedDatabaseFactoryServer can be embedded to have forward compatible implementations.
@@ -449,9 +1276,51 @@ func (*UnimplementedDatabaseFactoryServer) Delete(ctx context.Context, req *Name
 func (*UnimplementedDatabaseFactoryServer) FetchLogs(ctx context.Context, req *LogRequest) (*LogResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchLogs not implemented")
 }
+func (*UnimplementedDatabaseFactoryServer) FollowLogs(req *FollowLogRequest, srv DatabaseFactory_FollowLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method FollowLogs not implemented")
+}
 func (*UnimplementedDatabaseFactoryServer) Reload(ctx context.Context, req *LanguageHolder) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
 }
+func (*UnimplementedDatabaseFactoryServer) Backup(ctx context.Context, req *BackupRequest) (*ResponseBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) DeleteBackup(ctx context.Context, req *BackupHolder) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBackup not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) FetchBackup(req *BackupHolder, srv DatabaseFactory_FetchBackupServer) error {
+	return status.Errorf(codes.Unimplemented, "method FetchBackup not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) Restore(ctx context.Context, req *RestoreRequest) (*ResponseBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) Clone(ctx context.Context, req *CloneRequest) (*ResponseBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clone not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) RotatePassword(ctx context.Context, req *CredentialRequest) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotatePassword not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) CreateUser(ctx context.Context, req *CredentialRequest) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) DeleteUser(ctx context.Context, req *CredentialRequest) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) Migrate(ctx context.Context, req *MigrateRequest) (*ResponseBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Migrate not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) Evict(ctx context.Context, req *NameHolder) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evict not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) Usage(ctx context.Context, req *NameHolder) (*ResponseBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Usage not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) UpdateQuota(ctx context.Context, req *QuotaRequest) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateQuota not implemented")
+}
+func (*UnimplementedDatabaseFactoryServer) Query(ctx context.Context, req *QueryRequest) (*ResponseBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
 
 func RegisterDatabaseFactoryServer(s *grpc.Server, srv DatabaseFactoryServer) {
 	s.RegisterService(&_DatabaseFactory_serviceDesc, srv)
@@ -511,6 +1380,27 @@ func _DatabaseFactory_FetchLogs_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DatabaseFactory_FollowLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FollowLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DatabaseFactoryServer).FollowLogs(m, &databaseFactoryFollowLogsServer{stream})
+}
+
+type DatabaseFactory_FollowLogsServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type databaseFactoryFollowLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *databaseFactoryFollowLogsServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _DatabaseFactory_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LanguageHolder)
 	if err := dec(in); err != nil {
@@ -529,6 +1419,243 @@ func _DatabaseFactory_Reload_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DatabaseFactory_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Backup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Backup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Backup(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_DeleteBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupHolder)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).DeleteBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/DeleteBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).DeleteBackup(ctx, req.(*BackupHolder))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_FetchBackup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupHolder)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DatabaseFactoryServer).FetchBackup(m, &databaseFactoryFetchBackupServer{stream})
+}
+
+type DatabaseFactory_FetchBackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type databaseFactoryFetchBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *databaseFactoryFetchBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DatabaseFactory_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_Clone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Clone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Clone",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Clone(ctx, req.(*CloneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_RotatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CredentialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).RotatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/RotatePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).RotatePassword(ctx, req.(*CredentialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CredentialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).CreateUser(ctx, req.(*CredentialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CredentialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).DeleteUser(ctx, req.(*CredentialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_Migrate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Migrate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Migrate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Migrate(ctx, req.(*MigrateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_Evict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameHolder)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Evict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Evict",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Evict(ctx, req.(*NameHolder))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameHolder)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Usage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Usage(ctx, req.(*NameHolder))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_UpdateQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).UpdateQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/UpdateQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).UpdateQuota(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DatabaseFactory_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseFactoryServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/database.DatabaseFactory/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseFactoryServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _DatabaseFactory_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "database.DatabaseFactory",
 	HandlerType: (*DatabaseFactoryServer)(nil),
@@ -549,7 +1676,66 @@ var _DatabaseFactory_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Reload",
 			Handler:    _DatabaseFactory_Reload_Handler,
 		},
+		{
+			MethodName: "Backup",
+			Handler:    _DatabaseFactory_Backup_Handler,
+		},
+		{
+			MethodName: "DeleteBackup",
+			Handler:    _DatabaseFactory_DeleteBackup_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _DatabaseFactory_Restore_Handler,
+		},
+		{
+			MethodName: "Clone",
+			Handler:    _DatabaseFactory_Clone_Handler,
+		},
+		{
+			MethodName: "RotatePassword",
+			Handler:    _DatabaseFactory_RotatePassword_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _DatabaseFactory_CreateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _DatabaseFactory_DeleteUser_Handler,
+		},
+		{
+			MethodName: "Migrate",
+			Handler:    _DatabaseFactory_Migrate_Handler,
+		},
+		{
+			MethodName: "Evict",
+			Handler:    _DatabaseFactory_Evict_Handler,
+		},
+		{
+			MethodName: "Usage",
+			Handler:    _DatabaseFactory_Usage_Handler,
+		},
+		{
+			MethodName: "UpdateQuota",
+			Handler:    _DatabaseFactory_UpdateQuota_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _DatabaseFactory_Query_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FollowLogs",
+			Handler:       _DatabaseFactory_FollowLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FetchBackup",
+			Handler:       _DatabaseFactory_FetchBackup_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "database.proto",
 }
@@ -0,0 +1,187 @@
+package logship
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// Number of lines buffered for a drain before the new lines are dropped
+	drainBufferSize = 10000
+
+	// Maximum number of lines delivered to a drain at once
+	drainBatchSize = 200
+
+	// Time Interval after which the pending lines are delivered to a drain
+	drainFlushInterval = time.Second
+
+	// Number of attempts made for delivering a batch before it is dropped
+	drainAttempts = 5
+)
+
+// drain buffers the log lines of an application and delivers them to a sink
+type drain struct {
+	config  *types.LogDrain
+	sender  sender
+	queue   chan *types.LogEntry
+	done    chan struct{}
+	dropped uint64
+}
+
+// enqueue buffers a batch of lines for delivery
+// The lines which do not fit in a full buffer are dropped and counted so that a slow or dead
+// sink does not hold back the collector and the other handlers of the container's logs
+func (d *drain) enqueue(batch []*types.LogEntry) {
+	for i, entry := range batch {
+		select {
+		case d.queue <- entry:
+		case <-d.done:
+			return
+		default:
+			atomic.AddUint64(&d.dropped, uint64(len(batch)-i))
+			return
+		}
+	}
+}
+
+// deliver sends a batch to the sink, retrying with an exponential backoff on failure
+func (d *drain) deliver(batch []*types.LogEntry) {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := d.sender.send(batch)
+		if err == nil {
+			return
+		}
+		if attempt == drainAttempts {
+			utils.LogError("LogShip-Drain-1", err)
+			atomic.AddUint64(&d.dropped, uint64(len(batch)))
+			return
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// run delivers the buffered lines in batches until the drain is stopped
+func (d *drain) run() {
+	defer d.sender.close()
+	ticker := time.NewTicker(drainFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*types.LogEntry, 0, drainBatchSize)
+	for {
+		select {
+		case entry := <-d.queue:
+			batch = append(batch, entry)
+			if len(batch) < drainBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if dropped := atomic.SwapUint64(&d.dropped, 0); dropped > 0 {
+				utils.LogInfo("LogShip-Drain-2", "Dropped %d lines of application %s for drain %s", dropped, d.config.GetApp(), d.config.GetID())
+			}
+			if len(batch) == 0 {
+				continue
+			}
+		case <-d.done:
+			return
+		}
+		d.deliver(batch)
+		batch = make([]*types.LogEntry, 0, drainBatchSize)
+	}
+}
+
+// stop stops delivering lines to the sink
+func (d *drain) stop() {
+	close(d.done)
+}
+
+// Forwarder forwards the logs of applications to their drains
+type Forwarder struct {
+	mutex  sync.RWMutex
+	drains map[string]*drain
+	apps   map[string][]*drain
+}
+
+// NewForwarder returns a new forwarder without any drains
+func NewForwarder() *Forwarder {
+	return &Forwarder{
+		drains: make(map[string]*drain),
+		apps:   make(map[string][]*drain),
+	}
+}
+
+// Refresh synchronizes the drains being served with the ones configured for the given applications
+func (f *Forwarder) Refresh(apps []string) {
+	configs, err := mongo.FetchDrains(types.M{
+		mongo.AppKey: types.M{"$in": apps},
+	})
+	if err != nil {
+		utils.LogError("LogShip-Drain-3", err)
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	desired := make(map[string]*types.LogDrain)
+	for _, config := range configs {
+		desired[config.GetID()] = config
+	}
+	for id, d := range f.drains {
+		if _, ok := desired[id]; !ok {
+			d.stop()
+			delete(f.drains, id)
+		}
+	}
+	for id, config := range desired {
+		if _, ok := f.drains[id]; ok {
+			continue
+		}
+		s, err := newSender(config)
+		if err != nil {
+			utils.LogError("LogShip-Drain-4", err)
+			continue
+		}
+		d := &drain{
+			config: config,
+			sender: s,
+			queue:  make(chan *types.LogEntry, drainBufferSize),
+			done:   make(chan struct{}),
+		}
+		f.drains[id] = d
+		go d.run()
+	}
+
+	f.apps = make(map[string][]*drain)
+	for _, d := range f.drains {
+		f.apps[d.config.GetApp()] = append(f.apps[d.config.GetApp()], d)
+	}
+}
+
+// HasDrains checks whether an application has any drains
+func (f *Forwarder) HasDrains(app string) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return len(f.apps[app]) > 0
+}
+
+// Handle is a handler forwarding the batches of log lines to the drains of the container's application
+// The lock is released before enqueueing so that a full drain does not hold back refreshes
+func (f *Forwarder) Handle(container Container, batch []*types.LogEntry) {
+	f.mutex.RLock()
+	drains := f.apps[container.Name]
+	f.mutex.RUnlock()
+	for _, d := range drains {
+		d.enqueue(batch)
+	}
+}
@@ -0,0 +1,259 @@
+package logship
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	syslogScheme    = "syslog"
+	syslogTLSScheme = "syslog+tls"
+
+	// Timeout for connecting and writing to a drain's sink
+	sendTimeout = 10 * time.Second
+
+	// Syslog facility of the messages i.e user-level messages
+	syslogFacility = 1
+
+	// Syslog severities of the lines written to stdout and stderr
+	syslogInfo  = 6
+	syslogError = 3
+)
+
+// sender delivers batches of log lines to the sink of a drain
+type sender interface {
+	send(batch []*types.LogEntry) error
+	close()
+}
+
+// reservedHeaders holds the headers of the requests to HTTP sinks which cannot be set by a drain
+var reservedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Upgrade":           true,
+	"Te":                true,
+	"Trailer":           true,
+}
+
+// ValidateDrain checks whether the sink of a log drain is supported
+// The sink must resolve to public addresses or to the allowed networks so that drains cannot reach the internal network
+func ValidateDrain(drain *types.LogDrain) error {
+	sink, err := url.Parse(drain.GetURL())
+	if err != nil {
+		return fmt.Errorf("URL of the drain is invalid: %s", err.Error())
+	}
+	if sink.Hostname() == "" {
+		return fmt.Errorf("URL of the drain must have a host")
+	}
+	if err := utils.ValidatePublicHost(sink.Hostname()); err != nil {
+		return fmt.Errorf("URL of the drain is not allowed: %s", err.Error())
+	}
+	for key := range drain.Headers {
+		if reservedHeaders[http.CanonicalHeaderKey(key)] {
+			return fmt.Errorf("header %s of the drain cannot be set", key)
+		}
+	}
+	switch drain.GetType() {
+	case types.SyslogDrain:
+		if sink.Scheme != syslogScheme && sink.Scheme != syslogTLSScheme {
+			return fmt.Errorf("URL of a syslog drain must have the scheme %s or %s", syslogScheme, syslogTLSScheme)
+		}
+		if sink.Port() == "" {
+			return fmt.Errorf("URL of a syslog drain must have a port")
+		}
+	case types.HTTPSDrain:
+		if sink.Scheme != "https" {
+			return fmt.Errorf("URL of a %s drain must have the scheme https", drain.GetType())
+		}
+	case types.LokiDrain:
+		if sink.Scheme != "https" && sink.Scheme != "http" {
+			return fmt.Errorf("URL of a %s drain must have the scheme https or http", drain.GetType())
+		}
+	default:
+		return fmt.Errorf("drain type `%s` is not supported, must be one of %s, %s or %s",
+			drain.GetType(), types.SyslogDrain, types.HTTPSDrain, types.LokiDrain)
+	}
+	return nil
+}
+
+// newSender returns a sender for the sink of a log drain
+func newSender(drain *types.LogDrain) (sender, error) {
+	if err := ValidateDrain(drain); err != nil {
+		return nil, err
+	}
+	sink, _ := url.Parse(drain.GetURL())
+	switch drain.GetType() {
+	case types.SyslogDrain:
+		return &syslogSender{
+			address: sink.Host,
+			tls:     sink.Scheme == syslogTLSScheme,
+		}, nil
+	case types.LokiDrain:
+		return &httpSender{drain: drain, encode: encodeLokiBatch}, nil
+	default:
+		return &httpSender{drain: drain, encode: encodeJSONBatch}, nil
+	}
+}
+
+// syslogSender delivers log lines as RFC 5424 messages framed by octet counting over TCP or TLS
+type syslogSender struct {
+	address string
+	tls     bool
+	conn    net.Conn
+}
+
+// dial connects to the syslog server
+func (s *syslogSender) dial() (net.Conn, error) {
+	dialer := utils.NewPublicDialer(sendTimeout)
+	if s.tls {
+		host, _, _ := net.SplitHostPort(s.address)
+		return tls.DialWithDialer(dialer, "tcp", s.address, &tls.Config{ServerName: host})
+	}
+	return dialer.Dial("tcp", s.address)
+}
+
+// formatSyslog formats a log line as a RFC 5424 syslog message
+func formatSyslog(entry *types.LogEntry) string {
+	severity := syslogInfo
+	if entry.Stream == "stderr" {
+		severity = syslogError
+	}
+	appName := entry.Name
+	if len(appName) > 48 {
+		appName = appName[:48]
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		syslogFacility*8+severity,
+		entry.Timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		entry.HostIP,
+		appName,
+		entry.Stream,
+		entry.Message,
+	)
+}
+
+func (s *syslogSender) send(batch []*types.LogEntry) error {
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	buf := &bytes.Buffer{}
+	for _, entry := range batch {
+		msg := formatSyslog(entry)
+		fmt.Fprintf(buf, "%d %s", len(msg), msg)
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(sendTimeout))
+	if _, err := s.conn.Write(buf.Bytes()); err != nil {
+		// Reconnect on the next attempt
+		s.close()
+		return err
+	}
+	return nil
+}
+
+func (s *syslogSender) close() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// httpSender posts batches of log lines to an HTTP endpoint
+type httpSender struct {
+	drain  *types.LogDrain
+	encode func(drain *types.LogDrain, batch []*types.LogEntry) ([]byte, error)
+}
+
+// drainClient refuses to connect to internal addresses outside the allowed networks even if the sink resolves to one after validation
+var drainClient = utils.NewPublicHTTPClient(sendTimeout)
+
+func (s *httpSender) send(batch []*types.LogEntry) error {
+	body, err := s.encode(s.drain, batch)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.drain.GetURL(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.drain.Headers {
+		req.Header.Set(key, value)
+	}
+	res, err := drainClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("drain %s responded with status %d", s.drain.GetID(), res.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSender) close() {}
+
+// encodeJSONBatch encodes a batch of log lines as a JSON array
+func encodeJSONBatch(drain *types.LogDrain, batch []*types.LogEntry) ([]byte, error) {
+	return json.Marshal(batch)
+}
+
+// lokiStream is a stream of log lines sharing the same labels in the Loki push API
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encodeLokiBatch encodes a batch of log lines as a request body of the Loki push API
+// The lines are grouped into streams labelled with the application, node and output stream
+func encodeLokiBatch(drain *types.LogDrain, batch []*types.LogEntry) ([]byte, error) {
+	streams := make(map[string]*lokiStream)
+	order := []string{}
+	for _, entry := range batch {
+		key := entry.HostIP + "/" + entry.Stream
+		stream, ok := streams[key]
+		if !ok {
+			labels := map[string]string{
+				"app":    entry.Name,
+				"host":   entry.HostIP,
+				"stream": entry.Stream,
+			}
+			for label, value := range drain.Labels {
+				labels[label] = value
+			}
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			order = append(order, key)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+			entry.Message,
+		})
+	}
+	body := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range order {
+		body.Streams = append(body.Streams, streams[key])
+	}
+	return json.Marshal(body)
+}
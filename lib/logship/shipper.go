@@ -0,0 +1,221 @@
+package logship
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	defaultBatchSize     = 500
+	defaultFlushInterval = 5 * time.Second
+)
+
+// Container is a container whose logs are shipped
+type Container struct {
+	Name         string
+	InstanceType string
+}
+
+// Source returns the containers in the current node whose logs are to be shipped
+type Source func() []Container
+
+// Handler receives the batches of lines shipped from a container
+type Handler func(container Container, batch []*types.LogEntry)
+
+// tail is the shipping of the logs of a single container
+type tail struct {
+	cancel context.CancelFunc
+}
+
+// shipper follows the logs of the containers returned by a source and ships them to the handlers
+type shipper struct {
+	source   Source
+	handlers []Handler
+	mutex    sync.Mutex
+	tails    map[string]*tail
+}
+
+// reconcile starts following the logs of new containers and stops following those which are gone
+func (s *shipper) reconcile() {
+	desired := make(map[string]Container)
+	for _, container := range s.source() {
+		desired[container.Name] = container
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for name, t := range s.tails {
+		if _, ok := desired[name]; !ok {
+			t.cancel()
+			delete(s.tails, name)
+		}
+	}
+	for name, container := range desired {
+		if _, ok := s.tails[name]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		t := &tail{cancel: cancel}
+		s.tails[name] = t
+		go s.follow(ctx, container, t)
+	}
+}
+
+// release removes a tail once it stops so that it is started again by the next reconciliation
+func (s *shipper) release(name string, t *tail) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.tails[name] == t {
+		delete(s.tails, name)
+	}
+	t.cancel()
+}
+
+// lastShipped returns the timestamp and the position among the lines sharing it of the latest
+// line shipped from a container in the current node, the position is -1 if nothing was shipped
+func lastShipped(container Container) (time.Time, int) {
+	entry, err := mongo.FetchLastLog(types.M{
+		mongo.NameKey:         container.Name,
+		mongo.InstanceTypeKey: container.InstanceType,
+		mongo.HostIPKey:       utils.HostIP,
+	})
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			utils.LogError("LogShip-Shipper-4", err)
+		}
+		return time.Time{}, -1
+	}
+	// Lines shipped before the exact timestamps were stored are only known up to the millisecond
+	if entry.TimestampNano == 0 {
+		return entry.Timestamp.Add(time.Millisecond - time.Nanosecond), math.MaxInt32
+	}
+	return time.Unix(0, entry.TimestampNano), entry.Sequence
+}
+
+// Store is a handler inserting the batches of log lines into MongoDB
+func Store(container Container, batch []*types.LogEntry) {
+	docs := make([]interface{}, len(batch))
+	for i, entry := range batch {
+		docs[i] = entry
+	}
+	if _, err := mongo.BulkRegisterLogs(docs); err != nil {
+		utils.Log("LogShip-Shipper-1", "Failed to ship container logs", utils.ErrorTAG)
+		utils.LogError("LogShip-Shipper-2", err)
+	}
+}
+
+// flush hands over a batch of log lines to the handlers
+func (s *shipper) flush(container Container, batch []*types.LogEntry) {
+	if len(batch) == 0 {
+		return
+	}
+	for _, handler := range s.handlers {
+		handler(container, batch)
+	}
+}
+
+// follow ships the logs of a container in batches until the context is done or the container stops
+func (s *shipper) follow(ctx context.Context, container Container, t *tail) {
+	defer s.release(container.Name, t)
+
+	config := configs.ServiceConfig.LogShipping
+	retention := config.RetentionWindow()
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	flushInterval := config.FlushInterval * time.Second
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+
+	// Resume from the latest line stored in MongoDB so that nothing is shipped twice across restarts
+	// and start from the current time if the lines are not being stored
+	filter := &docker.LogFilter{Tail: "0"}
+	last, lastSequence := time.Time{}, -1
+	if config.PlugIn {
+		last, lastSequence = lastShipped(container)
+		filter = &docker.LogFilter{Tail: "all", Since: last}
+	}
+	lines, err := docker.FollowLogs(ctx, container.Name, filter)
+	if err != nil {
+		utils.LogError("LogShip-Shipper-3", err)
+		return
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	// Lines sharing a timestamp are told apart by their position among each other
+	var current time.Time
+	sequence := 0
+
+	batch := make([]*types.LogEntry, 0, batchSize)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				s.flush(container, batch)
+				return
+			}
+			timestamp, message, err := docker.SplitTimestamp(line.Line)
+			if err != nil {
+				continue
+			}
+			if timestamp.Equal(current) {
+				sequence++
+			} else {
+				current, sequence = timestamp, 0
+			}
+			if timestamp.Before(last) || (timestamp.Equal(last) && sequence <= lastSequence) {
+				continue
+			}
+			batch = append(batch, &types.LogEntry{
+				Name:          container.Name,
+				InstanceType:  container.InstanceType,
+				HostIP:        utils.HostIP,
+				Stream:        line.Stream,
+				Message:       message,
+				Timestamp:     timestamp,
+				TimestampNano: timestamp.UnixNano(),
+				Sequence:      sequence,
+				ExpiresAt:     timestamp.Add(retention),
+			})
+			if len(batch) >= batchSize {
+				s.flush(container, batch)
+				batch = make([]*types.LogEntry, 0, batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.flush(container, batch)
+				batch = make([]*types.LogEntry, 0, batchSize)
+			}
+		}
+	}
+}
+
+// Schedule ships the logs of the containers returned by the source to the handlers
+// and checks the source for new containers at every flush interval
+func Schedule(source Source, handlers ...Handler) {
+	s := &shipper{
+		source:   source,
+		handlers: handlers,
+		tails:    make(map[string]*tail),
+	}
+	interval := configs.ServiceConfig.LogShipping.FlushInterval * time.Second
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	s.reconcile()
+	scheduler := utils.NewScheduler(interval, s.reconcile)
+	scheduler.Run()
+}
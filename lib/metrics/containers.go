@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	defaultContainerInterval = 15 * time.Second
+
+	// Number of containers whose statistics are fetched from docker at once
+	containerStatsWorkers = 8
+)
+
+// Container is a container whose statistics are exported
+type Container struct {
+	Name         string
+	InstanceType string
+}
+
+// ContainerSource returns the containers in the current node whose statistics are exported
+type ContainerSource func() []Container
+
+// containerSample is the last statistics read from a container
+type containerSample struct {
+	container Container
+	stats     *types.Stats
+}
+
+func newContainerDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "container", name),
+		help,
+		[]string{"container", "instance_type"},
+		nil,
+	)
+}
+
+var (
+	cpuDesc          = newContainerDesc("cpu_usage_seconds_total", "Cumulative CPU time consumed by the container")
+	memoryUsageDesc  = newContainerDesc("memory_usage_bytes", "Memory used by the container")
+	memoryLimitDesc  = newContainerDesc("memory_limit_bytes", "Memory limit of the container")
+	pidsDesc         = newContainerDesc("pids", "Number of processes running in the container")
+	networkRxDesc    = newContainerDesc("network_receive_bytes_total", "Bytes received by the container over the network")
+	networkTxDesc    = newContainerDesc("network_transmit_bytes_total", "Bytes transmitted by the container over the network")
+	blockIOReadDesc  = newContainerDesc("blkio_read_bytes_total", "Bytes read by the container from block devices")
+	blockIOWriteDesc = newContainerDesc("blkio_write_bytes_total", "Bytes written by the container to block devices")
+)
+
+// containerCollector exports the statistics of the containers returned by the registered sources
+// Fetching the statistics from docker is slow hence they are refreshed in the background
+// and the scrapes are served from the last refresh
+type containerCollector struct {
+	sourcesMutex sync.Mutex
+	sources      []ContainerSource
+
+	samplesMutex sync.RWMutex
+	samples      []containerSample
+}
+
+var containers = &containerCollector{}
+
+func init() {
+	prometheus.MustRegister(containers)
+}
+
+// RegisterContainerSource adds a source of containers whose statistics are exported
+func RegisterContainerSource(source ContainerSource) {
+	containers.sourcesMutex.Lock()
+	defer containers.sourcesMutex.Unlock()
+	containers.sources = append(containers.sources, source)
+}
+
+// list returns the containers returned by all the sources
+func (cc *containerCollector) list() []Container {
+	cc.sourcesMutex.Lock()
+	sources := append([]ContainerSource{}, cc.sources...)
+	cc.sourcesMutex.Unlock()
+
+	seen := make(map[string]bool)
+	list := make([]Container, 0)
+	for _, source := range sources {
+		for _, container := range source() {
+			if seen[container.Name] {
+				continue
+			}
+			seen[container.Name] = true
+			list = append(list, container)
+		}
+	}
+	return list
+}
+
+// refresh fetches the statistics of all the containers from docker
+func (cc *containerCollector) refresh() {
+	list := cc.list()
+	samples := make([]containerSample, len(list))
+	fetched := make([]bool, len(list))
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for i := 0; i < containerStatsWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				stats, err := docker.ContainerStats(list[index].Name)
+				if err != nil {
+					utils.LogError("Metrics-Containers-1", err)
+					continue
+				}
+				samples[index] = containerSample{container: list[index], stats: stats}
+				fetched[index] = true
+			}
+		}()
+	}
+	for index := range list {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	refreshed := make([]containerSample, 0, len(samples))
+	for index, sample := range samples {
+		if fetched[index] {
+			refreshed = append(refreshed, sample)
+		}
+	}
+
+	cc.samplesMutex.Lock()
+	cc.samples = refreshed
+	cc.samplesMutex.Unlock()
+}
+
+// Describe implements the prometheus.Collector interface
+func (cc *containerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cpuDesc
+	ch <- memoryUsageDesc
+	ch <- memoryLimitDesc
+	ch <- pidsDesc
+	ch <- networkRxDesc
+	ch <- networkTxDesc
+	ch <- blockIOReadDesc
+	ch <- blockIOWriteDesc
+}
+
+// Collect implements the prometheus.Collector interface
+func (cc *containerCollector) Collect(ch chan<- prometheus.Metric) {
+	cc.samplesMutex.RLock()
+	defer cc.samplesMutex.RUnlock()
+
+	for _, sample := range cc.samples {
+		labels := []string{sample.container.Name, sample.container.InstanceType}
+		stats := sample.stats
+		rx, tx := stats.NetworkIO()
+		read, write := stats.BlockIO()
+
+		ch <- prometheus.MustNewConstMetric(cpuDesc, prometheus.CounterValue, stats.CPU.CPUUsage.TotalUsage/1e9, labels...)
+		ch <- prometheus.MustNewConstMetric(memoryUsageDesc, prometheus.GaugeValue, stats.Memory.Usage, labels...)
+		ch <- prometheus.MustNewConstMetric(memoryLimitDesc, prometheus.GaugeValue, stats.Memory.Limit, labels...)
+		ch <- prometheus.MustNewConstMetric(pidsDesc, prometheus.GaugeValue, stats.Pids.Current, labels...)
+		ch <- prometheus.MustNewConstMetric(networkRxDesc, prometheus.CounterValue, rx, labels...)
+		ch <- prometheus.MustNewConstMetric(networkTxDesc, prometheus.CounterValue, tx, labels...)
+		ch <- prometheus.MustNewConstMetric(blockIOReadDesc, prometheus.CounterValue, read, labels...)
+		ch <- prometheus.MustNewConstMetric(blockIOWriteDesc, prometheus.CounterValue, write, labels...)
+	}
+}
+
+// ScheduleContainerStats refreshes the statistics of the containers returned
+// by the registered sources at the given container interval
+func ScheduleContainerStats() {
+	interval := configs.ServiceConfig.Exporter.ContainerInterval * time.Second
+	if interval <= 0 {
+		interval = defaultContainerInterval
+	}
+	containers.refresh()
+	scheduler := utils.NewScheduler(interval, containers.refresh)
+	scheduler.RunAsync()
+}
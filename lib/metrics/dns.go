@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var dnsQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "gendns",
+	Name:      "queries_total",
+	Help:      "Number of DNS queries answered by GenDNS partitioned by query type and response code",
+}, []string{"qtype", "rcode"})
+
+// ObserveDNSQuery records a DNS query along with the reply sent for it
+func ObserveDNSQuery(query, reply *dns.Msg) {
+	qtype := "none"
+	if len(query.Question) > 0 {
+		qtype = dns.Type(query.Question[0].Qtype).String()
+	}
+	rcode, ok := dns.RcodeToString[reply.Rcode]
+	if !ok {
+		rcode = "UNKNOWN"
+	}
+	dnsQueries.WithLabelValues(qtype, rcode).Inc()
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcClientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_client",
+		Name:      "requests_total",
+		Help:      "Number of remote procedure calls made to AppMaker and DbMaker partitioned by method",
+	}, []string{"method"})
+
+	grpcClientErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_client",
+		Name:      "errors_total",
+		Help:      "Number of failed remote procedure calls made to AppMaker and DbMaker partitioned by method and status code",
+	}, []string{"method", "code"})
+)
+
+func observeGrpcError(method string, err error) {
+	grpcClientErrors.WithLabelValues(method, status.Code(err).String()).Inc()
+}
+
+// UnaryClientInterceptor records the count and the errors of unary remote procedure calls
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	grpcClientRequests.WithLabelValues(method).Inc()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		observeGrpcError(method, err)
+	}
+	return err
+}
+
+// StreamClientInterceptor records the count and the errors of streaming remote procedure calls
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	grpcClientRequests.WithLabelValues(method).Inc()
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		observeGrpcError(method, err)
+		return nil, err
+	}
+	return &clientStream{ClientStream: stream, method: method}, nil
+}
+
+// clientStream records the error which terminates a stream
+type clientStream struct {
+	grpc.ClientStream
+	method string
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil && err != io.EOF {
+		observeGrpcError(s.method, err)
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace is the prefix of all the metrics exported by Gasper
+const namespace = "gasper"
+
+// Handler returns a HTTP handler serving the metrics collected by the services
+// deployed in the current node in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
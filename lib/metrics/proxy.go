@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	proxyRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "genproxy",
+		Name:      "requests_total",
+		Help:      "Number of requests proxied by GenProxy partitioned by application and status code",
+	}, []string{"app", "code"})
+
+	proxyRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "genproxy",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests proxied by GenProxy partitioned by application",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"app"})
+)
+
+// ObserveProxyRequest records a request proxied to an application
+func ObserveProxyRequest(app string, code int, duration time.Duration) {
+	proxyRequests.WithLabelValues(app, strconv.Itoa(code)).Inc()
+	proxyRequestDuration.WithLabelValues(app).Observe(duration.Seconds())
+}
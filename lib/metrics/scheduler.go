@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sdslabs/gasper/lib/utils"
+)
+
+var (
+	schedulerLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "lag_seconds",
+		Help:      "Delay between the time a scheduled job was due and the time it started partitioned by job",
+		Buckets:   []float64{.001, .01, .1, .5, 1, 5, 15, 30, 60},
+	}, []string{"job"})
+
+	schedulerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "job_duration_seconds",
+		Help:      "Time taken by the runs of a scheduled job partitioned by job",
+		Buckets:   []float64{.01, .1, .5, 1, 5, 15, 30, 60, 120},
+	}, []string{"job"})
+)
+
+func init() {
+	utils.SchedulerObserver = func(job string, lag, duration time.Duration) {
+		schedulerLag.WithLabelValues(job).Observe(lag.Seconds())
+		schedulerDuration.WithLabelValues(job).Observe(duration.Seconds())
+	}
+}
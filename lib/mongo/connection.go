@@ -49,6 +49,7 @@ func setup() {
 	} else {
 		utils.LogInfo("Mongo-Connection-6", "MongoDB Connection Established")
 		setupAdmin()
+		setupIndexes()
 	}
 }
 
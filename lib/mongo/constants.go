@@ -21,6 +21,9 @@ const (
 	// AppInstance is app instance type name in the instances collection
 	AppInstance = "application"
 
+	// DBServerInstance is the instance type name of the database servers shared by databases
+	DBServerInstance = "database_server"
+
 	// InstanceCollection is the collection for all the instances
 	InstanceCollection = "instances"
 
@@ -30,6 +33,55 @@ const (
 	// MetricsCollection is the collection to hold the metrics of the instances
 	MetricsCollection = "metrics"
 
+	// FiveMinuteMetricsCollection is the collection to hold the 5-minute rollups of the metrics
+	FiveMinuteMetricsCollection = "metrics_5m"
+
+	// HourlyMetricsCollection is the collection to hold the hourly rollups of the metrics
+	HourlyMetricsCollection = "metrics_1h"
+
+	// SSHKeyCollection is the collection for all SSH public keys registered by users
+	SSHKeyCollection = "ssh_keys"
+
+	// SessionCollection is the collection for the audit information of all SSH sessions
+	SessionCollection = "sessions"
+
+	// SessionRecordingCollection is the collection holding the transcripts of SSH sessions
+	SessionRecordingCollection = "session_recordings"
+
+	// LogsCollection is the collection holding the logs shipped from the containers of the instances
+	LogsCollection = "logs"
+
+	// DrainCollection is the collection holding the log drains of applications
+	DrainCollection = "drains"
+
+	// AlertRuleCollection is the collection holding the alert rules of applications
+	AlertRuleCollection = "alert_rules"
+
+	// AlertCollection is the collection holding the history of alerts of applications
+	AlertCollection = "alerts"
+
+	// ChannelCollection is the collection holding the notification channels of users
+	ChannelCollection = "notification_channels"
+
+	// ProbeCollection is the collection holding the results of the HTTP probes sent to applications
+	ProbeCollection = "probes"
+
+	// BackupCollection is the collection holding the metadata of the backups of databases
+	BackupCollection = "backups"
+
+	// BackupScheduleCollection is the collection holding the backup schedules of databases
+	BackupScheduleCollection = "backup_schedules"
+
+	// RestoreCollection is the collection holding the restores of backups into databases
+	RestoreCollection = "restores"
+
+	// DatabaseUserCollection is the collection holding the additional users of databases
+	DatabaseUserCollection = "database_users"
+
+	// QueryAuditCollection is the collection holding the records of the queries run on databases
+	// through their query consoles
+	QueryAuditCollection = "query_audits"
+
 	// NameKey is the key holding the name of an instance
 	NameKey = "name"
 
@@ -51,6 +103,9 @@ const (
 	// PortKey is the key holding the port of the container in which a database server is deployed
 	PortKey = "port"
 
+	// DedicatedKey is the key denoting whether a database is deployed in its own container
+	DedicatedKey = "dedicated"
+
 	// EmailKey is the key holding the email of a user
 	EmailKey = "email"
 
@@ -60,17 +115,84 @@ const (
 	// PasswordKey is the key holding the password of a user/instance
 	PasswordKey = "password"
 
+	// RootPasswordKey is the key holding the password of the root user of the server of a dedicated database
+	RootPasswordKey = "root_password"
+
 	// AdminKey is the key denoting whether a user has superuser privileges or not
 	AdminKey = "admin"
 
 	// TimestampKey is the key holding the timestamp of when a metrics collection was inserted
 	TimestampKey = "timestamp"
 
+	// TimestampNanoKey is the key holding the timestamp of a log line with nanosecond precision
+	TimestampNanoKey = "timestamp_ns"
+
 	//GctlUUIDKey is the key holding a unique key for authentication of user by jwt
 	GctlUUIDKey = "gctl_uuid"
 
 	// DatetimeKey is the key holding the timestamp of when the instance was created
 	DatetimeKey = "datetime"
+
+	// FingerprintKey is the key holding the SHA256 fingerprint of a SSH public key
+	FingerprintKey = "fingerprint"
+
+	// AppsKey is the key holding the applications a SSH public key has been granted access to
+	AppsKey = "apps"
+
+	// SessionIDKey is the key holding the ID of a SSH session
+	SessionIDKey = "session_id"
+
+	// SequenceKey is the key holding the position of a chunk of a SSH session's transcript
+	// or of a log line among the lines of a container sharing its timestamp
+	SequenceKey = "seq"
+
+	// StartKey is the key holding the time at which a SSH session started
+	StartKey = "start"
+
+	// MessageKey is the key holding the message of a log line
+	MessageKey = "message"
+
+	// DrainIDKey is the key holding the ID of a log drain
+	DrainIDKey = "drain_id"
+
+	// AppKey is the key holding the name of the application a document belongs to
+	AppKey = "app"
+
+	// RuleIDKey is the key holding the ID of an alert rule
+	RuleIDKey = "rule_id"
+
+	// ChannelIDKey is the key holding the ID of a notification channel
+	ChannelIDKey = "channel_id"
+
+	// StateKey is the key holding the state of an alert rule
+	StateKey = "state"
+
+	// LastNotifiedKey is the key holding the time at which an alert rule was last notified
+	LastNotifiedKey = "last_notified"
+
+	// ExpiresAtKey is the key holding the time after which a document is removed by mongoDB
+	ExpiresAtKey = "expires_at"
+
+	// BackupIDKey is the key holding the ID of a backup
+	BackupIDKey = "backup_id"
+
+	// RestoreIDKey is the key holding the ID of a restore
+	RestoreIDKey = "restore_id"
+
+	// DatabaseKey is the key holding the name of the database of a backup or a backup schedule
+	DatabaseKey = "database"
+
+	// StatusKey is the key holding the status of a backup
+	StatusKey = "status"
+
+	// TriggerKey is the key holding what triggered a backup
+	TriggerKey = "trigger"
+
+	// StartedAtKey is the key holding the time at which a backup was started
+	StartedAtKey = "started_at"
+
+	// NextRunKey is the key holding the time at which the next scheduled backup of a database is due
+	NextRunKey = "next_run"
 )
 
 // ErrNoDocuments is the error when no matching documents are found
@@ -3,8 +3,13 @@ package mongo
 import (
 	"context"
 	"time"
+
+	m "go.mongodb.org/mongo-driver/mongo"
 )
 
+// duplicateKeyErrorCode is the code of the error raised by mongoDB when a write violates a unique index
+const duplicateKeyErrorCode = 11000
+
 // InsertOne inserts a document into a mongoDB collection
 func InsertOne(collectionName string, data interface{}) (interface{}, error) {
 	collection := link.Collection(collectionName)
@@ -17,6 +22,20 @@ func InsertOne(collectionName string, data interface{}) (interface{}, error) {
 	return res.InsertedID, nil
 }
 
+// IsDuplicateKeyError tells whether an insertion failed because it violates a unique index
+func IsDuplicateKeyError(err error) bool {
+	writeException, ok := err.(m.WriteException)
+	if !ok {
+		return false
+	}
+	for _, writeError := range writeException.WriteErrors {
+		if writeError.Code == duplicateKeyErrorCode {
+			return true
+		}
+	}
+	return false
+}
+
 // InsertMany inserts multiple document into a mongoDB collection
 func InsertMany(collectionName string, data []interface{}) ([]interface{}, error) {
 	collection := link.Collection(collectionName)
@@ -49,3 +68,66 @@ func RegisterMetrics(data interface{}) (interface{}, error) {
 func BulkRegisterMetrics(data []interface{}) ([]interface{}, error) {
 	return InsertMany(MetricsCollection, data)
 }
+
+// BulkRegisterLogs is an abstraction over InsertMany which inserts multiple
+// log lines into the mongoDB
+func BulkRegisterLogs(data []interface{}) ([]interface{}, error) {
+	return InsertMany(LogsCollection, data)
+}
+
+// RegisterDrain is an abstraction over InsertOne which inserts a log drain into the mongoDB
+func RegisterDrain(data interface{}) (interface{}, error) {
+	return InsertOne(DrainCollection, data)
+}
+
+// RegisterSSHKey is an abstraction over InsertOne which inserts a SSH public key into the mongoDB
+func RegisterSSHKey(data interface{}) (interface{}, error) {
+	return InsertOne(SSHKeyCollection, data)
+}
+
+// RegisterSession is an abstraction over InsertOne which inserts a SSH session's audit information into the mongoDB
+func RegisterSession(data interface{}) (interface{}, error) {
+	return InsertOne(SessionCollection, data)
+}
+
+// RegisterSessionRecording is an abstraction over InsertOne which inserts a chunk of a SSH session's
+// transcript into the mongoDB
+func RegisterSessionRecording(data interface{}) (interface{}, error) {
+	return InsertOne(SessionRecordingCollection, data)
+}
+
+// RegisterAlertRule is an abstraction over InsertOne which inserts an alert rule into the mongoDB
+func RegisterAlertRule(data interface{}) (interface{}, error) {
+	return InsertOne(AlertRuleCollection, data)
+}
+
+// RegisterAlert is an abstraction over InsertOne which inserts an alert into the history of alerts
+func RegisterAlert(data interface{}) (interface{}, error) {
+	return InsertOne(AlertCollection, data)
+}
+
+// RegisterChannel is an abstraction over InsertOne which inserts a notification channel into the mongoDB
+func RegisterChannel(data interface{}) (interface{}, error) {
+	return InsertOne(ChannelCollection, data)
+}
+
+// RegisterBackup is an abstraction over InsertOne which inserts the metadata of a backup into the mongoDB
+func RegisterBackup(data interface{}) (interface{}, error) {
+	return InsertOne(BackupCollection, data)
+}
+
+// RegisterRestore is an abstraction over InsertOne which inserts the state of a restore into the mongoDB
+func RegisterRestore(data interface{}) (interface{}, error) {
+	return InsertOne(RestoreCollection, data)
+}
+
+// RegisterQueryAudit is an abstraction over InsertOne which inserts the record of a query run on a database
+// into the mongoDB
+func RegisterQueryAudit(data interface{}) (interface{}, error) {
+	return InsertOne(QueryAuditCollection, data)
+}
+
+// RegisterDatabaseUser is an abstraction over InsertOne which inserts an additional user of a database into the mongoDB
+func RegisterDatabaseUser(data interface{}) (interface{}, error) {
+	return InsertOne(DatabaseUserCollection, data)
+}
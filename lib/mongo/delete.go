@@ -29,3 +29,91 @@ func DeleteUser(filter types.M) (interface{}, error) {
 func DeleteMetrics(filter types.M) (interface{}, error) {
 	return DeleteOne(MetricsCollection, filter)
 }
+
+// DeleteMany deletes multiple documents from a mongoDB collection
+func DeleteMany(collectionName string, filter types.M) (interface{}, error) {
+	collection := link.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return collection.DeleteMany(ctx, filter)
+}
+
+// DeleteSSHKey is an abstraction over DeleteOne which deletes a SSH public key from mongoDB
+func DeleteSSHKey(filter types.M) (interface{}, error) {
+	return DeleteOne(SSHKeyCollection, filter)
+}
+
+// DeleteSSHKeys is an abstraction over DeleteMany which deletes multiple SSH public keys from mongoDB
+func DeleteSSHKeys(filter types.M) (interface{}, error) {
+	return DeleteMany(SSHKeyCollection, filter)
+}
+
+// DeleteDrain is an abstraction over DeleteOne which deletes a log drain from mongoDB
+func DeleteDrain(filter types.M) (interface{}, error) {
+	return DeleteOne(DrainCollection, filter)
+}
+
+// DeleteDrains is an abstraction over DeleteMany which deletes multiple log drains from mongoDB
+func DeleteDrains(filter types.M) (interface{}, error) {
+	return DeleteMany(DrainCollection, filter)
+}
+
+// DeleteAlertRule is an abstraction over DeleteOne which deletes an alert rule from mongoDB
+func DeleteAlertRule(filter types.M) (interface{}, error) {
+	return DeleteOne(AlertRuleCollection, filter)
+}
+
+// DeleteAlertRules is an abstraction over DeleteMany which deletes multiple alert rules from mongoDB
+func DeleteAlertRules(filter types.M) (interface{}, error) {
+	return DeleteMany(AlertRuleCollection, filter)
+}
+
+// DeleteProbes is an abstraction over DeleteMany which deletes the probe results of applications from mongoDB
+func DeleteProbes(filter types.M) (interface{}, error) {
+	return DeleteMany(ProbeCollection, filter)
+}
+
+// DeleteChannel is an abstraction over DeleteOne which deletes a notification channel from mongoDB
+func DeleteChannel(filter types.M) (interface{}, error) {
+	return DeleteOne(ChannelCollection, filter)
+}
+
+// DeleteChannels is an abstraction over DeleteMany which deletes multiple notification channels from mongoDB
+func DeleteChannels(filter types.M) (interface{}, error) {
+	return DeleteMany(ChannelCollection, filter)
+}
+
+// DeleteBackup is an abstraction over DeleteOne which deletes the metadata of a backup from mongoDB
+func DeleteBackup(filter types.M) (interface{}, error) {
+	return DeleteOne(BackupCollection, filter)
+}
+
+// DeleteBackups is an abstraction over DeleteMany which deletes the metadata of multiple backups from mongoDB
+func DeleteBackups(filter types.M) (interface{}, error) {
+	return DeleteMany(BackupCollection, filter)
+}
+
+// DeleteBackupSchedule is an abstraction over DeleteOne which deletes the backup schedule of a database from mongoDB
+func DeleteBackupSchedule(filter types.M) (interface{}, error) {
+	return DeleteOne(BackupScheduleCollection, filter)
+}
+
+// DeleteRestores is an abstraction over DeleteMany which deletes the states of multiple restores from mongoDB
+func DeleteRestores(filter types.M) (interface{}, error) {
+	return DeleteMany(RestoreCollection, filter)
+}
+
+// DeleteQueryAudits is an abstraction over DeleteMany which deletes the records of the queries run on databases from mongoDB
+func DeleteQueryAudits(filter types.M) (interface{}, error) {
+	return DeleteMany(QueryAuditCollection, filter)
+}
+
+// DeleteDatabaseUser is an abstraction over DeleteOne which deletes an additional user of a database from mongoDB
+func DeleteDatabaseUser(filter types.M) (interface{}, error) {
+	return DeleteOne(DatabaseUserCollection, filter)
+}
+
+// DeleteDatabaseUsers is an abstraction over DeleteMany which deletes multiple additional users of databases from mongoDB
+func DeleteDatabaseUsers(filter types.M) (interface{}, error) {
+	return DeleteMany(DatabaseUserCollection, filter)
+}
@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+	"go.mongodb.org/mongo-driver/bson"
+	m "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ttlIndexes holds the collections whose documents are removed by mongoDB once the time
+// stored in the corresponding key has passed
+var ttlIndexes = map[string]string{
+	SessionCollection:           ExpiresAtKey,
+	SessionRecordingCollection:  ExpiresAtKey,
+	LogsCollection:              ExpiresAtKey,
+	MetricsCollection:           ExpiresAtKey,
+	FiveMinuteMetricsCollection: ExpiresAtKey,
+	HourlyMetricsCollection:     ExpiresAtKey,
+	AlertCollection:             ExpiresAtKey,
+	ProbeCollection:             ExpiresAtKey,
+	QueryAuditCollection:        ExpiresAtKey,
+}
+
+// compoundIndexes holds the indexes speeding up the frequent queries on a collection
+var compoundIndexes = map[string]bson.D{
+	LogsCollection:              {{Key: NameKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+	MetricsCollection:           {{Key: NameKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+	FiveMinuteMetricsCollection: {{Key: NameKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+	HourlyMetricsCollection:     {{Key: NameKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+	AlertCollection:             {{Key: AppKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+	ProbeCollection:             {{Key: AppKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+	BackupCollection:            {{Key: DatabaseKey, Value: 1}, {Key: StartedAtKey, Value: -1}},
+	RestoreCollection:           {{Key: DatabaseKey, Value: 1}, {Key: StartedAtKey, Value: -1}},
+	DatabaseUserCollection:      {{Key: DatabaseKey, Value: 1}, {Key: UsernameKey, Value: 1}},
+	QueryAuditCollection:        {{Key: DatabaseKey, Value: 1}, {Key: TimestampKey, Value: -1}},
+}
+
+// uniqueIndexes holds the collections in which no two documents can share the value of a key
+var uniqueIndexes = map[string]string{
+	SSHKeyCollection: FingerprintKey,
+}
+
+// CreateTTLIndex creates an index on a key holding the time after which a document expires
+func CreateTTLIndex(collectionName, key string) error {
+	collection := link.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, m.IndexModel{
+		Keys:    types.M{key: 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// CreateIndex creates an index on the given keys of a collection
+func CreateIndex(collectionName string, keys bson.D) error {
+	collection := link.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, m.IndexModel{Keys: keys})
+	return err
+}
+
+// CreateUniqueIndex creates an index rejecting documents which repeat the value of a key
+func CreateUniqueIndex(collectionName, key string) error {
+	collection := link.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, m.IndexModel{
+		Keys:    types.M{key: 1},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func setupIndexes() {
+	for collection, key := range ttlIndexes {
+		if err := CreateTTLIndex(collection, key); err != nil {
+			utils.LogError("Mongo-Index-1", err)
+		}
+	}
+	for collection, keys := range compoundIndexes {
+		if err := CreateIndex(collection, keys); err != nil {
+			utils.LogError("Mongo-Index-2", err)
+		}
+	}
+	for collection, key := range uniqueIndexes {
+		if err := CreateUniqueIndex(collection, key); err != nil {
+			utils.LogError("Mongo-Index-3", err)
+		}
+	}
+}
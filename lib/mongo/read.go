@@ -7,6 +7,7 @@ import (
 
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/types"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -39,8 +40,14 @@ func FetchDocs(collectionName string, filter types.M, opts ...*options.FindOptio
 }
 
 // FetchInstances is an abstraction over FetchDocs for retrieving any instance documents
+// The passwords of the root users of the servers of dedicated databases are left out
 func FetchInstances(filter types.M) []types.M {
-	return FetchDocs(InstanceCollection, filter)
+	return FetchDocs(
+		InstanceCollection,
+		filter,
+		&options.FindOptions{
+			Projection: types.M{RootPasswordKey: 0},
+		})
 }
 
 // FetchAppInfo is an abstraction over FetchDocs for retrieving application related documents
@@ -153,11 +160,75 @@ func FetchUserInfo(filter types.M) []types.M {
 
 // FetchContainerMetrics is an abstraction over FetchDocs for retrieving metrics of a container
 func FetchContainerMetrics(filter types.M, count int64) []types.M {
+	return FetchMetricsFrom(MetricsCollection, filter, count)
+}
+
+// FetchMetricsFrom is an abstraction over FetchDocs for retrieving the latest metrics
+// or metrics rollups of a container from the given collection
+func FetchMetricsFrom(collectionName string, filter types.M, count int64) []types.M {
 	options := options.Find().SetSort(types.M{TimestampKey: -1})
 	if count > 0 {
 		options.SetLimit(count)
 	}
-	return FetchDocs(MetricsCollection, filter, options)
+	return FetchDocs(collectionName, filter, options)
+}
+
+// FetchMetricsRollups returns the metrics rollups satisfying the filter from the given collection
+// sorted in the descending order of their periods
+func FetchMetricsRollups(collectionName string, filter types.M) ([]*types.MetricsRollup, error) {
+	collection := link.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(types.M{TimestampKey: -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	rollups := []*types.MetricsRollup{}
+	if err := cur.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// FetchMetricsSamples returns the raw metrics of containers satisfying the filter
+// sorted from the latest to the oldest
+func FetchMetricsSamples(filter types.M) ([]*types.Metrics, error) {
+	collection := link.Collection(MetricsCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(types.M{TimestampKey: -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	metrics := []*types.Metrics{}
+	if err := cur.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// FetchMetricsInRange returns the raw metrics of all containers read in the period [start, end)
+// The metrics of databases stored in the same collection are left out
+func FetchMetricsInRange(start, end int64) ([]*types.Metrics, error) {
+	return FetchMetricsSamples(types.M{
+		TimestampKey: types.M{
+			"$gte": start,
+			"$lt":  end,
+		},
+		InstanceTypeKey: types.M{"$ne": DBInstance},
+	})
+}
+
+// FetchDatabaseMetrics is an abstraction over FetchDocs for retrieving the latest metrics of a database
+func FetchDatabaseMetrics(filter types.M, count int64) []types.M {
+	filter[InstanceTypeKey] = DBInstance
+	return FetchMetricsFrom(MetricsCollection, filter, count)
 }
 
 // CountDocs returns the number of documents matching a filter
@@ -210,3 +281,322 @@ func CountInstanceInTimeFrame(owner string, instance string, rate time.Duration)
 	}
 	return len(FetchInstances(filter))
 }
+
+// FetchSSHKeys is an abstraction over FetchDocs for retrieving SSH public keys
+func FetchSSHKeys(filter types.M) []types.M {
+	return FetchDocs(SSHKeyCollection, filter)
+}
+
+// FetchSSHKeyGrants is an abstraction over FetchDocs for retrieving the SSH public keys granted access to
+// an application, only their owners, names and fingerprints are returned since the keys are not owned
+// by the owner of the application
+func FetchSSHKeyGrants(filter types.M) []types.M {
+	return FetchDocs(
+		SSHKeyCollection,
+		filter,
+		&options.FindOptions{
+			Projection: types.M{"_id": 0, OwnerKey: 1, NameKey: 1, FingerprintKey: 1},
+		})
+}
+
+// FetchSingleSSHKey returns a SSH public key based on its SHA256 fingerprint
+func FetchSingleSSHKey(fingerprint string) (*types.SSHKey, error) {
+	collection := link.Collection(SSHKeyCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := &types.SSHKey{}
+	err := collection.FindOne(ctx, types.M{FingerprintKey: fingerprint}).Decode(key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// CountSSHKeys returns the number of SSH public keys matching a filter
+func CountSSHKeys(filter types.M) (int64, error) {
+	return CountDocs(SSHKeyCollection, filter)
+}
+
+// FetchSessions is an abstraction over FetchDocs for retrieving the audit information of SSH sessions
+// The latest sessions are returned first
+func FetchSessions(filter types.M) []types.M {
+	return FetchDocs(SessionCollection, filter, options.Find().SetSort(types.M{StartKey: -1}))
+}
+
+// FetchSessionRecording returns the chunks of a SSH session's transcript in order
+func FetchSessionRecording(sessionID string) []types.M {
+	return FetchDocs(
+		SessionRecordingCollection,
+		types.M{SessionIDKey: sessionID},
+		options.Find().SetSort(types.M{SequenceKey: 1}),
+	)
+}
+
+// FetchLogs is an abstraction over FetchDocs for retrieving the log lines shipped from containers
+// The latest lines are returned first and at most limit lines are returned
+func FetchLogs(filter types.M, limit int64) []types.M {
+	return FetchDocs(
+		LogsCollection,
+		filter,
+		options.Find().
+			SetSort(types.M{TimestampKey: -1}).
+			SetLimit(limit).
+			SetProjection(types.M{"_id": 0, ExpiresAtKey: 0, TimestampNanoKey: 0, SequenceKey: 0}),
+	)
+}
+
+// FetchLastLog returns the latest log line satisfying the filter
+func FetchLastLog(filter types.M) (*types.LogEntry, error) {
+	collection := link.Collection(LogsCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entry := &types.LogEntry{}
+	err := collection.FindOne(ctx, filter, options.FindOne().
+		SetSort(bson.D{{Key: TimestampNanoKey, Value: -1}, {Key: TimestampKey, Value: -1}, {Key: SequenceKey, Value: -1}}),
+	).Decode(entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// FetchDrains returns the log drains of applications satisfying the filter
+func FetchDrains(filter types.M) ([]*types.LogDrain, error) {
+	collection := link.Collection(DrainCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	drains := []*types.LogDrain{}
+	if err := cur.All(ctx, &drains); err != nil {
+		return nil, err
+	}
+	return drains, nil
+}
+
+// CountDrains returns the number of log drains matching a filter
+func CountDrains(filter types.M) (int64, error) {
+	return CountDocs(DrainCollection, filter)
+}
+
+// FetchAlertRules returns the alert rules of applications satisfying the filter
+func FetchAlertRules(filter types.M) ([]*types.AlertRule, error) {
+	collection := link.Collection(AlertRuleCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	rules := []*types.AlertRule{}
+	if err := cur.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CountAlertRules returns the number of alert rules matching a filter
+func CountAlertRules(filter types.M) (int64, error) {
+	return CountDocs(AlertRuleCollection, filter)
+}
+
+// FetchAlerts returns the latest alerts satisfying the filter from the history of alerts
+func FetchAlerts(filter types.M, limit int64) ([]*types.Alert, error) {
+	collection := link.Collection(AlertCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().
+		SetSort(types.M{TimestampKey: -1}).
+		SetLimit(limit).
+		SetProjection(types.M{"_id": 0, ExpiresAtKey: 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	alerts := []*types.Alert{}
+	if err := cur.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// FetchChannels returns the notification channels of users satisfying the filter
+func FetchChannels(filter types.M) ([]*types.NotificationChannel, error) {
+	collection := link.Collection(ChannelCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	channels := []*types.NotificationChannel{}
+	if err := cur.All(ctx, &channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// CountChannels returns the number of notification channels matching a filter
+func CountChannels(filter types.M) (int64, error) {
+	return CountDocs(ChannelCollection, filter)
+}
+
+// FetchProbeSlots returns the results of the HTTP probes sent to applications satisfying the filter
+func FetchProbeSlots(filter types.M) ([]*types.ProbeSlot, error) {
+	collection := link.Collection(ProbeCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetProjection(types.M{"_id": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	slots := []*types.ProbeSlot{}
+	if err := cur.All(ctx, &slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// FetchContainerUptime returns the fraction of the time for which containers satisfying the filter
+// were alive in the 5-minute rollups of their metrics
+func FetchContainerUptime(filter types.M) ([]*types.MetricsRollup, error) {
+	collection := link.Collection(FiveMinuteMetricsCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetProjection(types.M{
+		"_id":        0,
+		NameKey:      1,
+		TimestampKey: 1,
+		"samples":    1,
+		"uptime":     1,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	rollups := []*types.MetricsRollup{}
+	if err := cur.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// FetchBackups returns the metadata of the backups satisfying the filter from the latest to the oldest
+func FetchBackups(filter types.M) ([]*types.Backup, error) {
+	collection := link.Collection(BackupCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(types.M{StartedAtKey: -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	backups := []*types.Backup{}
+	if err := cur.All(ctx, &backups); err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+// FetchBackupSchedules returns the backup schedules of databases satisfying the filter
+func FetchBackupSchedules(filter types.M) ([]*types.BackupSchedule, error) {
+	collection := link.Collection(BackupScheduleCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	schedules := []*types.BackupSchedule{}
+	if err := cur.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// FetchRestores returns the states of the restores satisfying the filter from the latest to the oldest
+func FetchRestores(filter types.M) ([]*types.Restore, error) {
+	collection := link.Collection(RestoreCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(types.M{StartedAtKey: -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	restores := []*types.Restore{}
+	if err := cur.All(ctx, &restores); err != nil {
+		return nil, err
+	}
+	return restores, nil
+}
+
+// FetchQueryAudits returns the records of the queries run on databases satisfying the filter from the latest
+// to the oldest, at most count of them if count is positive
+func FetchQueryAudits(filter types.M, count int64) ([]*types.QueryAudit, error) {
+	collection := link.Collection(QueryAuditCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(types.M{TimestampKey: -1})
+	if count > 0 {
+		opts.SetLimit(count)
+	}
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	audits := []*types.QueryAudit{}
+	if err := cur.All(ctx, &audits); err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// FetchDatabaseUsers returns the additional users of databases satisfying the filter from the oldest to the latest
+func FetchDatabaseUsers(filter types.M) ([]*types.DatabaseUser, error) {
+	collection := link.Collection(DatabaseUserCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, options.Find().SetSort(types.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	users := []*types.DatabaseUser{}
+	if err := cur.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
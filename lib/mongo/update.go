@@ -72,4 +72,113 @@ func UpdateOneWithUpsert(collectionName string, filter types.M, data interface{}
 	defer cancel()
 	_,err=collection.UpdateOne(ctx, filter, types.M{"$set": data}, option)
 	return err
-}
\ No newline at end of file
+}
+
+// UpsertMetricsRollup is an abstraction over UpdateOneWithUpsert which stores the rollup of a
+// container's metrics in the given collection replacing any previous rollup of the same period
+func UpsertMetricsRollup(collectionName string, rollup *types.MetricsRollup) error {
+	filter := types.M{
+		NameKey:      rollup.Name,
+		TimestampKey: rollup.Timestamp,
+	}
+	return UpdateOneWithUpsert(collectionName, filter, rollup, options.Update().SetUpsert(true))
+}
+
+// TransitionAlertRule is an abstraction over ModifyOne which updates an alert rule matching
+// the filter and returns whether it matched, hence only one Master instance wins a transition
+func TransitionAlertRule(filter types.M, update types.M) (bool, error) {
+	matched, err := ModifyOne(AlertRuleCollection, filter, types.M{"$set": update})
+	return matched > 0, err
+}
+
+// RecordProbe counts the result of a HTTP probe sent to an application in the 5-minute
+// period starting at the given timestamp, creating the period's document if required
+func RecordProbe(app string, timestamp int64, probeErr error, expiresAt time.Time) error {
+	set := types.M{ExpiresAtKey: expiresAt}
+	failures := 0
+	if probeErr != nil {
+		set["last_error"] = probeErr.Error()
+		failures = 1
+	}
+	collection := link.Collection(ProbeCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.UpdateOne(ctx, types.M{
+		AppKey:       app,
+		TimestampKey: timestamp,
+	}, types.M{
+		"$inc": types.M{"probes": 1, "failures": failures},
+		"$set": set,
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+// UpdateBackup is an abstraction over UpdateOne which updates the metadata of a backup in mongoDB
+func UpdateBackup(filter types.M, data interface{}) error {
+	return UpdateOne(BackupCollection, filter, data, nil)
+}
+
+// UpdateRestore is an abstraction over UpdateOne which updates the state of a restore in mongoDB
+func UpdateRestore(filter types.M, data interface{}) error {
+	return UpdateOne(RestoreCollection, filter, data, nil)
+}
+
+// UpdateDatabaseUser is an abstraction over UpdateOne which updates an additional user of a database in mongoDB
+func UpdateDatabaseUser(filter types.M, data interface{}) error {
+	return UpdateOne(DatabaseUserCollection, filter, data, nil)
+}
+
+// UpsertBackupSchedule is an abstraction over UpdateOneWithUpsert which stores the backup schedule
+// of a database replacing its previous schedule
+func UpsertBackupSchedule(schedule *types.BackupSchedule) error {
+	return UpdateOneWithUpsert(BackupScheduleCollection, types.M{DatabaseKey: schedule.Database}, schedule, options.Update().SetUpsert(true))
+}
+
+// ClaimBackupSchedule moves the next run of a due backup schedule and returns whether it was
+// still due, hence a scheduled backup is taken only once
+func ClaimBackupSchedule(schedule *types.BackupSchedule, now, nextRun time.Time) (bool, error) {
+	matched, err := ModifyOne(BackupScheduleCollection, types.M{
+		DatabaseKey: schedule.Database,
+		NextRunKey:  schedule.NextRun,
+	}, types.M{"$set": types.M{
+		NextRunKey: nextRun,
+		"last_run": now,
+	}})
+	return matched > 0, err
+}
+
+// ModifyOne applies an update document consisting of update operators like `$addToSet` and `$pull`
+// to a document in the mongoDB collection and returns the number of matched documents
+func ModifyOne(collectionName string, filter types.M, update types.M) (int64, error) {
+	collection := link.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+// GrantSSHKeyAccess is an abstraction over ModifyOne which grants a SSH public key access to an application
+func GrantSSHKeyAccess(filter types.M, app string) (int64, error) {
+	return ModifyOne(SSHKeyCollection, filter, types.M{"$addToSet": types.M{AppsKey: app}})
+}
+
+// RevokeSSHKeyAccess is an abstraction over ModifyOne which revokes the access of a SSH public key to an application
+func RevokeSSHKeyAccess(filter types.M, app string) (int64, error) {
+	return ModifyOne(SSHKeyCollection, filter, types.M{"$pull": types.M{AppsKey: app}})
+}
+
+// RevokeAllSSHKeyAccess revokes the access of all SSH public keys to an application
+func RevokeAllSSHKeyAccess(app string) (interface{}, error) {
+	collection := link.Collection(SSHKeyCollection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return collection.UpdateMany(ctx, types.M{AppsKey: app}, types.M{"$pull": types.M{AppsKey: app}})
+}
+
+// UpdateSession is an abstraction over UpdateOne which updates a SSH session's audit information in mongoDB
+func UpdateSession(filter types.M, data interface{}) error {
+	return UpdateOne(SessionCollection, filter, data, nil)
+}
@@ -12,6 +12,15 @@ const (
 	// SSHKey is the key name for the Sorted Set containing ssh microservice instances
 	SSHKey string = types.GenSSH
 
+	// SSHHostKeysKey is the key name for the HashMap containing the public host keys of ssh microservice instances
+	SSHHostKeysKey string = "ssh_host_keys"
+
+	// WebTerminalTicketKey is the prefix of the keys holding the tickets of web terminals
+	WebTerminalTicketKey string = "web_terminal_ticket"
+
+	// DeployEventsKey is the name of the channel where the deploy events of applications are published
+	DeployEventsKey string = "deploy_events"
+
 	// WorkerInstanceKey is the key name for Worker nodes
 	WorkerInstanceKey string = types.AppMaker
 
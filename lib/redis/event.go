@@ -0,0 +1,23 @@
+package redis
+
+import (
+	"encoding/json"
+
+	"github.com/go-redis/redis"
+	"github.com/sdslabs/gasper/types"
+)
+
+// PublishDeployEvent publishes a deploy event of an application to its subscribers
+func PublishDeployEvent(event *types.DeployEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = client.Publish(DeployEventsKey, data).Result()
+	return err
+}
+
+// SubscribeDeployEvents returns a subscription to the deploy events of all applications
+func SubscribeDeployEvents() *redis.PubSub {
+	return client.Subscribe(DeployEventsKey)
+}
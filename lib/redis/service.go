@@ -122,3 +122,19 @@ func GetSSHPort(url string) (string, error) {
 	}
 	return strings.Split(data[0], ":")[1], nil
 }
+
+// RegisterSSHHostKeys stores the public host keys of a ssh microservice instance
+// The keys are pinned by other instances while creating SSH bridge connections
+func RegisterSSHHostKeys(url string, keys []string) error {
+	_, err := client.HSet(SSHHostKeysKey, url, strings.Join(keys, "\n")).Result()
+	return err
+}
+
+// FetchSSHHostKeys returns the public host keys of a ssh microservice instance
+func FetchSSHHostKeys(url string) ([]string, error) {
+	data, err := client.HGet(SSHHostKeysKey, url).Result()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(data, "\n"), nil
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// RegisterWebTerminalTicket stores a ticket mapping a web terminal of an application to the user it was
+// deployed for, the ticket expires after the given duration unless it is refreshed
+func RegisterWebTerminalTicket(ticket, appName, user string, expiration time.Duration) error {
+	_, err := client.Set(WebTerminalTicketKey+":"+ticket, appName+":"+user, expiration).Result()
+	return err
+}
+
+// RefreshWebTerminalTicket extends the expiration of the ticket of a web terminal which is still running
+func RefreshWebTerminalTicket(ticket string, expiration time.Duration) error {
+	_, err := client.Expire(WebTerminalTicketKey+":"+ticket, expiration).Result()
+	return err
+}
+
+// RemoveWebTerminalTicket removes the ticket of a web terminal which is torn down
+func RemoveWebTerminalTicket(ticket string) error {
+	_, err := client.Del(WebTerminalTicketKey + ":" + ticket).Result()
+	return err
+}
+
+// RedeemWebTerminalTicket returns the user a web terminal of an application was deployed for
+// The ticket is redeemed by every connection of the terminal until the terminal is torn down
+func RedeemWebTerminalTicket(ticket, appName string) (string, error) {
+	data, err := client.Get(WebTerminalTicketKey + ":" + ticket).Result()
+	if err != nil {
+		return "", err
+	}
+	values := strings.SplitN(data, ":", 2)
+	if len(values) != 2 || values[0] != appName {
+		return "", errors.New("web terminal ticket was not issued for application " + appName)
+	}
+	return values[1], nil
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/sdslabs/gasper/configs"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The encrypted objects start with a magic and a random salt from which the key of the object is
+// derived along with the cluster secret and the name of the target
+// The plaintext is then sealed with AES-256-GCM in chunks whose nonces hold the index of the chunk
+// and whether it is the last one, so that the chunks cannot be reordered or truncated unnoticed
+const (
+	encryptionMagic = "GSPRENC1"
+	saltSize        = 16
+	keySize         = 32
+	chunkSize       = 64 * 1024
+)
+
+// errTruncated is returned when an encrypted object ends before its last chunk
+var errTruncated = errors.New("encrypted backup is truncated")
+
+// encryptedTarget encrypts the objects stored in the underlying target
+type encryptedTarget struct {
+	Target
+	name string
+}
+
+func newEncryptedTarget(target Target, name string) *encryptedTarget {
+	return &encryptedTarget{Target: target, name: name}
+}
+
+// aead returns the cipher sealing the chunks of an object with the given salt
+func (target *encryptedTarget) aead(salt []byte) (cipher.AEAD, error) {
+	key := make([]byte, keySize)
+	derivation := hkdf.New(sha256.New, []byte(configs.GasperConfig.Secret), salt, []byte("gasper-backups:"+target.name))
+	if _, err := io.ReadFull(derivation, key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the chunk at the index
+func chunkNonce(size int, index uint64, last bool) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-9:size-1], index)
+	if last {
+		nonce[size-1] = 1
+	}
+	return nonce
+}
+
+func (target *encryptedTarget) Put(key string, reader io.Reader) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := target.aead(salt)
+	if err != nil {
+		return err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(encrypt(aead, salt, reader, pipeWriter))
+	}()
+	err = target.Target.Put(key, pipeReader)
+	pipeReader.CloseWithError(err)
+	return err
+}
+
+// encrypt writes the header and the sealed chunks of the plaintext
+// A chunk is sealed only once the next one is started so that the last chunk is always known
+func encrypt(aead cipher.AEAD, salt []byte, plaintext io.Reader, writer io.Writer) error {
+	if _, err := writer.Write(append([]byte(encryptionMagic), salt...)); err != nil {
+		return err
+	}
+	current := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+aead.Overhead())
+
+	n, err := io.ReadFull(plaintext, current)
+	for index := uint64(0); ; index++ {
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		last := err != nil
+		var m int
+		if !last {
+			m, err = io.ReadFull(plaintext, next)
+			// The current chunk is the last one if nothing follows it
+			last = m == 0 && err == io.EOF
+		}
+		sealed = aead.Seal(sealed[:0], chunkNonce(aead.NonceSize(), index, last), current[:n], nil)
+		if _, werr := writer.Write(sealed); werr != nil {
+			return werr
+		}
+		if last {
+			return nil
+		}
+		current, next, n = next, current, m
+	}
+}
+
+func (target *encryptedTarget) Get(key string) (io.ReadCloser, error) {
+	object, err := target.Target.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(encryptionMagic)+saltSize)
+	if _, err := io.ReadFull(object, header); err != nil || !bytes.Equal(header[:len(encryptionMagic)], []byte(encryptionMagic)) {
+		object.Close()
+		return nil, errors.New("backup is not encrypted")
+	}
+	aead, err := target.aead(header[len(encryptionMagic):])
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+	return &decryptingReader{
+		object: object,
+		reader: bufio.NewReaderSize(object, chunkSize+aead.Overhead()+1),
+		aead:   aead,
+		sealed: make([]byte, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+// decryptingReader opens the sealed chunks of an encrypted object as they are read
+type decryptingReader struct {
+	object io.Closer
+	reader *bufio.Reader
+	aead   cipher.AEAD
+	sealed []byte
+	chunk  []byte
+	index  uint64
+	done   bool
+}
+
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.chunk) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.chunk)
+	dr.chunk = dr.chunk[n:]
+	return n, nil
+}
+
+// next opens the next chunk, which is the last one if nothing follows it
+func (dr *decryptingReader) next() error {
+	n, err := io.ReadFull(dr.reader, dr.sealed)
+	if err == io.EOF {
+		return errTruncated
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	last := err == io.ErrUnexpectedEOF
+	if !last {
+		if _, err := dr.reader.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	chunk, err := dr.aead.Open(dr.sealed[:0], chunkNonce(dr.aead.NonceSize(), dr.index, last), dr.sealed[:n], nil)
+	if err != nil {
+		return errors.New("encrypted backup cannot be decrypted, it might be corrupted or the secret might have changed")
+	}
+	dr.chunk = chunk
+	dr.index++
+	dr.done = last
+	return nil
+}
+
+func (dr *decryptingReader) Close() error {
+	return dr.object.Close()
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sdslabs/gasper/configs"
+)
+
+// localTarget stores the objects as files in a directory of the current node
+type localTarget struct {
+	directory string
+}
+
+func newLocalTarget(config configs.StorageTarget) (*localTarget, error) {
+	directory := config.Directory
+	if directory == "" {
+		directory = defaultDirectory()
+	}
+	return &localTarget{directory: directory}, nil
+}
+
+func (target *localTarget) path(key string) string {
+	return filepath.Join(target.directory, filepath.FromSlash(key))
+}
+
+// Put writes the object to a temporary file which is renamed once it is complete
+func (target *localTarget) Put(key string, reader io.Reader) (err error) {
+	path := target.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.part")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+
+	if _, err = io.Copy(file, reader); err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+	if err = file.Chmod(0600); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (target *localTarget) Get(key string) (io.ReadCloser, error) {
+	return os.Open(target.path(key))
+}
+
+// Delete removes the object along with its directory if it becomes empty
+func (target *localTarget) Delete(key string) error {
+	path := target.path(key)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != target.directory {
+		os.Remove(dir)
+	}
+	return nil
+}
+
+func (target *localTarget) Shared() bool {
+	return false
+}
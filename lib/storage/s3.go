@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+)
+
+const (
+	// s3DefaultRegion is the region used for signing the requests when none is configured
+	s3DefaultRegion = "us-east-1"
+
+	// emptyPayloadHash is the SHA-256 hash of an empty request body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// s3PartSize is the size of the parts in which the objects are uploaded
+	// S3 allows at most 10000 parts of an object hence objects up to 640 GiB can be uploaded
+	s3PartSize = 64 * 1024 * 1024
+
+	// s3MaxParts is the maximum number of parts of an object uploaded in parts
+	s3MaxParts = 10000
+)
+
+// s3Target stores the objects in a bucket of an S3-compatible object storage like AWS S3 or MinIO
+// The requests are signed with AWS Signature Version 4 and address the bucket in the path
+type s3Target struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func newS3Target(config configs.StorageTarget) (*s3Target, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errors.New("endpoint and bucket are required")
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint %s must be a http or https URL", config.Endpoint)
+	}
+	region := config.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	return &s3Target{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    config.Bucket,
+		prefix:    strings.Trim(config.Directory, "/"),
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+		client:    &http.Client{},
+	}, nil
+}
+
+// objectURL returns the URL of the object stored at the key with the given canonical query string
+func (target *s3Target) objectURL(key, query string) *url.URL {
+	objectURL := *target.endpoint
+	objectURL.Path = "/" + path.Join(target.bucket, target.prefix, key)
+	objectURL.RawPath = uriEncode(objectURL.Path)
+	objectURL.RawQuery = query
+	return &objectURL
+}
+
+// do sends a signed request for the object stored at the key
+func (target *s3Target) do(method, key string, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	return target.doQuery(method, key, "", body, size, payloadHash)
+}
+
+// doQuery sends a signed request for the object stored at the key with a query string
+// The parameters of the query string must be sorted and encoded as required by the signature
+func (target *s3Target) doQuery(method, key, query string, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	req, err := http.NewRequest(method, target.objectURL(key, query).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	target.sign(req, payloadHash, time.Now().UTC())
+
+	res, err := target.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 && !(method == http.MethodDelete && res.StatusCode == http.StatusNotFound) {
+		defer res.Body.Close()
+		message, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("%s of object %s failed with status %d : %s", method, key, res.StatusCode, message)
+	}
+	return res, nil
+}
+
+// sign adds the headers of AWS Signature Version 4 to the request
+func (target *s3Target) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, target.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+target.secretKey), date)
+	key = hmacSHA256(key, target.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		target.accessKey, scope, signedHeaders, signature))
+}
+
+// Put uploads the object in a single request if it fits in a part, otherwise in parts with a
+// multipart upload since S3 requires the size and the hash of every payload before its upload
+// and limits a single upload to 5 GiB
+func (target *s3Target) Put(key string, reader io.Reader) error {
+	part := make([]byte, s3PartSize)
+	size, err := io.ReadFull(reader, part)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	if size < s3PartSize {
+		return target.putObject(key, part[:size])
+	}
+	return target.putMultipart(key, part, reader)
+}
+
+// putObject uploads an object in a single request
+func (target *s3Target) putObject(key string, data []byte) error {
+	var body io.Reader = bytes.NewReader(data)
+	if len(data) == 0 {
+		body = http.NoBody
+	}
+	res, err := target.do(http.MethodPut, key, body, int64(len(data)), sha256Hex(data))
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// putMultipart uploads an object in parts, the first of which is already read from the reader
+// The upload is aborted on failure so that the uploaded parts are not left behind
+func (target *s3Target) putMultipart(key string, part []byte, reader io.Reader) error {
+	uploadID, err := target.createMultipartUpload(key)
+	if err != nil {
+		return err
+	}
+	if err := target.uploadParts(key, uploadID, part, reader); err != nil {
+		if res, abortErr := target.doQuery(http.MethodDelete, key, "uploadId="+queryEncode(uploadID), nil, 0, emptyPayloadHash); abortErr == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+// createMultipartUpload starts a multipart upload of an object and returns its ID
+func (target *s3Target) createMultipartUpload(key string) (string, error) {
+	res, err := target.doQuery(http.MethodPost, key, "uploads=", nil, 0, emptyPayloadHash)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	result := struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+	if err := xml.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("multipart upload of object %s was not assigned an ID", key)
+	}
+	return result.UploadID, nil
+}
+
+// s3CompletedPart is a part listed in the request completing a multipart upload
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts uploads the parts of an object one after the other and completes the multipart upload
+func (target *s3Target) uploadParts(key, uploadID string, part []byte, reader io.Reader) error {
+	completed := []s3CompletedPart{}
+	size := len(part)
+	for number := 1; size > 0; number++ {
+		if number > s3MaxParts {
+			return fmt.Errorf("object %s is larger than %d parts of %d bytes", key, s3MaxParts, s3PartSize)
+		}
+		query := fmt.Sprintf("partNumber=%d&uploadId=%s", number, queryEncode(uploadID))
+		res, err := target.doQuery(http.MethodPut, key, query, bytes.NewReader(part[:size]), int64(size), sha256Hex(part[:size]))
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		completed = append(completed, s3CompletedPart{PartNumber: number, ETag: res.Header.Get("ETag")})
+
+		size, err = io.ReadFull(reader, part)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: completed})
+	if err != nil {
+		return err
+	}
+	res, err := target.doQuery(http.MethodPost, key, "uploadId="+queryEncode(uploadID), bytes.NewReader(body), int64(len(body)), sha256Hex(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// The completion can fail after the response has started, the error is then sent in its body
+	message, _ := ioutil.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if bytes.Contains(message, []byte("<Error>")) {
+		return fmt.Errorf("completion of the multipart upload of object %s failed : %s", key, message)
+	}
+	return nil
+}
+
+func (target *s3Target) Get(key string) (io.ReadCloser, error) {
+	res, err := target.do(http.MethodGet, key, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (target *s3Target) Delete(key string) error {
+	res, err := target.do(http.MethodDelete, key, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (target *s3Target) Shared() bool {
+	return true
+}
+
+// uriEncode encodes a path as required by AWS Signature Version 4, leaving only
+// the unreserved characters and the slashes as they are
+func uriEncode(path string) string {
+	var encoded strings.Builder
+	for _, b := range []byte(path) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || b == '/' {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+// queryEncode encodes a value of a query string as required by AWS Signature Version 4
+func queryEncode(value string) string {
+	return strings.ReplaceAll(uriEncode(value), "/", "%2F")
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/sdslabs/gasper/configs"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sftpDefaultPort is the port of the SFTP server used when none is configured
+	sftpDefaultPort = 22
+
+	// sftpDialTimeout is the maximum time taken for connecting to the SFTP server
+	sftpDialTimeout = 30 * time.Second
+)
+
+// sftpTarget stores the objects as files in a directory of a SFTP server
+// A connection is opened for every operation since backups are stored and fetched rarely
+type sftpTarget struct {
+	address   string
+	directory string
+	config    *ssh.ClientConfig
+}
+
+func newSFTPTarget(config configs.StorageTarget) (*sftpTarget, error) {
+	if config.Host == "" || config.Username == "" {
+		return nil, errors.New("host and username are required")
+	}
+	if config.HostKey == "" {
+		return nil, errors.New("host_key is required for verifying the SFTP server")
+	}
+
+	auth := []ssh.AuthMethod{}
+	if config.PrivateKey != "" {
+		key, err := ioutil.ReadFile(config.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if config.Password != "" {
+		auth = append(auth, ssh.Password(config.Password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("password or private_key is required")
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = sftpDefaultPort
+	}
+	directory := config.Directory
+	if directory == "" {
+		directory = "."
+	}
+	return &sftpTarget{
+		address:   net.JoinHostPort(config.Host, strconv.Itoa(port)),
+		directory: directory,
+		config: &ssh.ClientConfig{
+			User:            config.Username,
+			Auth:            auth,
+			HostKeyCallback: verifyHostKey(config.HostKey),
+			Timeout:         sftpDialTimeout,
+		},
+	}, nil
+}
+
+// verifyHostKey accepts only the host key with the given SHA256 fingerprint
+func verifyHostKey(fingerprint string) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if ssh.FingerprintSHA256(key) != fingerprint {
+			return fmt.Errorf("host key of %s does not match the fingerprint %s", hostname, fingerprint)
+		}
+		return nil
+	}
+}
+
+// sftpSession is a connection to the SFTP server
+type sftpSession struct {
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+func (session *sftpSession) Close() error {
+	session.client.Close()
+	return session.conn.Close()
+}
+
+func (target *sftpTarget) connect() (*sftpSession, error) {
+	conn, err := ssh.Dial("tcp", target.address, target.config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &sftpSession{conn: conn, client: client}, nil
+}
+
+func (target *sftpTarget) path(key string) string {
+	return path.Join(target.directory, key)
+}
+
+// Put writes the object to a temporary file which is renamed once it is complete
+func (target *sftpTarget) Put(key string, reader io.Reader) (err error) {
+	session, err := target.connect()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	destination := target.path(key)
+	if err := session.client.MkdirAll(path.Dir(destination)); err != nil {
+		return err
+	}
+	partial := destination + ".part"
+	file, err := session.client.Create(partial)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			session.client.Remove(partial)
+		}
+	}()
+
+	if _, err = io.Copy(file, reader); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return session.client.PosixRename(partial, destination)
+}
+
+// sftpReader closes the connection to the SFTP server along with the file being read
+type sftpReader struct {
+	*sftp.File
+	session *sftpSession
+}
+
+func (reader *sftpReader) Close() error {
+	reader.File.Close()
+	return reader.session.Close()
+}
+
+func (target *sftpTarget) Get(key string) (io.ReadCloser, error) {
+	session, err := target.connect()
+	if err != nil {
+		return nil, err
+	}
+	file, err := session.client.Open(target.path(key))
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	return &sftpReader{File: file, session: session}, nil
+}
+
+func (target *sftpTarget) Delete(key string) error {
+	session, err := target.connect()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if _, err := session.client.Stat(target.path(key)); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return session.client.Remove(target.path(key))
+}
+
+func (target *sftpTarget) Shared() bool {
+	return true
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sdslabs/gasper/configs"
+)
+
+const (
+	// Local is the type of the targets storing the backups in a directory of the current node
+	Local = "local"
+
+	// S3 is the type of the targets storing the backups in a bucket of an S3-compatible object storage
+	S3 = "s3"
+
+	// SFTP is the type of the targets storing the backups in a directory of a SFTP server
+	SFTP = "sftp"
+
+	// DefaultTarget is the name of the target used when no targets are configured
+	DefaultTarget = "local"
+
+	// LegacyTarget is the target of the backups taken before storage targets were introduced
+	// whose paths are absolute paths of files in the current node
+	LegacyTarget = ""
+)
+
+// Target is a storage target in which the backups of databases are stored as objects addressed by keys
+type Target interface {
+	// Put stores the contents of the reader at the key, replacing the existing object if any
+	// The object is not stored partially if the reader fails
+	Put(key string, reader io.Reader) error
+
+	// Get returns a reader for the object stored at the key
+	Get(key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored at the key, it does not fail if the object does not exist
+	Delete(key string) error
+
+	// Shared checks whether the objects stored in the target can be accessed from all the nodes
+	Shared() bool
+}
+
+// Default returns the name of the target in which the new backups are stored
+// and whether the backups are encrypted
+func Default() (string, bool) {
+	name := configs.BackupsConfig.Target
+	if name == "" {
+		name = DefaultTarget
+	}
+	return name, configs.BackupsConfig.Targets[name].Encrypt
+}
+
+// Open returns the storage target with the given name
+// The objects are encrypted and decrypted with the key of the target if required
+func Open(name string, encrypted bool) (Target, error) {
+	if name == LegacyTarget {
+		return &localTarget{}, nil
+	}
+	config, ok := configs.BackupsConfig.Targets[name]
+	if !ok {
+		if name != DefaultTarget {
+			return nil, fmt.Errorf("Storage target %s is not configured", name)
+		}
+		config = configs.StorageTarget{Type: Local}
+	}
+
+	var target Target
+	var err error
+	switch config.Type {
+	case Local, "":
+		target, err = newLocalTarget(config)
+	case S3:
+		target, err = newS3Target(config)
+	case SFTP:
+		target, err = newSFTPTarget(config)
+	default:
+		return nil, fmt.Errorf("Storage target %s has invalid type `%s`", name, config.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Storage target %s is invalid : %s", name, err)
+	}
+	if encrypted {
+		return newEncryptedTarget(target, name), nil
+	}
+	return target, nil
+}
+
+// defaultDirectory returns the directory in which the backups are stored by local targets without one
+func defaultDirectory() string {
+	cwd, _ := os.Getwd()
+	return filepath.Join(cwd, "backups")
+}
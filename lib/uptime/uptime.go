@@ -0,0 +1,259 @@
+package uptime
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sdslabs/gasper/types"
+)
+
+// SlotSize is the length (in seconds) of the periods in which the availability of applications is measured
+// It matches the 5-minute rollups of the metrics
+const SlotSize int64 = 5 * 60
+
+const (
+	// ContainerCause is the cause of incidents in which the application's container was not alive
+	ContainerCause = "container"
+
+	// ProbeCause is the cause of incidents in which the HTTP probes sent to the application failed
+	ProbeCause = "probe"
+)
+
+// Period is a rolling window over which the availability of applications is reported
+type Period struct {
+	Name   string
+	Length time.Duration
+}
+
+// Periods are the windows over which the availability of applications is reported
+var Periods = []Period{
+	{"day", 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+}
+
+// LookupPeriod returns the period with the given name
+func LookupPeriod(name string) (Period, bool) {
+	for _, period := range Periods {
+		if period.Name == name {
+			return period, true
+		}
+	}
+	return Period{}, false
+}
+
+// Summary is the availability of an application over a period
+type Summary struct {
+	Period       string    `json:"period"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Availability *float64  `json:"availability"`
+	Downtime     int64     `json:"downtime"`
+	Observed     int64     `json:"observed"`
+	Incidents    int       `json:"incidents"`
+	MeetsTarget  *bool     `json:"meets_target,omitempty"`
+}
+
+// Incident is a window of consecutive periods in which an application was not fully available
+type Incident struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Downtime  int64     `json:"downtime"`
+	Causes    []string  `json:"causes"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// slot is the state of an application in a 5-minute period
+type slot struct {
+	container    float64
+	hasContainer bool
+	probes       int64
+	failures     int64
+	lastError    string
+}
+
+// availability returns the fraction of the slot for which the application was available
+// and whether the application was observed at all in it
+// The application must be both alive and reachable to be available
+func (s *slot) availability() (float64, bool) {
+	available := 1.0
+	observed := false
+	if s.hasContainer {
+		available = math.Min(available, s.container)
+		observed = true
+	}
+	if s.probes > 0 {
+		available = math.Min(available, 1-float64(s.failures)/float64(s.probes))
+		observed = true
+	}
+	return available, observed
+}
+
+// causes returns the reasons for which the application was not fully available in the slot
+func (s *slot) causes() []string {
+	causes := []string{}
+	if s.hasContainer && s.container < 1 {
+		causes = append(causes, ContainerCause)
+	}
+	if s.failures > 0 {
+		causes = append(causes, ProbeCause)
+	}
+	return causes
+}
+
+// Timeline holds the state of an application in every 5-minute period keyed by its start
+type Timeline map[int64]*slot
+
+// SlotStart returns the start of the 5-minute period containing the given timestamp
+func SlotStart(timestamp int64) int64 {
+	return timestamp - timestamp%SlotSize
+}
+
+func (timeline Timeline) slot(timestamp int64) *slot {
+	start := SlotStart(timestamp)
+	if _, ok := timeline[start]; !ok {
+		timeline[start] = &slot{}
+	}
+	return timeline[start]
+}
+
+// AddRollup adds the liveness of an application's container from a 5-minute rollup of its metrics
+func (timeline Timeline) AddRollup(rollup *types.MetricsRollup) {
+	if rollup.Samples == 0 {
+		return
+	}
+	s := timeline.slot(rollup.Timestamp)
+	s.container = rollup.Uptime
+	s.hasContainer = true
+}
+
+// AddSamples adds the liveness of an application's container from its raw metrics
+// for the periods which are not rolled up yet
+func (timeline Timeline) AddSamples(samples []*types.Metrics) {
+	alive := make(map[int64]int)
+	total := make(map[int64]int)
+	for _, sample := range samples {
+		start := SlotStart(sample.ReadTime)
+		if s, ok := timeline[start]; ok && s.hasContainer {
+			continue
+		}
+		total[start]++
+		if sample.Alive {
+			alive[start]++
+		}
+	}
+	for start, count := range total {
+		s := timeline.slot(start)
+		s.container = float64(alive[start]) / float64(count)
+		s.hasContainer = true
+	}
+}
+
+// AddMissing marks the container of an application as down in the 5-minute periods fully within
+// the given timestamps in which no metrics were collected, AppMaker stops collecting the metrics
+// of a container once it stops hence their absence while the application is deployed means downtime
+// It must be called after adding the rollups and the raw metrics of the application
+func (timeline Timeline) AddMissing(from, to int64) {
+	for timestamp := SlotStart(from + SlotSize - 1); timestamp+SlotSize <= to; timestamp += SlotSize {
+		s := timeline.slot(timestamp)
+		if !s.hasContainer {
+			s.container = 0
+			s.hasContainer = true
+		}
+	}
+}
+
+// AddProbes adds the results of the HTTP probes sent to an application in a 5-minute period
+func (timeline Timeline) AddProbes(probes *types.ProbeSlot) {
+	s := timeline.slot(probes.Timestamp)
+	s.probes += probes.Probes
+	s.failures += probes.Failures
+	if probes.LastError != "" {
+		s.lastError = probes.LastError
+	}
+}
+
+// Summarize returns the availability of an application over the period ending at the given time
+// The target is the availability (in percent) promised by the SLA of the application, 0 if there is none
+func (timeline Timeline) Summarize(period Period, end time.Time, target float64) *Summary {
+	start := end.Add(-period.Length)
+	summary := &Summary{
+		Period:    period.Name,
+		Start:     start,
+		End:       end,
+		Incidents: len(timeline.Incidents(start, end)),
+	}
+
+	var available float64
+	for timestamp := SlotStart(start.Unix()); timestamp < end.Unix(); timestamp += SlotSize {
+		s, ok := timeline[timestamp]
+		if !ok {
+			continue
+		}
+		availability, observed := s.availability()
+		if !observed {
+			continue
+		}
+		available += availability
+		summary.Observed += SlotSize
+		summary.Downtime += int64(math.Round((1 - availability) * float64(SlotSize)))
+	}
+
+	if summary.Observed > 0 {
+		percent := math.Round(available*float64(SlotSize)/float64(summary.Observed)*1e5) / 1e3
+		summary.Availability = &percent
+		if target > 0 {
+			meetsTarget := percent >= target
+			summary.MeetsTarget = &meetsTarget
+		}
+	}
+	return summary
+}
+
+// Incidents returns the windows of consecutive 5-minute periods in which an application
+// was not fully available between the given times, from the latest to the oldest
+func (timeline Timeline) Incidents(start, end time.Time) []*Incident {
+	incidents := []*Incident{}
+	var current *Incident
+	for timestamp := SlotStart(start.Unix()); timestamp < end.Unix(); timestamp += SlotSize {
+		s, ok := timeline[timestamp]
+		if !ok {
+			current = nil
+			continue
+		}
+		availability, observed := s.availability()
+		if !observed || availability >= 1 {
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = &Incident{Start: time.Unix(timestamp, 0)}
+			incidents = append(incidents, current)
+		}
+		current.End = time.Unix(timestamp+SlotSize, 0)
+		current.Downtime += int64(math.Round((1 - availability) * float64(SlotSize)))
+		for _, cause := range s.causes() {
+			if !contains(current.Causes, cause) {
+				current.Causes = append(current.Causes, cause)
+			}
+		}
+		if s.lastError != "" {
+			current.LastError = s.lastError
+		}
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].Start.After(incidents[j].Start)
+	})
+	return incidents
+}
+
+func contains(list []string, item string) bool {
+	for _, element := range list {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
@@ -27,3 +27,14 @@ func GetMapKeys(data map[string]string) []string {
 	}
 	return keys
 }
+
+// RemoveDuplicates returns the strings of the given array with their duplicates removed
+func RemoveDuplicates(s []string) []string {
+	unique := make([]string, 0, len(s))
+	for _, a := range s {
+		if !Contains(unique, a) {
+			unique = append(unique, a)
+		}
+	}
+	return unique
+}
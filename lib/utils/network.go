@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/miekg/dns"
@@ -10,6 +14,24 @@ import (
 	"github.com/sdslabs/gasper/types"
 )
 
+// connContextKey is the key of a request's context holding the connection it was received on
+type connContextKey struct{}
+
+// ConnContext stores the connection in the context of the requests received on it
+// It is meant to be used as the ConnContext of a HTTP server
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+// ClearWriteDeadline lifts the write timeout of the server for the response of a request
+// so that long-lived responses like server-sent events and WebSockets are not cut short
+// The server must store the connections in the context of the requests with ConnContext
+func ClearWriteDeadline(r *http.Request) {
+	if conn, ok := r.Context().Value(connContextKey{}).(net.Conn); ok {
+		conn.SetWriteDeadline(time.Time{})
+	}
+}
+
 // HostIP variable stores the IPv4 address of the host machine
 var HostIP, _ = GetOutboundIP()
 
@@ -105,3 +127,142 @@ func IsValidPort(port int) bool {
 
 	return true
 }
+
+// internalNetworks holds the address ranges which are not reachable over the internet
+var internalNetworks = parseCIDRs(
+	"0.0.0.0/8",      // Current network
+	"10.0.0.0/8",     // Private network
+	"100.64.0.0/10",  // Carrier-grade NAT
+	"127.0.0.0/8",    // Loopback
+	"169.254.0.0/16", // Link-local
+	"172.16.0.0/12",  // Private network
+	"192.0.0.0/24",   // IETF protocol assignments
+	"192.168.0.0/16", // Private network
+	"198.18.0.0/15",  // Benchmarking
+	"224.0.0.0/4",    // Multicast
+	"240.0.0.0/4",    // Reserved
+	"::/128",         // Unspecified
+	"::1/128",        // Loopback
+	"64:ff9b::/96",   // IPv4/IPv6 translation
+	"fc00::/7",       // Unique local
+	"fe80::/10",      // Link-local
+	"ff00::/8",       // Multicast
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, networks[i], _ = net.ParseCIDR(cidr)
+	}
+	return networks
+}
+
+// allowedNetworks holds the internal address ranges which are explicitly allowed
+// by the `allowed_networks` field of the log shipping configuration
+var allowedNetworks = parseAllowedNetworks(configs.ServiceConfig.LogShipping.AllowedNetworks)
+
+// parseAllowedNetworks parses the allowed networks given either as CIDRs or as single addresses
+func parseAllowedNetworks(entries []string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, entry := range entries {
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			LogError("Utils-Network-6", fmt.Errorf("allowed network %s is invalid: %s", entry, err.Error()))
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// ErrInternalAddress is the error when a connection to an internal address is refused
+var ErrInternalAddress = errors.New("connections to loopback, private and link-local addresses are not allowed")
+
+// IsPublicIP checks whether an IP address is reachable over the internet
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAllowedIP checks whether an IP address is either reachable over the internet
+// or belongs to one of the allowed networks
+func IsAllowedIP(ip net.IP) bool {
+	if IsPublicIP(ip) {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, network := range allowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePublicHost resolves a host and checks that all of its addresses are reachable over
+// the internet or belong to one of the allowed networks
+func ValidatePublicHost(host string) error {
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("host %s cannot be resolved: %s", host, err.Error())
+	}
+	for _, ip := range ips {
+		if !IsAllowedIP(ip) {
+			return fmt.Errorf("host %s resolves to %s: %s", host, ip, ErrInternalAddress.Error())
+		}
+	}
+	return nil
+}
+
+// NewPublicDialer returns a dialer which refuses to connect to internal addresses outside the allowed networks
+// The address is checked after it is resolved, hence a host resolving to a public
+// address at validation and to an internal one while connecting is refused as well
+func NewPublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsAllowedIP(net.ParseIP(host)) {
+				return ErrInternalAddress
+			}
+			return nil
+		},
+	}
+}
+
+// NewPublicHTTPClient returns a HTTP client which refuses to connect to internal addresses outside
+// the allowed networks including the ones it is redirected to
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         NewPublicDialer(timeout).DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
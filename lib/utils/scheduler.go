@@ -1,9 +1,20 @@
 package utils
 
-import "time"
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SchedulerObserver is notified after every run of a scheduled task with the name of the task,
+// the lag between the time the task was due and the time it started and the time taken by it
+var SchedulerObserver func(job string, lag, duration time.Duration)
 
 // Scheduler deals with running and managing various tasks at defined intervals
 type Scheduler struct {
+	name        string
 	interval    time.Duration
 	task        func()
 	stopTrigger chan bool
@@ -13,11 +24,21 @@ type Scheduler struct {
 // NewScheduler returns a pointer to a Scheduler object
 func NewScheduler(interval time.Duration, task func()) *Scheduler {
 	return &Scheduler{
+		name:     taskName(task),
 		interval: interval,
 		task:     task,
 	}
 }
 
+// taskName returns the name of the function scheduled in the form `package.function`
+func taskName(task func()) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(task).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return strings.TrimSuffix(path.Base(fn.Name()), "-fm")
+}
+
 // Run starts scheduling the given task
 func (s *Scheduler) Run() {
 	if s.running {
@@ -27,8 +48,12 @@ func (s *Scheduler) Run() {
 	ticker := time.NewTicker(s.interval)
 	for {
 		select {
-		case <-ticker.C:
+		case tick := <-ticker.C:
+			start := time.Now()
 			s.task()
+			if SchedulerObserver != nil {
+				SchedulerObserver(s.name, start.Sub(tick), time.Since(start))
+			}
 		case <-s.stopTrigger:
 			ticker.Stop()
 			return
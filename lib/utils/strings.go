@@ -1,6 +1,14 @@
 package utils
 
-import "github.com/sdslabs/gasper/types"
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/sdslabs/gasper/types"
+)
+
+// randomCharset holds the characters used in the randomly generated strings
+const randomCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
 // QueryToFilter filters out queries from the URL parameters
 func QueryToFilter(queries map[string][]string) types.M {
@@ -12,3 +20,17 @@ func QueryToFilter(queries map[string][]string) types.M {
 
 	return filter
 }
+
+// GenerateRandomString returns a cryptographically secure random alphanumeric string of the given length
+func GenerateRandomString(length int) (string, error) {
+	result := make([]byte, length)
+	max := big.NewInt(int64(len(randomCharset)))
+	for i := range result {
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = randomCharset[index.Int64()]
+	}
+	return string(result), nil
+}
@@ -5,8 +5,10 @@ import (
 	"strings"
 
 	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/metrics"
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/services/appmaker"
+	"github.com/sdslabs/gasper/services/dbmaker"
 	"github.com/sdslabs/gasper/services/gendns"
 	"github.com/sdslabs/gasper/services/genproxy"
 	"github.com/sdslabs/gasper/services/master"
@@ -17,6 +19,13 @@ func initMaster() {
 	go master.ScheduleServiceExposure()
 	if configs.ServiceConfig.Master.Deploy {
 		go master.ScheduleCleanup()
+		go master.ScheduleMetricsRollup()
+		if configs.ServiceConfig.Master.Alerting.PlugIn {
+			go master.ScheduleAlerting()
+		}
+		if configs.ServiceConfig.Master.Uptime.PlugIn {
+			go master.ScheduleProbes()
+		}
 	}
 }
 
@@ -24,6 +33,22 @@ func initAppMaker() {
 	if configs.ServiceConfig.AppMaker.Deploy {
 		go appmaker.ScheduleMetricsCollection()
 		go appmaker.ScheduleHealthCheck()
+		go appmaker.ScheduleLogShipping()
+	}
+}
+
+func initDbMaker() {
+	if configs.ServiceConfig.DbMaker.Deploy && configs.ServiceConfig.LogShipping.PlugIn {
+		go dbmaker.ScheduleLogShipping()
+	}
+	if configs.ServiceConfig.DbMaker.Deploy && configs.ServiceConfig.DbMaker.Backup.PlugIn {
+		go dbmaker.ScheduleBackups()
+	}
+	if configs.ServiceConfig.DbMaker.Deploy {
+		go dbmaker.ScheduleMetricsCollection()
+	}
+	if configs.ServiceConfig.DbMaker.Deploy && configs.ServiceConfig.DbMaker.Quota.PlugIn {
+		go dbmaker.ScheduleQuotaChecks()
 	}
 }
 
@@ -39,6 +64,19 @@ func initGenProxy() {
 	}
 }
 
+func initExporter() {
+	if !configs.ServiceConfig.Exporter.Deploy {
+		return
+	}
+	if configs.ServiceConfig.AppMaker.Deploy {
+		appmaker.ExportContainerMetrics()
+	}
+	if configs.ServiceConfig.DbMaker.Deploy {
+		dbmaker.ExportContainerMetrics()
+	}
+	go metrics.ScheduleContainerStats()
+}
+
 func initServices() {
 	var g errgroup.Group
 	for service, launcher := range launcherBindings {
@@ -56,7 +94,9 @@ func initServices() {
 func main() {
 	initMaster()
 	initAppMaker()
+	initDbMaker()
 	initGenDNS()
 	initGenProxy()
+	initExporter()
 	initServices()
 }
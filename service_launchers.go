@@ -5,10 +5,12 @@ import (
 	"runtime"
 
 	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/database"
 	"github.com/sdslabs/gasper/lib/docker"
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/services/appmaker"
 	"github.com/sdslabs/gasper/services/dbmaker"
+	"github.com/sdslabs/gasper/services/exporter"
 	"github.com/sdslabs/gasper/services/gendns"
 	"github.com/sdslabs/gasper/services/genproxy"
 	"github.com/sdslabs/gasper/services/genssh"
@@ -40,6 +42,14 @@ var launcherBindings = map[string]*serviceLauncher{
 		Deploy: configs.ServiceConfig.GenDNS.Deploy,
 		Start:  gendns.NewService().ListenAndServe,
 	},
+	gendns.DoHServiceName: {
+		Deploy: configs.ServiceConfig.GenDNS.Deploy && configs.ServiceConfig.GenDNS.DoH.PlugIn,
+		Start:  startGenDNSServiceWithDoH,
+	},
+	gendns.DoTServiceName: {
+		Deploy: configs.ServiceConfig.GenDNS.Deploy && configs.ServiceConfig.GenDNS.DoT.PlugIn,
+		Start:  startGenDNSServiceWithDoT,
+	},
 	genproxy.DefaultServiceName: {
 		Deploy: configs.ServiceConfig.GenProxy.Deploy,
 		Start:  startGenProxyService,
@@ -56,23 +66,21 @@ var launcherBindings = map[string]*serviceLauncher{
 		Deploy: configs.ServiceConfig.Jikan.Deploy,
 		Start:  startJikanService,
 	},
+	exporter.ServiceName: {
+		Deploy: configs.ServiceConfig.Exporter.Deploy,
+		Start:  startExporterService,
+	},
 }
 
 func startDbMakerService() error {
-	if configs.ServiceConfig.DbMaker.MySQL.PlugIn {
-		docker.CheckAndPullImages(configs.ImageConfig.Mysql)
-		setupDatabaseContainer(types.MySQL)
-	}
-	if configs.ServiceConfig.DbMaker.MongoDB.PlugIn {
-		docker.CheckAndPullImages(configs.ImageConfig.Mongodb)
-		setupDatabaseContainer(types.MongoDB)
-	}
-	if configs.ServiceConfig.DbMaker.PostgreSQL.PlugIn {
-		docker.CheckAndPullImages(configs.ImageConfig.Postgresql)
-		setupDatabaseContainer(types.PostgreSQL)
-	}
-	if configs.ServiceConfig.DbMaker.Redis.PlugIn {
-		docker.CheckAndPullImages(configs.ImageConfig.Redis)
+	for _, engine := range database.Engines() {
+		if !engine.Config().PlugIn {
+			continue
+		}
+		docker.CheckAndPullImages(engine.Images()...)
+		if engine.Server != nil {
+			setupDatabaseContainer(engine.Name)
+		}
 	}
 	return startGrpcServer(dbmaker.NewService(), configs.ServiceConfig.DbMaker.Port)
 }
@@ -101,7 +109,7 @@ func startMasterService() error {
 		docker.CheckAndPullImages(configs.ImageConfig.Redis)
 		setupDatabaseContainer(types.RedisGasper)
 	}
-	return buildHTTPServer(master.NewService(), configs.ServiceConfig.Master.Port).ListenAndServe()
+	return buildStreamingHTTPServer(master.NewService(), configs.ServiceConfig.Master.Port).ListenAndServe()
 }
 
 func startGenProxyService() error {
@@ -136,3 +144,32 @@ func startGenProxyServiceWithSSL() error {
 func startJikanService() error {
 	return jikan.NewService().ListenAndServe()
 }
+
+func startExporterService() error {
+	return buildHTTPServer(exporter.NewService(), configs.ServiceConfig.Exporter.Port).ListenAndServe()
+}
+
+func startGenDNSServiceWithDoH() error {
+	port := configs.ServiceConfig.GenDNS.DoH.Port
+	certificate := configs.ServiceConfig.GenProxy.SSL.Certificate
+	privateKey := configs.ServiceConfig.GenProxy.SSL.PrivateKey
+	err := buildHTTPServer(gendns.NewDoHService(), port).ListenAndServeTLS(certificate, privateKey)
+	if err != nil {
+		utils.Log("Main-Launchers-5", "There was a problem deploying GenDNS Service with DNS-over-HTTPS", utils.ErrorTAG)
+		utils.Log("Main-Launchers-6", "Make sure the paths of certificate and private key are correct in `config.toml`", utils.ErrorTAG)
+		utils.LogError("Main-Launchers-7", err)
+		os.Exit(1)
+	}
+	return nil
+}
+
+func startGenDNSServiceWithDoT() error {
+	server, err := gendns.NewDoTService()
+	if err != nil {
+		utils.Log("Main-Launchers-8", "There was a problem deploying GenDNS Service with DNS-over-TLS", utils.ErrorTAG)
+		utils.Log("Main-Launchers-9", "Make sure the paths of certificate and private key are correct in `config.toml`", utils.ErrorTAG)
+		utils.LogError("Main-Launchers-10", err)
+		os.Exit(1)
+	}
+	return server.ListenAndServe()
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ServiceName is the name of the current microservice
@@ -26,7 +28,6 @@ type server struct{}
 
 // Create creates an application
 func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.ResponseBody, error) {
-	language := body.GetLanguage()
 	app := &types.ApplicationConfig{}
 
 	err := json.Unmarshal(body.GetData(), app)
@@ -34,7 +35,18 @@ func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.Response
 		return nil, err
 	}
 
-	user, err := mongo.FetchSingleUser(body.GetOwner())
+	response, err := createApp(body.GetLanguage(), body.GetOwner(), app)
+	if err != nil {
+		publishEvent(app.GetName(), types.AppFailedEvent, err.Error())
+		return nil, err
+	}
+	publishEvent(app.GetName(), types.AppCreatedEvent, "")
+	return response, nil
+}
+
+// createApp creates an application of the given language for the given owner
+func createApp(language, owner string, app *types.ApplicationConfig) (*pb.ResponseBody, error) {
+	user, err := mongo.FetchSingleUser(owner)
 	if err != nil {
 		return nil, err
 	}
@@ -43,8 +55,8 @@ func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.Response
 	rateCount := configs.ServiceConfig.RateLimit
 	timeInterval := configs.ServiceConfig.RateInterval
 	if !user.IsAdmin() && maxCount >= 0 {
-		rateLimitCount := mongo.CountInstanceInTimeFrame(body.GetOwner(), mongo.AppInstance, timeInterval)
-		totalCount := mongo.CountInstancesByUser(body.GetOwner(), mongo.AppInstance)
+		rateLimitCount := mongo.CountInstanceInTimeFrame(owner, mongo.AppInstance, timeInterval)
+		totalCount := mongo.CountInstancesByUser(owner, mongo.AppInstance)
 		if totalCount < maxCount {
 			if rateLimitCount >= rateCount && rateCount >= 0 {
 				return nil, fmt.Errorf("cannot deploy more than %d app instances in %d hours", rateCount, timeInterval)
@@ -55,7 +67,7 @@ func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.Response
 	}
 
 	app.SetLanguage(language)
-	app.SetOwner(body.GetOwner())
+	app.SetOwner(owner)
 	app.SetInstanceType(mongo.AppInstance)
 	app.SetHostIP(utils.HostIP)
 	app.SetNameServers(configs.GasperConfig.DNSServers)
@@ -171,8 +183,10 @@ func (s *server) Rebuild(ctx context.Context, body *pb.NameHolder) (*pb.Response
 	_, err = docker.ExecProcess(app.ContainerID, pullChanges)
 
 	if err != nil {
+		publishEvent(appName, types.AppFailedEvent, err.Error())
 		return nil, err
 	}
+	publishEvent(appName, types.AppRebuiltEvent, "")
 
 	response, err := json.Marshal(app)
 	return &pb.ResponseBody{Data: response}, err
@@ -199,6 +213,7 @@ func (s *server) Delete(ctx context.Context, body *pb.NameHolder) (*pb.DeletionR
 	if err != nil {
 		return nil, err
 	}
+	publishEvent(appName, types.AppDeletedEvent, "")
 	return &pb.DeletionResponse{Success: true}, nil
 }
 
@@ -218,6 +233,24 @@ func (s *server) FetchLogs(ctx context.Context, body *pb.LogRequest) (*pb.LogRes
 	}, nil
 }
 
+// FollowLogs streams the docker container logs of an application as they are written
+func (s *server) FollowLogs(body *pb.FollowLogRequest, stream pb.ApplicationFactory_FollowLogsServer) error {
+	filter, err := docker.NewLogFilter(body.GetTail(), body.GetSince(), body.GetUntil(), body.GetStream(), body.GetGrep())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	lines, err := docker.FollowLogs(stream.Context(), body.GetName(), filter)
+	if err != nil {
+		return err
+	}
+	for line := range lines {
+		if err := stream.Send(&pb.LogLine{Stream: line.Stream, Line: line.Line}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewService returns a new instance of the current microservice
 func NewService() *grpc.Server {
 	return factory.NewApplicationFactory(&server{})
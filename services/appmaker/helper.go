@@ -68,3 +68,10 @@ func FetchAllApplicationNames() []string {
 	}
 	return appNames
 }
+
+// publishEvent publishes a deploy event of an application to its subscribers
+func publishEvent(appName, event, message string) {
+	if err := redis.PublishDeployEvent(types.NewDeployEvent(appName, event, message, utils.HostIP)); err != nil {
+		utils.LogError("AppMaker-Helper-5", err)
+	}
+}
@@ -0,0 +1,50 @@
+package appmaker
+
+import (
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/logship"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// forwarder forwards the logs of the applications deployed in the current node to their drains
+var forwarder = logship.NewForwarder()
+
+// shippedContainers returns the containers of the applications deployed in the current node
+// Only the applications having drains are returned if the logs are not being stored in MongoDB
+func shippedContainers() []logship.Container {
+	apps := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.AppInstance,
+		mongo.HostIPKey:       utils.HostIP,
+	})
+	names := make([]string, 0, len(apps))
+	for _, app := range apps {
+		if name, ok := app[mongo.NameKey].(string); ok {
+			names = append(names, name)
+		}
+	}
+	forwarder.Refresh(names)
+
+	containers := make([]logship.Container, 0, len(names))
+	for _, name := range names {
+		if !configs.ServiceConfig.LogShipping.PlugIn && !forwarder.HasDrains(name) {
+			continue
+		}
+		containers = append(containers, logship.Container{
+			Name:         name,
+			InstanceType: mongo.AppInstance,
+		})
+	}
+	return containers
+}
+
+// ScheduleLogShipping ships the container logs of the applications deployed in the current node
+// to MongoDB and to the drains of the applications
+func ScheduleLogShipping() {
+	handlers := []logship.Handler{forwarder.Handle}
+	if configs.ServiceConfig.LogShipping.PlugIn {
+		handlers = append(handlers, logship.Store)
+	}
+	logship.Schedule(shippedContainers, handlers...)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/database"
 	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/lib/metrics"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/sdslabs/gasper/lib/mongo"
@@ -27,7 +28,7 @@ func registerMetrics() {
 			continue
 		}
 
-		containerStatus, err := docker.InspectContainerState(app)
+		container, err := docker.InspectContainer(app)
 		if err != nil {
 			utils.LogError("AppMaker-Monitor-2", err)
 			continue
@@ -58,17 +59,30 @@ func registerMetrics() {
 				utils.LogError("AppMaker-Monitor-12", fmt.Errorf("Error in getting logs of %s:,%s", app, err))
 			}
 		}
+		// network and block IO metrics
+		networkRx, networkTx := metrics.NetworkIO()
+		blockRead, blockWrite := metrics.BlockIO()
+
 		parsedMetrics := types.Metrics{
 			Name:           app,
-			Alive:          containerStatus.Running,
+			Alive:          container.State.Running,
 			ReadTime:       time.Now().Unix(),
 			MemoryUsage:    memoryUsage / memoryLimit,
 			MaxMemoryUsage: maxUsage / memoryLimit,
 			MemoryLimit:    memoryLimit / math.Pow(1024, 3),
 			OnlineCPUs:     onlineCPUs,
 			CPUUsage:       cpuTime / (math.Pow(10, 9) * onlineCPUs),
+			CPUPercent:     metrics.CPUPercent(),
+			NetworkRx:      networkRx,
+			NetworkTx:      networkTx,
+			BlockRead:      blockRead,
+			BlockWrite:     blockWrite,
+			PIDs:           int64(metrics.Pids.Current),
+			RestartCount:   int64(container.RestartCount),
+			OOMKilled:      container.State.OOMKilled,
 			HostIP:         utils.HostIP,
 			Logs:           logs,
+			ExpiresAt:      time.Now().Add(configs.ServiceConfig.MetricsRetention.RawWindow()),
 		}
 		if app == types.MySQL || app == types.PostgreSQL || app == types.MongoDB {
 			err = mongo.UpdateOneWithUpsert(mongo.MetricsCollection, types.M{"name": app}, parsedMetrics, options.Update().SetUpsert(true))
@@ -119,3 +133,27 @@ func ScheduleHealthCheck() {
 	scheduler := utils.NewScheduler(interval, checkContainerHealth)
 	scheduler.RunAsync()
 }
+
+// localContainers returns the containers of the applications deployed in the current node
+func localContainers() []metrics.Container {
+	apps := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.AppInstance,
+		mongo.HostIPKey:       utils.HostIP,
+	})
+	containers := make([]metrics.Container, 0, len(apps))
+	for _, app := range apps {
+		if name, ok := app[mongo.NameKey].(string); ok {
+			containers = append(containers, metrics.Container{
+				Name:         name,
+				InstanceType: mongo.AppInstance,
+			})
+		}
+	}
+	return containers
+}
+
+// ExportContainerMetrics exports the statistics of the application containers
+// deployed in the current node to Prometheus
+func ExportContainerMetrics() {
+	metrics.RegisterContainerSource(localContainers)
+}
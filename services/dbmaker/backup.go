@@ -0,0 +1,289 @@
+package dbmaker
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/database"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/storage"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	defaultBackupCheckInterval = 60 * time.Second
+	defaultBackupRetention     = 7
+
+	// backupCompression is the compression applied to the dumps of databases
+	backupCompression = "gzip"
+)
+
+// busyDatabases holds the databases whose backups are being taken or into which backups are
+// being restored in the current node
+var busyDatabases sync.Map
+
+// backupRetention returns the number of scheduled backups retained according to a backup schedule
+func backupRetention(schedule *types.BackupSchedule) int {
+	if schedule.Retention > 0 {
+		return schedule.Retention
+	}
+	if configs.ServiceConfig.DbMaker.Backup.Retention > 0 {
+		return configs.ServiceConfig.DbMaker.Backup.Retention
+	}
+	return defaultBackupRetention
+}
+
+// startBackup starts taking a backup of a database deployed in the current node and returns its metadata
+// The backup is taken in the background and its status is updated once it completes
+// The retention is the number of scheduled backups to retain afterwards, 0 if none are to be removed
+func startBackup(databaseName, trigger string, retention int) (*types.Backup, error) {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	if db.HostIP != utils.HostIP {
+		return nil, fmt.Errorf("Database %s is not deployed in the current node", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil || handler.Dump == nil {
+		return nil, fmt.Errorf("Database type `%s` does not support backups", db.GetLanguage())
+	}
+	target, encrypted := storage.Default()
+	if _, running := busyDatabases.LoadOrStore(databaseName, true); running {
+		return nil, fmt.Errorf("A backup or restore of database %s is already in progress", databaseName)
+	}
+
+	backup := &types.Backup{
+		ID:          uuid.New().String(),
+		Database:    databaseName,
+		Language:    db.GetLanguage(),
+		Owner:       db.Owner,
+		Node:        fmt.Sprintf("%s:%d", utils.HostIP, configs.ServiceConfig.DbMaker.Port),
+		HostIP:      utils.HostIP,
+		Trigger:     trigger,
+		Target:      target,
+		Encrypted:   encrypted,
+		Status:      types.BackupRunning,
+		Compression: backupCompression,
+		StartedAt:   time.Now(),
+	}
+	if _, err := mongo.RegisterBackup(backup); err != nil {
+		busyDatabases.Delete(databaseName)
+		return nil, err
+	}
+
+	go func() {
+		defer busyDatabases.Delete(databaseName)
+		takeBackup(db, handler, backup)
+		if retention > 0 {
+			enforceBackupRetention(databaseName, retention)
+		}
+	}()
+	return backup, nil
+}
+
+// takeBackup dumps a database, stores the compressed dump in the storage target of the backup and
+// records the result in the backup's metadata
+func takeBackup(db *types.DatabaseConfig, handler *databaseHandler, backup *types.Backup) {
+	update := types.M{}
+	var err error
+	// The snapshot of a database being moved to another node must hold all the writes made to it
+	if backup.Trigger == types.MigrationBackup {
+		err = freezeWrites(db, handler, true)
+	}
+	if err == nil {
+		err = storeBackup(db, handler, backup, update)
+	}
+	if err != nil {
+		utils.LogError("DbMaker-Backup-1", err)
+		update[mongo.StatusKey] = types.BackupFailed
+		update["error"] = err.Error()
+		if backup.Trigger == types.MigrationBackup {
+			if err := freezeWrites(db, handler, false); err != nil {
+				utils.LogError("DbMaker-Backup-13", err)
+			}
+		}
+	} else {
+		update[mongo.StatusKey] = types.BackupCompleted
+	}
+	update["completed_at"] = time.Now()
+
+	if err := mongo.UpdateBackup(types.M{mongo.BackupIDKey: backup.ID}, update); err != nil {
+		utils.LogError("DbMaker-Backup-2", err)
+	}
+}
+
+// storeBackup streams the compressed dump of a database into the storage target of the backup
+// The details of the stored backup are added to the update
+func storeBackup(db *types.DatabaseConfig, handler *databaseHandler, backup *types.Backup, update types.M) error {
+	target, err := storage.Open(backup.Target, backup.Encrypted)
+	if err != nil {
+		return err
+	}
+	dump, err := handler.Dump(db, backup.ID)
+	if err != nil {
+		return err
+	}
+
+	var rawSize, size int64
+	var checksum string
+	var exportErr error
+	reader, writer := io.Pipe()
+	exported := make(chan struct{})
+	go func() {
+		defer close(exported)
+		rawSize, size, checksum, exportErr = database.ExportDump(dump, writer)
+		writer.CloseWithError(exportErr)
+	}()
+
+	key := path.Join(db.GetName(), fmt.Sprintf("%s.%s.gz", backup.ID, dump.Format))
+	err = target.Put(key, reader)
+	reader.CloseWithError(err)
+	<-exported
+	if exportErr != nil {
+		return exportErr
+	}
+	if err != nil {
+		return err
+	}
+
+	update["format"] = dump.Format
+	update["path"] = key
+	update["raw_size"] = rawSize
+	update["size"] = size
+	update["checksum"] = checksum
+	return nil
+}
+
+// removeBackup deletes a backup from its storage target along with its metadata
+func removeBackup(backup *types.Backup) error {
+	if backup.Path != "" {
+		target, err := storage.Open(backup.Target, backup.Encrypted)
+		if err != nil {
+			return err
+		}
+		if err := target.Delete(backup.Path); err != nil {
+			return err
+		}
+	}
+	_, err := mongo.DeleteBackup(types.M{mongo.BackupIDKey: backup.ID})
+	return err
+}
+
+// isReachable checks whether a backup can be accessed from the current node
+func isReachable(backup *types.Backup) bool {
+	if backup.HostIP == utils.HostIP {
+		return true
+	}
+	target, err := storage.Open(backup.Target, backup.Encrypted)
+	return err == nil && target.Shared()
+}
+
+// enforceBackupRetention removes the scheduled backups of a database except the latest ones
+// Failed backups are pruned separately so that they never displace the completed ones
+func enforceBackupRetention(databaseName string, retention int) {
+	for _, status := range []string{types.BackupCompleted, types.BackupFailed} {
+		backups, err := mongo.FetchBackups(types.M{
+			mongo.DatabaseKey: databaseName,
+			mongo.TriggerKey:  types.ScheduledBackup,
+			mongo.StatusKey:   status,
+			mongo.HostIPKey:   utils.HostIP,
+		})
+		if err != nil {
+			utils.LogError("DbMaker-Backup-3", err)
+			return
+		}
+		if len(backups) <= retention {
+			continue
+		}
+		for _, backup := range backups[retention:] {
+			if err := removeBackup(backup); err != nil {
+				utils.LogError("DbMaker-Backup-4", err)
+			}
+		}
+	}
+}
+
+// removeDatabaseBackups deletes all the backups of a database which can be accessed from the current node
+// along with the metadata, the backup schedule and the restores of the database
+func removeDatabaseBackups(databaseName string) {
+	backups, err := mongo.FetchBackups(types.M{mongo.DatabaseKey: databaseName})
+	if err != nil {
+		utils.LogError("DbMaker-Backup-5", err)
+	}
+	for _, backup := range backups {
+		if !isReachable(backup) {
+			continue
+		}
+		if err := removeBackup(backup); err != nil {
+			utils.LogError("DbMaker-Backup-6", err)
+		}
+	}
+	if _, err := mongo.DeleteBackupSchedule(types.M{mongo.DatabaseKey: databaseName}); err != nil {
+		utils.LogError("DbMaker-Backup-7", err)
+	}
+	if _, err := mongo.DeleteRestores(types.M{mongo.DatabaseKey: databaseName}); err != nil {
+		utils.LogError("DbMaker-Backup-12", err)
+	}
+}
+
+// runScheduledBackups starts the due backups of the databases deployed in the current node
+func runScheduledBackups() {
+	dbs := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.DBInstance,
+		mongo.HostIPKey:       utils.HostIP,
+	})
+	names := make([]string, 0, len(dbs))
+	for _, db := range dbs {
+		if name, ok := db[mongo.NameKey].(string); ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	now := time.Now()
+	schedules, err := mongo.FetchBackupSchedules(types.M{
+		mongo.DatabaseKey: types.M{"$in": names},
+		mongo.NextRunKey:  types.M{"$lte": now},
+	})
+	if err != nil {
+		utils.LogError("DbMaker-Backup-8", err)
+		return
+	}
+	for _, schedule := range schedules {
+		interval, err := schedule.GetInterval()
+		if err != nil || interval <= 0 {
+			utils.Log("DbMaker-Backup-9", fmt.Sprintf("Invalid backup interval %s of database %s", schedule.Interval, schedule.Database), utils.ErrorTAG)
+			continue
+		}
+		claimed, err := mongo.ClaimBackupSchedule(schedule, now, now.Add(interval))
+		if err != nil {
+			utils.LogError("DbMaker-Backup-10", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if _, err := startBackup(schedule.Database, types.ScheduledBackup, backupRetention(schedule)); err != nil {
+			utils.LogError("DbMaker-Backup-11", err)
+		}
+	}
+}
+
+// ScheduleBackups runs runScheduledBackups at the given check interval
+func ScheduleBackups() {
+	interval := configs.ServiceConfig.DbMaker.Backup.CheckInterval * time.Second
+	if interval <= 0 {
+		interval = defaultBackupCheckInterval
+	}
+	scheduler := utils.NewScheduler(interval, runScheduledBackups)
+	scheduler.RunAsync()
+}
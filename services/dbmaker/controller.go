@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/cloudflare"
+	"github.com/sdslabs/gasper/lib/database"
+	"github.com/sdslabs/gasper/lib/docker"
 	"github.com/sdslabs/gasper/lib/factory"
 	pb "github.com/sdslabs/gasper/lib/factory/protos/database"
 	"github.com/sdslabs/gasper/lib/mongo"
@@ -13,81 +17,134 @@ import (
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ServiceName is the name of the current microservice
 const ServiceName = types.DbMaker
 
-type server struct{}
+// rootPasswordLength is the length of the passwords of the root users of the servers of dedicated databases
+const rootPasswordLength = 32
 
-// Create creates a database of the specified type
-func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.ResponseBody, error) {
-	language := body.GetLanguage()
-	db := &types.DatabaseConfig{}
+// backupChunkSize is the size of the chunks in which the backups are streamed to other nodes
+const backupChunkSize = 64 * 1024
 
-	err := json.Unmarshal(body.GetData(), db)
-	if err != nil {
-		return nil, err
-	}
+type server struct{}
 
-	user, err := mongo.FetchSingleUser(body.GetOwner())
+// createDatabase creates a database of the specified type owned by the user and registers it
+func createDatabase(language, owner string, db *types.DatabaseConfig) error {
+	user, err := mongo.FetchSingleUser(owner)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	maxCount := configs.ServiceConfig.DbMaker.DBLimit
 	rateCount := configs.ServiceConfig.RateLimit
 	timeInterval := configs.ServiceConfig.RateInterval
 	if user.IsAdmin() == false && maxCount >= 0 {
-		rateLimitCount := mongo.CountInstanceInTimeFrame(body.GetOwner(), mongo.DBInstance, timeInterval)
-		totalCount := mongo.CountInstancesByUser(body.GetOwner(), mongo.DBInstance)
+		rateLimitCount := mongo.CountInstanceInTimeFrame(owner, mongo.DBInstance, timeInterval)
+		totalCount := mongo.CountInstancesByUser(owner, mongo.DBInstance)
 		if totalCount < maxCount {
 			if rateLimitCount >= rateCount && rateCount >= 0 {
-				return nil, fmt.Errorf("Cannot deploy more than %d db instances in %d hours", rateCount, timeInterval)
+				return fmt.Errorf("Cannot deploy more than %d db instances in %d hours", rateCount, timeInterval)
 			}
 		} else {
-			return nil, fmt.Errorf("Cannot deploy more than %d db instances", maxCount)
+			return fmt.Errorf("Cannot deploy more than %d db instances", maxCount)
 		}
 	}
 
 	db.SetInstanceType(mongo.DBInstance)
 	db.SetHostIP(utils.HostIP)
 	db.SetUser(db.GetName())
-	db.SetOwner(body.GetOwner())
+	db.SetOwner(owner)
+	db.SetQuota(defaultQuota())
+	db.QuotaExceeded = false
 	db.SetDateTime()
 
 	if pipeline[language] == nil {
-		return nil, fmt.Errorf("Database type `%s` is not supported", language)
+		return fmt.Errorf("Database type `%s` is not supported", language)
+	}
+	if err := setupDedicatedDatabase(pipeline[language], db); err != nil {
+		return err
 	}
 
 	pipeline[language].init(db)
+	handler := fetchHandler(db)
 
-	err = pipeline[language].create(db)
+	err = handler.Create(db)
+	if err == database.ErrDatabaseExists {
+		return err
+	}
 	if err != nil {
-		go pipeline[language].cleanup(db.GetName())
-		return nil, err
+		go handler.cleanup(db.GetName())
+		return err
 	}
 
+	if err := registerDatabase(language, db); err != nil {
+		go handler.cleanup(db.GetName())
+		return err
+	}
+
+	db.SetSuccess(true)
+	return nil
+}
+
+// setupDedicatedDatabase checks the version of the server of a dedicated database against the versions
+// allowed for its type and generates the password of the root user of the server
+// The databases of the engines without a shared server are always deployed in their own containers
+func setupDedicatedDatabase(handler *databaseHandler, db *types.DatabaseConfig) error {
+	if handler.Dedicated == nil {
+		if db.GetVersion() != "" {
+			return fmt.Errorf("Database type `%s` does not support choosing the version of its server", handler.Name)
+		}
+		return nil
+	}
+	if !db.IsDedicated() {
+		if db.GetVersion() != "" {
+			return fmt.Errorf("Version of the server can only be chosen for dedicated databases")
+		}
+		return nil
+	}
+	if db.GetVersion() != "" && !utils.Contains(handler.Config().Versions, db.GetVersion()) {
+		return fmt.Errorf("Version `%s` of database type `%s` is not available, choose one of %v",
+			db.GetVersion(), handler.Name, handler.Config().Versions)
+	}
+	password, err := utils.GenerateRandomString(rootPasswordLength)
+	if err != nil {
+		return err
+	}
+	db.SetRootPassword(password)
+	return nil
+}
+
+// registerDatabase registers a database deployed in the current node in MongoDB, Redis and
+// Cloudflare if enabled, so that it is reachable
+func registerDatabase(language string, db *types.DatabaseConfig) error {
 	db.SetDbURL(fmt.Sprintf("%s.%s.%s", db.GetName(), cloudflare.DatabaseInstance, configs.GasperConfig.Domain))
 
 	if configs.CloudflareConfig.PlugIn {
+		// The record pointing to the node a migrated database was deployed in is replaced
+		if db.CloudflareID != "" {
+			if _, err := cloudflare.DeleteRecord(db.GetName(), cloudflare.DatabaseInstance); err != nil {
+				utils.LogError("DbMaker-Controller-1", err)
+			}
+		}
 		resp, err := cloudflare.CreateDatabaseRecord(db.GetName())
 		if err != nil {
-			go pipeline[language].cleanup(db.GetName())
-			return nil, err
+			return err
 		}
 		db.SetCloudflareID(resp.Result.ID)
 		db.SetPublicIP(configs.CloudflareConfig.PublicIP)
 	}
 
-	err = mongo.UpsertInstance(
+	err := mongo.UpsertInstance(
 		types.M{
 			mongo.NameKey:         db.GetName(),
 			mongo.InstanceTypeKey: mongo.DBInstance,
 		}, db)
 	if err != nil && err != mongo.ErrNoDocuments {
-		go pipeline[language].cleanup(db.GetName())
-		return nil, err
+		return err
 	}
 
 	err = redis.RegisterDB(
@@ -96,8 +153,7 @@ func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.Response
 		fmt.Sprintf("%s:%d", utils.HostIP, db.GetContainerPort()),
 	)
 	if err != nil {
-		go pipeline[language].cleanup(db.GetName())
-		return nil, err
+		return err
 	}
 
 	err = redis.IncrementServiceLoad(
@@ -105,12 +161,21 @@ func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.Response
 		fmt.Sprintf("%s:%d", utils.HostIP, configs.ServiceConfig.DbMaker.Port),
 	)
 	if err != nil {
-		go pipeline[language].cleanup(db.GetName())
-		return nil, err
+		return err
 	}
 
-	db.SetSuccess(true)
+	return nil
+}
 
+// Create creates a database of the specified type
+func (s *server) Create(ctx context.Context, body *pb.RequestBody) (*pb.ResponseBody, error) {
+	db := &types.DatabaseConfig{}
+	if err := json.Unmarshal(body.GetData(), db); err != nil {
+		return nil, err
+	}
+	if err := createDatabase(body.GetLanguage(), body.GetOwner(), db); err != nil {
+		return nil, err
+	}
 	response, err := json.Marshal(db)
 	return &pb.ResponseBody{Data: response}, err
 }
@@ -121,10 +186,15 @@ func (s *server) Delete(ctx context.Context, body *pb.NameHolder) (*pb.GenericRe
 	if err != nil {
 		return nil, err
 	}
-	if pipeline[language] == nil {
+	handler := pipeline[language]
+	if handler == nil {
 		return nil, fmt.Errorf("Database type `%s` is not supported", language)
 	}
-	err = pipeline[language].delete(body.GetName())
+	if db, err := mongo.FetchSingleDatabase(body.GetName()); err == nil {
+		handler = fetchHandler(db)
+		removeDatabaseUsers(db, handler)
+	}
+	err = handler.Delete(body.GetName())
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +207,11 @@ func (s *server) Delete(ctx context.Context, body *pb.NameHolder) (*pb.GenericRe
 		mongo.InstanceTypeKey: mongo.DBInstance,
 	}
 	_, err = mongo.DeleteInstance(filter)
+	// The queries are not to be read by the owner of a database created later with the same name
+	if _, err := mongo.DeleteQueryAudits(types.M{mongo.DatabaseKey: body.GetName()}); err != nil {
+		utils.LogError("DbMaker-Query-2", err)
+	}
+	go removeDatabaseBackups(body.GetName())
 	return &pb.GenericResponse{Success: true}, err
 }
 
@@ -153,6 +228,28 @@ func (s *server) FetchLogs(ctx context.Context, body *pb.LogRequest) (*pb.LogRes
 	}, err
 }
 
+// FollowLogs streams the docker logs from the specified database server's container as they are written
+func (s *server) FollowLogs(body *pb.FollowLogRequest, stream pb.DatabaseFactory_FollowLogsServer) error {
+	language := body.GetLanguage()
+	if pipeline[language] == nil {
+		return status.Errorf(codes.InvalidArgument, "Database type `%s` is not supported", language)
+	}
+	filter, err := docker.NewLogFilter(body.GetTail(), body.GetSince(), body.GetUntil(), body.GetStream(), body.GetGrep())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	lines, err := docker.FollowLogs(stream.Context(), language, filter)
+	if err != nil {
+		return err
+	}
+	for line := range lines {
+		if err := stream.Send(&pb.LogLine{Stream: line.Stream, Line: line.Line}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Reload restarts the specified database server
 func (s *server) Reload(ctx context.Context, body *pb.LanguageHolder) (*pb.GenericResponse, error) {
 	language := body.GetLanguage()
@@ -163,6 +260,206 @@ func (s *server) Reload(ctx context.Context, body *pb.LanguageHolder) (*pb.Gener
 	return &pb.GenericResponse{Success: true}, err
 }
 
+// Backup starts taking a backup of the specified database and returns its metadata
+func (s *server) Backup(ctx context.Context, body *pb.BackupRequest) (*pb.ResponseBody, error) {
+	trigger := body.GetTrigger()
+	if trigger == "" {
+		trigger = types.ManualBackup
+	}
+	backup, err := startBackup(body.GetName(), trigger, 0)
+	if err != nil {
+		return nil, err
+	}
+	response, err := json.Marshal(backup)
+	return &pb.ResponseBody{Data: response}, err
+}
+
+// DeleteBackup deletes a backup of the specified database which can be accessed from the current node
+func (s *server) DeleteBackup(ctx context.Context, body *pb.BackupHolder) (*pb.GenericResponse, error) {
+	backups, err := mongo.FetchBackups(types.M{
+		mongo.BackupIDKey: body.GetId(),
+		mongo.DatabaseKey: body.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) == 0 || !isReachable(backups[0]) {
+		return nil, fmt.Errorf("Backup %s of database %s cannot be accessed from the current node", body.GetId(), body.GetName())
+	}
+	if backups[0].Status == types.BackupRunning {
+		return nil, fmt.Errorf("Backup %s of database %s is in progress", body.GetId(), body.GetName())
+	}
+	if err := removeBackup(backups[0]); err != nil {
+		return nil, err
+	}
+	if backups[0].Trigger == types.MigrationBackup {
+		if err := thawDatabase(body.GetName()); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+// FetchBackup streams a completed backup of the specified database which can be accessed from the current node
+// The backup is decrypted before it is streamed if it is encrypted in its storage target
+func (s *server) FetchBackup(body *pb.BackupHolder, stream pb.DatabaseFactory_FetchBackupServer) error {
+	backups, err := mongo.FetchBackups(types.M{
+		mongo.BackupIDKey: body.GetId(),
+		mongo.DatabaseKey: body.GetName(),
+		mongo.StatusKey:   types.BackupCompleted,
+	})
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 || !isReachable(backups[0]) {
+		return status.Errorf(codes.NotFound, "Completed backup %s of database %s cannot be accessed from the current node", body.GetId(), body.GetName())
+	}
+	reader, err := openBackup(backups[0])
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	buffer := make([]byte, backupChunkSize)
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			if err := stream.Send(&pb.BackupChunk{Data: buffer[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Restore starts restoring a backup of the specified database into it and returns the state of the restore
+func (s *server) Restore(ctx context.Context, body *pb.RestoreRequest) (*pb.ResponseBody, error) {
+	if body.GetBackup() == "" {
+		return nil, fmt.Errorf("No backup given for restoring database %s", body.GetName())
+	}
+	restore, err := startRestore(body.GetName(), body.GetName(), body.GetBackup(), types.RestoreOperation)
+	if err != nil {
+		return nil, err
+	}
+	response, err := json.Marshal(restore)
+	return &pb.ResponseBody{Data: response}, err
+}
+
+// Clone creates a database of the specified type and starts restoring a backup of the source database into it,
+// or a snapshot of its live state if no backup is given
+// It returns the new database along with the state of the restore
+func (s *server) Clone(ctx context.Context, body *pb.CloneRequest) (*pb.ResponseBody, error) {
+	language := body.GetLanguage()
+	db := &types.DatabaseConfig{}
+	if err := json.Unmarshal(body.GetData(), db); err != nil {
+		return nil, err
+	}
+	if err := createDatabase(language, body.GetOwner(), db); err != nil {
+		return nil, err
+	}
+	restore, err := startRestore(db.GetName(), body.GetSource(), body.GetBackup(), types.CloneOperation)
+	if err != nil {
+		go fetchHandler(db).cleanup(db.GetName())
+		return nil, err
+	}
+	response, err := json.Marshal(types.M{
+		"database": db,
+		"restore":  restore,
+	})
+	return &pb.ResponseBody{Data: response}, err
+}
+
+// RotatePassword changes the password of a user of the specified database, or of the user
+// of the database itself if no username is given
+func (s *server) RotatePassword(ctx context.Context, body *pb.CredentialRequest) (*pb.GenericResponse, error) {
+	if err := rotatePassword(body.GetName(), body.GetUsername(), body.GetPassword()); err != nil {
+		return nil, err
+	}
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+// CreateUser creates an additional user of the specified database with a read-only or a read-write role
+func (s *server) CreateUser(ctx context.Context, body *pb.CredentialRequest) (*pb.GenericResponse, error) {
+	err := createUser(&types.DatabaseUser{
+		Database: body.GetName(),
+		Username: body.GetUsername(),
+		Password: body.GetPassword(),
+		Role:     body.GetRole(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+// DeleteUser deletes an additional user of the specified database
+func (s *server) DeleteUser(ctx context.Context, body *pb.CredentialRequest) (*pb.GenericResponse, error) {
+	if err := deleteUser(body.GetName(), body.GetUsername()); err != nil {
+		return nil, err
+	}
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+// Migrate starts moving the specified database into the current node from the source node, or from its latest
+// backup if the node it was deployed in is lost, and returns the state of the migration
+func (s *server) Migrate(ctx context.Context, body *pb.MigrateRequest) (*pb.ResponseBody, error) {
+	restore, err := startMigration(body.GetName(), body.GetSource())
+	if err != nil {
+		return nil, err
+	}
+	response, err := json.Marshal(restore)
+	return &pb.ResponseBody{Data: response}, err
+}
+
+// Evict removes the specified database from the current node once it is moved to another node
+func (s *server) Evict(ctx context.Context, body *pb.NameHolder) (*pb.GenericResponse, error) {
+	if err := evictDatabase(body.GetName()); err != nil {
+		return nil, err
+	}
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+// Usage returns the resources consumed by a database along with its quota
+func (s *server) Usage(ctx context.Context, body *pb.NameHolder) (*pb.ResponseBody, error) {
+	usage, err := fetchUsage(body.GetName())
+	if err != nil {
+		return nil, err
+	}
+	response, err := json.Marshal(usage)
+	return &pb.ResponseBody{Data: response}, err
+}
+
+// UpdateQuota changes the quota of a database
+func (s *server) UpdateQuota(ctx context.Context, body *pb.QuotaRequest) (*pb.GenericResponse, error) {
+	quota := types.DatabaseQuota{}
+	if err := json.Unmarshal(body.GetData(), &quota); err != nil {
+		return nil, err
+	}
+	if err := updateQuota(body.GetName(), quota); err != nil {
+		return nil, err
+	}
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+// Query runs an ad hoc query on a database through its query console
+func (s *server) Query(ctx context.Context, body *pb.QueryRequest) (*pb.ResponseBody, error) {
+	query := &types.DatabaseQuery{}
+	if err := json.Unmarshal(body.GetData(), query); err != nil {
+		return nil, err
+	}
+	result, err := runQuery(body.GetName(), body.GetUser(), query)
+	if err != nil {
+		return nil, err
+	}
+	response, err := json.Marshal(result)
+	return &pb.ResponseBody{Data: response}, err
+}
+
 // NewService returns a new instance of the current microservice
 func NewService() *grpc.Server {
 	return factory.NewDatabaseFactory(&server{})
@@ -0,0 +1,52 @@
+package dbmaker
+
+import (
+	"github.com/sdslabs/gasper/lib/logship"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// shippedContainers returns the containers of the database servers and the
+// databases having dedicated containers deployed in the current node
+func shippedContainers() []logship.Container {
+	containers := []logship.Container{}
+	dedicated := []string{}
+	for _, handler := range pipeline {
+		if handler.Server == nil {
+			dedicated = append(dedicated, handler.Name)
+		} else if handler.Config().PlugIn {
+			containers = append(containers, logship.Container{
+				Name:         handler.Name,
+				InstanceType: mongo.DBServerInstance,
+			})
+		}
+	}
+
+	// The databases of the engines without a shared server and the dedicated databases
+	// are deployed in their own containers
+	dbs := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.DBInstance,
+		mongo.HostIPKey:       utils.HostIP,
+		"$or": []types.M{
+			{mongo.LanguageKey: types.M{"$in": dedicated}},
+			{mongo.DedicatedKey: true},
+		},
+	})
+	for _, db := range dbs {
+		name, ok := db[mongo.NameKey].(string)
+		if !ok {
+			continue
+		}
+		containers = append(containers, logship.Container{
+			Name:         name,
+			InstanceType: mongo.DBInstance,
+		})
+	}
+	return containers
+}
+
+// ScheduleLogShipping ships the container logs of the database servers in the current node to MongoDB
+func ScheduleLogShipping() {
+	logship.Schedule(shippedContainers, logship.Store)
+}
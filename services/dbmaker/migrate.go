@@ -0,0 +1,247 @@
+package dbmaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/alerting"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// migrationEvent is the event notified to the owner of a database once it is migrated
+const migrationEvent = "Database migration"
+
+// latestReachableBackup returns the latest completed backup of a database which can be accessed from the current node
+func latestReachableBackup(databaseName string) (*types.Backup, error) {
+	backups, err := mongo.FetchBackups(types.M{
+		mongo.DatabaseKey: databaseName,
+		mongo.StatusKey:   types.BackupCompleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, backup := range backups {
+		if isReachable(backup) {
+			return backup, nil
+		}
+	}
+	return nil, fmt.Errorf("Database %s has no backup which can be restored in the current node", databaseName)
+}
+
+// startMigration starts moving a database deployed in the source node into the current node and returns
+// the state of the migration
+// A snapshot of the database is moved if the source node is given, otherwise its latest backup is restored
+// since the node it was deployed in is lost
+func startMigration(databaseName, source string) (*types.Restore, error) {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	if db.HostIP == utils.HostIP {
+		return nil, fmt.Errorf("Database %s is already deployed in the current node", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil || handler.Restore == nil {
+		return nil, fmt.Errorf("Database type `%s` does not support migrations", db.GetLanguage())
+	}
+	if _, busy := busyDatabases.LoadOrStore(databaseName, true); busy {
+		return nil, fmt.Errorf("A backup or restore of database %s is already in progress", databaseName)
+	}
+
+	restore := &types.Restore{
+		ID:        uuid.New().String(),
+		Database:  databaseName,
+		Source:    databaseName,
+		Operation: types.MigrateOperation,
+		Node:      utils.HostIP,
+		Status:    types.BackupRunning,
+		StartedAt: time.Now(),
+	}
+	if _, err := mongo.RegisterRestore(restore); err != nil {
+		busyDatabases.Delete(databaseName)
+		return nil, err
+	}
+
+	go func() {
+		defer busyDatabases.Delete(databaseName)
+		err := runRestore(restore, func(update types.M) error {
+			return migrateDatabase(db, handler, restore, source, update)
+		})
+		notifyMigration(db, restore, source, err)
+	}()
+	return restore, nil
+}
+
+// migrateDatabase creates a database along with its users in the current node, restores its snapshot or
+// latest backup and registers it, after which the database is removed from the source node if it is given
+func migrateDatabase(db *types.DatabaseConfig, handler *databaseHandler, restore *types.Restore, source string, update types.M) error {
+	if source == "" {
+		backup, err := latestReachableBackup(db.GetName())
+		if err != nil {
+			return err
+		}
+		restore.BackupID = backup.ID
+		update[mongo.BackupIDKey] = backup.ID
+	} else {
+		snapshot, err := takeSnapshot(db.GetName(), types.MigrationBackup)
+		if err != nil {
+			return err
+		}
+		// The snapshot is removed once the database is evicted from the source node, or it grants the
+		// writes to the database back in the source node if the database could not be moved
+		defer removeSnapshot(snapshot)
+		restore.BackupID = snapshot.ID
+		update[mongo.BackupIDKey] = snapshot.ID
+	}
+	users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: db.GetName()})
+	if err != nil {
+		return err
+	}
+
+	// The writes to the database are revoked again in the current node if it still exceeds its size quota
+	migrated := *db
+	migrated.SetHostIP(utils.HostIP)
+	migrated.QuotaExceeded = false
+	handler.init(&migrated)
+	if err := handler.Create(&migrated); err != nil {
+		return err
+	}
+	// The server of a dedicated database is bound to another port in the current node
+	handler = fetchHandler(&migrated)
+	if err := restoreBackup(&migrated, handler, restore, update); err != nil {
+		go handler.Delete(migrated.GetName())
+		return err
+	}
+	// The users are created after the restore so that they are granted privileges on the restored objects
+	for index, user := range users {
+		if handler.CreateUser == nil {
+			break
+		}
+		if err := handler.CreateUser(&migrated, user); err != nil {
+			for _, created := range users[:index] {
+				handler.DeleteUser(&migrated, created)
+			}
+			go handler.Delete(migrated.GetName())
+			return fmt.Errorf("Error while creating user %s : %s", user.Username, err)
+		}
+	}
+	if err := registerDatabase(migrated.GetLanguage(), &migrated); err != nil {
+		return err
+	}
+
+	if source != "" {
+		if _, err := factory.EvictDatabase(db.GetName(), source); err != nil {
+			utils.LogError("DbMaker-Migrate-1", err)
+		}
+	}
+	return nil
+}
+
+// freezeWrites revokes or grants back the writes to a database deployed in the current node while it is
+// moved to another node, the writes remain revoked if the database exceeds its size quota
+func freezeWrites(db *types.DatabaseConfig, handler *databaseHandler, frozen bool) error {
+	if handler.RestrictWrites == nil || db.QuotaExceeded {
+		return nil
+	}
+	users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: db.GetName()})
+	if err != nil {
+		return err
+	}
+	return handler.RestrictWrites(db, users, frozen)
+}
+
+// thawDatabase grants the writes back to a database whose migration snapshot is removed if the
+// database could not be moved away from the current node
+func thawDatabase(databaseName string) error {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return err
+	}
+	if db.HostIP != utils.HostIP {
+		return nil
+	}
+	handler := fetchHandler(db)
+	if handler == nil {
+		return fmt.Errorf("Database type `%s` is not supported", db.GetLanguage())
+	}
+	return freezeWrites(db, handler, false)
+}
+
+// evictDatabase removes a database which was moved away from the current node along with its users
+func evictDatabase(databaseName string) error {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return err
+	}
+	if db.HostIP == utils.HostIP {
+		return fmt.Errorf("Database %s is deployed in the current node and cannot be evicted", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil {
+		return fmt.Errorf("Database type `%s` is not supported", db.GetLanguage())
+	}
+
+	users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: databaseName})
+	if err != nil {
+		return err
+	}
+	// The users of a dedicated database are removed along with the container of its server
+	for _, user := range users {
+		if handler.DeleteUser == nil || db.IsDedicated() {
+			break
+		}
+		if err := handler.DeleteUser(db, user); err != nil {
+			utils.LogError("DbMaker-Migrate-2", err)
+		}
+	}
+	if err := handler.Delete(databaseName); err != nil {
+		return err
+	}
+	err = redis.DecrementServiceLoad(
+		db.GetLanguage(),
+		fmt.Sprintf("%s:%d", utils.HostIP, configs.ServiceConfig.DbMaker.Port),
+	)
+	if err != nil {
+		utils.LogError("DbMaker-Migrate-3", err)
+	}
+	return nil
+}
+
+// notifyMigration sends the result of the migration of a database to the notification channels of its owner
+func notifyMigration(db *types.DatabaseConfig, restore *types.Restore, source string, migrationErr error) {
+	var state, message string
+	switch {
+	case migrationErr != nil && source == "":
+		state = types.BackupFailed
+		message = fmt.Sprintf("Database %s could not be restored in another node after the node it was deployed in was lost : %s",
+			db.GetName(), migrationErr)
+	case migrationErr != nil:
+		state = types.BackupFailed
+		message = fmt.Sprintf("Database %s could not be moved to another node : %s", db.GetName(), migrationErr)
+	case source == "":
+		state = types.BackupCompleted
+		message = fmt.Sprintf("Database %s was restored from its backup %s in another node since the node it was deployed in was lost, the changes made after the backup are lost",
+			db.GetName(), restore.BackupID)
+	default:
+		state = types.BackupCompleted
+		message = fmt.Sprintf("Database %s was moved to another node", db.GetName())
+	}
+
+	channels, err := mongo.FetchChannels(types.M{mongo.OwnerKey: db.Owner})
+	if err != nil {
+		utils.LogError("DbMaker-Migrate-4", err)
+		return
+	}
+	notification := alerting.NewDatabaseNotification(db.GetName(), migrationEvent, state, message, time.Now())
+	for _, channel := range channels {
+		if err := alerting.Send(channel, notification); err != nil {
+			utils.LogError("DbMaker-Migrate-5", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package dbmaker
+
+import (
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/metrics"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const defaultMetricsInterval = 300 * time.Second
+
+// queryReading is a reading of the number of queries run on a database
+type queryReading struct {
+	queries int64
+	time    int64
+}
+
+// queryReadings holds the previous readings of the number of queries run on the databases
+// deployed in the current node from which their query rates are computed
+var queryReadings = make(map[string]queryReading)
+
+// queryRate returns the number of queries run per second on a database since its previous reading
+// The rate is 0 for the first reading and when the counter is reset by a restart of the server
+func queryRate(databaseName string, queries, now int64) float64 {
+	previous, ok := queryReadings[databaseName]
+	queryReadings[databaseName] = queryReading{queries: queries, time: now}
+	if !ok || now <= previous.time || queries < previous.queries {
+		return 0
+	}
+	return float64(queries-previous.queries) / float64(now-previous.time)
+}
+
+// registerMetrics stores the metrics of the databases deployed in the current node
+// in the metrics collection along with the metrics of the containers
+func registerMetrics() {
+	dbs := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.DBInstance,
+		mongo.HostIPKey:       utils.HostIP,
+	})
+	var parsedMetricsList []interface{}
+	deployed := make(map[string]bool)
+
+	for _, db := range dbs {
+		name, ok := db[mongo.NameKey].(string)
+		if !ok {
+			continue
+		}
+		language, _ := db[mongo.LanguageKey].(string)
+		if handler := pipeline[language]; handler == nil || handler.Metrics == nil {
+			continue
+		}
+		deployed[name] = true
+		database, err := mongo.FetchSingleDatabase(name)
+		if err != nil {
+			utils.LogError("DbMaker-Monitor-1", err)
+			continue
+		}
+		handler := fetchHandler(database)
+		parsedMetrics, err := handler.Metrics(database)
+		if err != nil {
+			utils.LogError("DbMaker-Monitor-2", err)
+			continue
+		}
+
+		now := time.Now()
+		parsedMetrics.Name = name
+		parsedMetrics.InstanceType = mongo.DBInstance
+		parsedMetrics.Language = language
+		parsedMetrics.QueryRate = queryRate(name, parsedMetrics.Queries, now.Unix())
+		parsedMetrics.ReadTime = now.Unix()
+		parsedMetrics.HostIP = utils.HostIP
+		parsedMetrics.ExpiresAt = now.Add(configs.ServiceConfig.MetricsRetention.RawWindow())
+		parsedMetricsList = append(parsedMetricsList, parsedMetrics)
+	}
+
+	// Forget the readings of the databases which are no longer deployed in the current node
+	for name := range queryReadings {
+		if !deployed[name] {
+			delete(queryReadings, name)
+		}
+	}
+
+	if len(parsedMetricsList) == 0 {
+		return
+	}
+	if _, err := mongo.BulkRegisterMetrics(parsedMetricsList); err != nil {
+		utils.LogError("DbMaker-Monitor-3", err)
+	}
+}
+
+// ScheduleMetricsCollection runs registerMetrics at the given metrics interval
+func ScheduleMetricsCollection() {
+	interval := configs.ServiceConfig.DbMaker.MetricsInterval * time.Second
+	if interval <= 0 {
+		interval = defaultMetricsInterval
+	}
+	scheduler := utils.NewScheduler(interval, registerMetrics)
+	scheduler.RunAsync()
+}
+
+// localContainers returns the containers of the database servers and the
+// databases having dedicated containers deployed in the current node
+func localContainers() []metrics.Container {
+	shipped := shippedContainers()
+	containers := make([]metrics.Container, 0, len(shipped))
+	for _, container := range shipped {
+		containers = append(containers, metrics.Container{
+			Name:         container.Name,
+			InstanceType: container.InstanceType,
+		})
+	}
+	return containers
+}
+
+// ExportContainerMetrics exports the statistics of the database containers
+// deployed in the current node to Prometheus
+func ExportContainerMetrics() {
+	metrics.RegisterContainerSource(localContainers)
+}
@@ -1,7 +1,6 @@
 package dbmaker
 
 import (
-	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/database"
 	"github.com/sdslabs/gasper/lib/docker"
 	"github.com/sdslabs/gasper/lib/mongo"
@@ -18,24 +17,34 @@ func databaseStateCleanup(databaseName string) {
 	redis.RemoveDB(databaseName)
 }
 
-// databaseHandler is a struct for managing operations of a specific type of database (eg:- MySQL, Redis etc)
+// databaseHandler manages the operations of a specific type of database (eg:- MySQL, Redis etc)
+// with the engine registered for it
 type databaseHandler struct {
-	language      string
-	containerPort int
-	create        func(types.Database) error
-	delete        func(string) error
+	*database.Engine
 }
 
-// init sets the language and container port of the database server in the context
-// of the new database to be created
+// init sets the language of the database and the container port of the database server, if the
+// engine has one, in the context of the new database to be created
 func (handler *databaseHandler) init(db *types.DatabaseConfig) {
-	db.SetLanguage(handler.language)
-	db.SetContainerPort(handler.containerPort)
+	db.SetLanguage(handler.Name)
+	if handler.Server != nil {
+		db.SetContainerPort(handler.Server.ContainerPort)
+	}
+}
+
+// fetchHandler returns the handler of a database, which manages it in its own server if it is a dedicated
+// database, or nil if its type is not supported
+func fetchHandler(db *types.DatabaseConfig) *databaseHandler {
+	handler := pipeline[db.GetLanguage()]
+	if handler == nil || !db.IsDedicated() || handler.Dedicated == nil {
+		return handler
+	}
+	return &databaseHandler{handler.Dedicated(db)}
 }
 
 // cleanup cleans the database from MongoDB, Redis and the corresponding database server
 func (handler *databaseHandler) cleanup(databaseName string) {
-	go handler.delete(databaseName)
+	go handler.Delete(databaseName)
 	databaseStateCleanup(databaseName)
 }
 
@@ -44,7 +53,7 @@ func (handler *databaseHandler) logs(tail string) ([]string, error) {
 	if tail == "" {
 		tail = "-1"
 	}
-	data, err := docker.ReadLogs(handler.language, tail)
+	data, err := docker.ReadLogs(handler.Name, tail)
 	if err != nil && err.Error() != "EOF" {
 		return nil, err
 	}
@@ -53,34 +62,16 @@ func (handler *databaseHandler) logs(tail string) ([]string, error) {
 
 // reload restarts the database server
 func (handler *databaseHandler) reload() error {
-	cmd := []string{"service", handler.language, "start"}
-	_, err := docker.ExecDetachedProcess(handler.language, cmd)
+	cmd := []string{"service", handler.Name, "start"}
+	_, err := docker.ExecDetachedProcess(handler.Name, cmd)
 	return err
 }
 
-// pipeline maps the type of database to the corresponding handler
-var pipeline = map[string]*databaseHandler{
-	types.MongoDB: {
-		language:      types.MongoDB,
-		containerPort: configs.ServiceConfig.DbMaker.MongoDB.ContainerPort,
-		create:        database.CreateMongoDB,
-		delete:        database.DeleteMongoDB,
-	},
-	types.MySQL: {
-		language:      types.MySQL,
-		containerPort: configs.ServiceConfig.DbMaker.MySQL.ContainerPort,
-		create:        database.CreateMysqlDB,
-		delete:        database.DeleteMysqlDB,
-	},
-	types.PostgreSQL: {
-		language:      types.PostgreSQL,
-		containerPort: configs.ServiceConfig.DbMaker.PostgreSQL.ContainerPort,
-		create:        database.CreatePostgresqlDB,
-		delete:        database.DeletePostgresqlDB,
-	},
-	types.Redis: {
-		language: types.Redis,
-		create:   database.CreateRedisDB,
-		delete:   database.DeleteRedisDB,
-	},
+// pipeline maps the type of database to the handler of the engine registered for it
+var pipeline = make(map[string]*databaseHandler)
+
+func init() {
+	for _, engine := range database.Engines() {
+		pipeline[engine.Name] = &databaseHandler{engine}
+	}
 }
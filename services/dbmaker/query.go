@@ -0,0 +1,76 @@
+package dbmaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// boundQuery applies the limits configured for the query console to a query
+// A query is read-only if either the query or the configuration asks for it, and its timeout
+// and limit are bounded by the configured ones
+func boundQuery(query *types.DatabaseQuery) {
+	config := configs.ServiceConfig.DbMaker.Query
+	query.ReadOnly = query.ReadOnly || config.ReadOnly
+
+	timeout := int(config.GetTimeout() / time.Second)
+	if query.Timeout <= 0 || query.Timeout > timeout {
+		query.Timeout = timeout
+	}
+	if query.Limit <= 0 || query.Limit > config.GetMaxRows() {
+		query.Limit = config.GetMaxRows()
+	}
+}
+
+// runQuery runs an ad hoc query on a database through its query console on behalf of a user
+// The query is recorded in the audit log whether it succeeds or not, and the error with which
+// it fails is returned in its result
+func runQuery(databaseName, user string, query *types.DatabaseQuery) (*types.QueryResult, error) {
+	if !configs.ServiceConfig.DbMaker.Query.PlugIn {
+		return nil, fmt.Errorf("The query console is disabled in the current node")
+	}
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	if db.HostIP != utils.HostIP {
+		return nil, fmt.Errorf("Database %s is not deployed in the current node", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil || handler.Query == nil {
+		return nil, fmt.Errorf("Database type `%s` does not support the query console", db.GetLanguage())
+	}
+	boundQuery(query)
+
+	start := time.Now()
+	result, err := handler.Query(db, query)
+	duration := float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result = &types.QueryResult{Error: err.Error()}
+	}
+	result.Duration = duration
+
+	audit := &types.QueryAudit{
+		Database:     databaseName,
+		Language:     db.GetLanguage(),
+		User:         user,
+		Query:        query.Query,
+		ReadOnly:     query.ReadOnly,
+		Success:      result.Error == "",
+		Error:        result.Error,
+		Rows:         len(result.Rows) + len(result.Documents),
+		RowsAffected: result.RowsAffected,
+		Duration:     duration,
+		HostIP:       utils.HostIP,
+		Timestamp:    start,
+		ExpiresAt:    start.Add(configs.ServiceConfig.DbMaker.Query.RetentionWindow()),
+	}
+	if _, err := mongo.RegisterQueryAudit(audit); err != nil {
+		utils.LogError("DbMaker-Query-1", err)
+	}
+	return result, nil
+}
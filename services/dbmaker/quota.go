@@ -0,0 +1,188 @@
+package dbmaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/alerting"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	defaultQuotaCheckInterval = 300 * time.Second
+
+	// quotaEvent is the event notified to the owner of a database once it exceeds its size quota
+	// or is within it again
+	quotaEvent = "Database size quota"
+)
+
+// defaultQuota returns the quota of the databases created in the current node
+func defaultQuota() types.DatabaseQuota {
+	return types.DatabaseQuota{
+		Size:        configs.ServiceConfig.DbMaker.Quota.Size,
+		Connections: configs.ServiceConfig.DbMaker.Quota.Connections,
+		Memory:      configs.ServiceConfig.DbMaker.Quota.Memory,
+		CPU:         configs.ServiceConfig.DbMaker.Quota.CPU,
+	}
+}
+
+// fetchQuotaHandler returns a database deployed in the current node along with its handler
+func fetchQuotaHandler(databaseName string) (*types.DatabaseConfig, *databaseHandler, error) {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if db.HostIP != utils.HostIP {
+		return nil, nil, fmt.Errorf("Database %s is not deployed in the current node", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil || handler.Usage == nil {
+		return nil, nil, fmt.Errorf("Database type `%s` does not support quotas", db.GetLanguage())
+	}
+	return db, handler, nil
+}
+
+// fetchUsage returns the resources consumed by a database along with its quota
+func fetchUsage(databaseName string) (*types.DatabaseUsage, error) {
+	db, handler, err := fetchQuotaHandler(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := handler.Usage(db)
+	if err != nil {
+		return nil, err
+	}
+	usage.Quota = db.GetQuota()
+	usage.QuotaExceeded = db.QuotaExceeded
+	return usage, nil
+}
+
+// updateQuota changes the quota of a database and applies it to the database server
+// The size of the database is checked against the new quota right away
+func updateQuota(databaseName string, quota types.DatabaseQuota) error {
+	db, handler, err := fetchQuotaHandler(databaseName)
+	if err != nil {
+		return err
+	}
+	if _, busy := busyDatabases.LoadOrStore(databaseName, true); busy {
+		return fmt.Errorf("A backup or restore of database %s is in progress", databaseName)
+	}
+	defer busyDatabases.Delete(databaseName)
+
+	db.SetQuota(quota)
+	if handler.ApplyQuota != nil {
+		users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: databaseName})
+		if err != nil {
+			return err
+		}
+		if err := handler.ApplyQuota(db, users); err != nil {
+			return err
+		}
+	}
+	err = mongo.UpdateInstance(types.M{
+		mongo.NameKey:         databaseName,
+		mongo.InstanceTypeKey: mongo.DBInstance,
+	}, types.M{"quota": quota})
+	if err != nil {
+		return err
+	}
+	return checkQuota(db, handler)
+}
+
+// checkQuota revokes the writes to a database once it exceeds its size quota and grants them back
+// once it is within the quota again
+func checkQuota(db *types.DatabaseConfig, handler *databaseHandler) error {
+	if handler.Usage == nil || handler.RestrictWrites == nil {
+		return nil
+	}
+	if db.GetQuota().Size == 0 && !db.QuotaExceeded {
+		return nil
+	}
+	usage, err := handler.Usage(db)
+	if err != nil {
+		return err
+	}
+	exceeded := db.GetQuota().Size > 0 && usage.Size > db.GetQuota().GetSizeLimit()
+	if exceeded == db.QuotaExceeded {
+		return nil
+	}
+
+	users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: db.GetName()})
+	if err != nil {
+		return err
+	}
+	if err := handler.RestrictWrites(db, users, exceeded); err != nil {
+		return err
+	}
+	db.QuotaExceeded = exceeded
+	err = mongo.UpdateInstance(types.M{
+		mongo.NameKey:         db.GetName(),
+		mongo.InstanceTypeKey: mongo.DBInstance,
+	}, types.M{"quota_exceeded": exceeded})
+	if err != nil {
+		return err
+	}
+	go notifyQuota(db, usage)
+	return nil
+}
+
+// notifyQuota sends the state of the size quota of a database to the notification channels of its owner
+func notifyQuota(db *types.DatabaseConfig, usage *types.DatabaseUsage) {
+	state := types.AlertResolved
+	message := fmt.Sprintf("Database %s is within its size quota of %d MB again and can be written to",
+		db.GetName(), db.GetQuota().Size)
+	if db.QuotaExceeded {
+		state = types.AlertFiring
+		message = fmt.Sprintf("Database %s of size %.1f MB exceeds its size quota of %d MB and can only be read from until its size is reduced",
+			db.GetName(), float64(usage.Size)/(1024*1024), db.GetQuota().Size)
+	}
+
+	channels, err := mongo.FetchChannels(types.M{mongo.OwnerKey: db.Owner})
+	if err != nil {
+		utils.LogError("DbMaker-Quota-1", err)
+		return
+	}
+	notification := alerting.NewDatabaseNotification(db.GetName(), quotaEvent, state, message, time.Now())
+	for _, channel := range channels {
+		if err := alerting.Send(channel, notification); err != nil {
+			utils.LogError("DbMaker-Quota-2", err)
+		}
+	}
+}
+
+// runQuotaChecks checks the size of the databases deployed in the current node against their quotas
+// The databases being backed up or restored are checked in the next run
+func runQuotaChecks() {
+	dbs := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.DBInstance,
+		mongo.HostIPKey:       utils.HostIP,
+	})
+	for _, doc := range dbs {
+		name, ok := doc[mongo.NameKey].(string)
+		if !ok {
+			continue
+		}
+		if _, busy := busyDatabases.LoadOrStore(name, true); busy {
+			continue
+		}
+		if db, handler, err := fetchQuotaHandler(name); err == nil {
+			if err := checkQuota(db, handler); err != nil {
+				utils.LogError("DbMaker-Quota-3", err)
+			}
+		}
+		busyDatabases.Delete(name)
+	}
+}
+
+// ScheduleQuotaChecks runs runQuotaChecks at the given check interval
+func ScheduleQuotaChecks() {
+	interval := configs.ServiceConfig.DbMaker.Quota.CheckInterval * time.Second
+	if interval <= 0 {
+		interval = defaultQuotaCheckInterval
+	}
+	scheduler := utils.NewScheduler(interval, runQuotaChecks)
+	scheduler.RunAsync()
+}
@@ -0,0 +1,202 @@
+package dbmaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/storage"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// snapshotPollInterval is the time between the checks of whether a snapshot of a database is taken
+	snapshotPollInterval = 5 * time.Second
+
+	// snapshotTimeout is the maximum time waited for a snapshot of a database to be taken
+	snapshotTimeout = 2 * time.Hour
+)
+
+// fetchCompletedBackup returns the metadata of a completed backup of a database
+func fetchCompletedBackup(databaseName, backupID string) (*types.Backup, error) {
+	backups, err := mongo.FetchBackups(types.M{
+		mongo.BackupIDKey: backupID,
+		mongo.DatabaseKey: databaseName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) == 0 {
+		return nil, fmt.Errorf("Backup %s of database %s does not exist", backupID, databaseName)
+	}
+	if !backups[0].IsCompleted() {
+		return nil, fmt.Errorf("Backup %s of database %s is not completed", backupID, databaseName)
+	}
+	return backups[0], nil
+}
+
+// openBackup returns a reader for a backup which is read from its storage target if the target can be
+// accessed from the current node, or streamed from the node storing it otherwise
+func openBackup(backup *types.Backup) (io.ReadCloser, error) {
+	if isReachable(backup) {
+		target, err := storage.Open(backup.Target, backup.Encrypted)
+		if err != nil {
+			return nil, err
+		}
+		return target.Get(backup.Path)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		err := factory.DownloadDatabaseBackup(backup.Database, backup.ID, backup.Node, writer)
+		if err != nil {
+			err = fmt.Errorf("Error while downloading backup %s : %s", backup.ID, err)
+		}
+		writer.CloseWithError(err)
+	}()
+	return reader, nil
+}
+
+// takeSnapshot takes a backup of the live state of a database in the node it is deployed in
+// and waits for it to complete
+func takeSnapshot(databaseName, trigger string) (*types.Backup, error) {
+	instanceURL, err := redis.FetchDbNode(databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("Database %s does not exist", databaseName)
+	}
+	response, err := factory.BackupDatabase(databaseName, trigger, instanceURL)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &types.Backup{}
+	if err := json.Unmarshal(response, snapshot); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(snapshotTimeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(snapshotPollInterval)
+		backups, err := mongo.FetchBackups(types.M{mongo.BackupIDKey: snapshot.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(backups) == 0 {
+			return nil, fmt.Errorf("Snapshot %s of database %s was removed", snapshot.ID, databaseName)
+		}
+		switch backups[0].Status {
+		case types.BackupCompleted:
+			return backups[0], nil
+		case types.BackupFailed:
+			removeSnapshot(backups[0])
+			return nil, fmt.Errorf("Snapshot of database %s failed : %s", databaseName, backups[0].Error)
+		}
+	}
+	return nil, fmt.Errorf("Snapshot of database %s did not complete in %s", databaseName, snapshotTimeout)
+}
+
+// removeSnapshot deletes a snapshot from the node storing it
+func removeSnapshot(snapshot *types.Backup) {
+	if _, err := factory.DeleteDatabaseBackup(snapshot.Database, snapshot.ID, snapshot.Node); err != nil {
+		utils.LogError("DbMaker-Restore-1", err)
+	}
+}
+
+// startRestore starts restoring a backup of the source database into a database deployed in the current node
+// and returns the state of the restore
+// A snapshot of the live state of the source database is restored if no backup is given
+func startRestore(databaseName, source, backupID, operation string) (*types.Restore, error) {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	if db.HostIP != utils.HostIP {
+		return nil, fmt.Errorf("Database %s is not deployed in the current node", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil || handler.Restore == nil {
+		return nil, fmt.Errorf("Database type `%s` does not support restores", db.GetLanguage())
+	}
+	if backupID != "" {
+		backup, err := fetchCompletedBackup(source, backupID)
+		if err != nil {
+			return nil, err
+		}
+		if backup.Language != db.GetLanguage() {
+			return nil, fmt.Errorf("Backup %s of type `%s` cannot be restored into database %s of type `%s`",
+				backupID, backup.Language, databaseName, db.GetLanguage())
+		}
+	}
+	if _, busy := busyDatabases.LoadOrStore(databaseName, true); busy {
+		return nil, fmt.Errorf("A backup or restore of database %s is already in progress", databaseName)
+	}
+
+	restore := &types.Restore{
+		ID:        uuid.New().String(),
+		Database:  databaseName,
+		Source:    source,
+		BackupID:  backupID,
+		Operation: operation,
+		Status:    types.BackupRunning,
+		StartedAt: time.Now(),
+	}
+	if _, err := mongo.RegisterRestore(restore); err != nil {
+		busyDatabases.Delete(databaseName)
+		return nil, err
+	}
+
+	go func() {
+		defer busyDatabases.Delete(databaseName)
+		runRestore(restore, func(update types.M) error {
+			return restoreBackup(db, handler, restore, update)
+		})
+	}()
+	return restore, nil
+}
+
+// runRestore runs a restore and records the result in its state along with the updates made by it
+func runRestore(restore *types.Restore, run func(update types.M) error) error {
+	update := types.M{}
+	err := run(update)
+	if err != nil {
+		utils.LogError("DbMaker-Restore-2", err)
+		update[mongo.StatusKey] = types.BackupFailed
+		update["error"] = err.Error()
+	} else {
+		update[mongo.StatusKey] = types.BackupCompleted
+	}
+	update["completed_at"] = time.Now()
+
+	if err := mongo.UpdateRestore(types.M{mongo.RestoreIDKey: restore.ID}, update); err != nil {
+		utils.LogError("DbMaker-Restore-3", err)
+	}
+	return err
+}
+
+// restoreBackup fetches the backup of a restore, taking a snapshot if required, and restores it
+// The snapshot is removed once it is restored
+func restoreBackup(db *types.DatabaseConfig, handler *databaseHandler, restore *types.Restore, update types.M) error {
+	var backup *types.Backup
+	var err error
+	if restore.BackupID == "" {
+		if backup, err = takeSnapshot(restore.Source, types.SnapshotBackup); err != nil {
+			return err
+		}
+		defer removeSnapshot(backup)
+		update[mongo.BackupIDKey] = backup.ID
+	} else if backup, err = fetchCompletedBackup(restore.Source, restore.BackupID); err != nil {
+		return err
+	}
+
+	reader, err := openBackup(backup)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return handler.Restore(db, backup, reader)
+}
@@ -0,0 +1,151 @@
+package dbmaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// fetchUserHandler returns a database deployed in the current node along with the handler managing its users
+// The engines which cannot have additional users can only change the password of the user of the database
+func fetchUserHandler(databaseName string, additionalUsers bool) (*types.DatabaseConfig, *databaseHandler, error) {
+	db, err := mongo.FetchSingleDatabase(databaseName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if db.HostIP != utils.HostIP {
+		return nil, nil, fmt.Errorf("Database %s is not deployed in the current node", databaseName)
+	}
+	handler := fetchHandler(db)
+	if handler == nil || handler.RotatePassword == nil {
+		return nil, nil, fmt.Errorf("Database type `%s` does not support managing users", db.GetLanguage())
+	}
+	if additionalUsers && (handler.CreateUser == nil || handler.DeleteUser == nil) {
+		return nil, nil, fmt.Errorf("Database type `%s` does not support additional users", db.GetLanguage())
+	}
+	return db, handler, nil
+}
+
+// fetchDatabaseUser returns an additional user of a database
+func fetchDatabaseUser(databaseName, username string) (*types.DatabaseUser, error) {
+	users, err := mongo.FetchDatabaseUsers(types.M{
+		mongo.DatabaseKey: databaseName,
+		mongo.UsernameKey: username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("User %s of database %s does not exist", username, databaseName)
+	}
+	return users[0], nil
+}
+
+// rotatePassword changes the password of a user of a database, or of the user of the database itself
+// if no username is given
+func rotatePassword(databaseName, username, password string) error {
+	if !types.IsValidDatabasePassword(password) {
+		return fmt.Errorf("Password of the user is invalid")
+	}
+	db, handler, err := fetchUserHandler(databaseName, username != "")
+	if err != nil {
+		return err
+	}
+
+	if username == "" {
+		if err := handler.RotatePassword(db, db.GetUser(), password); err != nil {
+			return err
+		}
+		return mongo.UpdateInstance(types.M{
+			mongo.NameKey:         databaseName,
+			mongo.InstanceTypeKey: mongo.DBInstance,
+		}, types.M{mongo.PasswordKey: password})
+	}
+
+	user, err := fetchDatabaseUser(databaseName, username)
+	if err != nil {
+		return err
+	}
+	if err := handler.RotatePassword(db, user.GetUser(), password); err != nil {
+		return err
+	}
+	return mongo.UpdateDatabaseUser(types.M{
+		mongo.DatabaseKey: databaseName,
+		mongo.UsernameKey: username,
+	}, types.M{mongo.PasswordKey: password})
+}
+
+// createUser creates an additional user of a database and registers it
+func createUser(user *types.DatabaseUser) error {
+	if user.Role != types.ReadOnlyRole && user.Role != types.ReadWriteRole {
+		return fmt.Errorf("Role of the user must be %s or %s", types.ReadOnlyRole, types.ReadWriteRole)
+	}
+	if !types.IsValidDatabasePassword(user.Password) {
+		return fmt.Errorf("Password of the user is invalid")
+	}
+	db, handler, err := fetchUserHandler(user.Database, true)
+	if err != nil {
+		return err
+	}
+	if _, err := fetchDatabaseUser(user.Database, user.Username); err == nil {
+		return fmt.Errorf("User %s of database %s already exists", user.Username, user.Database)
+	}
+
+	user.CreatedAt = time.Now()
+	if err := handler.CreateUser(db, user); err != nil {
+		return err
+	}
+	if _, err := mongo.RegisterDatabaseUser(user); err != nil {
+		go handler.DeleteUser(db, user)
+		return err
+	}
+	if db.QuotaExceeded && handler.RestrictWrites != nil && !user.IsReadOnly() {
+		if err := handler.RestrictWrites(db, []*types.DatabaseUser{user}, true); err != nil {
+			utils.LogError("DbMaker-Users-4", err)
+		}
+	}
+	return nil
+}
+
+// deleteUser deletes an additional user of a database
+func deleteUser(databaseName, username string) error {
+	db, handler, err := fetchUserHandler(databaseName, true)
+	if err != nil {
+		return err
+	}
+	user, err := fetchDatabaseUser(databaseName, username)
+	if err != nil {
+		return err
+	}
+	if err := handler.DeleteUser(db, user); err != nil {
+		return err
+	}
+	_, err = mongo.DeleteDatabaseUser(types.M{
+		mongo.DatabaseKey: databaseName,
+		mongo.UsernameKey: username,
+	})
+	return err
+}
+
+// removeDatabaseUsers deletes all the additional users of a database before the database is deleted
+// since the users outlive the databases in some of the database servers
+func removeDatabaseUsers(db *types.DatabaseConfig, handler *databaseHandler) {
+	users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: db.GetName()})
+	if err != nil {
+		utils.LogError("DbMaker-Users-1", err)
+		return
+	}
+	if handler.DeleteUser != nil {
+		for _, user := range users {
+			if err := handler.DeleteUser(db, user); err != nil {
+				utils.LogError("DbMaker-Users-2", err)
+			}
+		}
+	}
+	if _, err := mongo.DeleteDatabaseUsers(types.M{mongo.DatabaseKey: db.GetName()}); err != nil {
+		utils.LogError("DbMaker-Users-3", err)
+	}
+}
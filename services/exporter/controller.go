@@ -0,0 +1,21 @@
+package exporter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/metrics"
+	"github.com/sdslabs/gasper/types"
+)
+
+// ServiceName is the name of the current microservice
+const ServiceName = types.Exporter
+
+// NewService returns a new instance of the current microservice
+// It exposes the metrics of all the services deployed in the current node
+func NewService() http.Handler {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	return router
+}
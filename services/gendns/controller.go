@@ -1,37 +1,72 @@
 package gendns
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 
 	"github.com/miekg/dns"
 	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/metrics"
 	"github.com/sdslabs/gasper/types"
 )
 
-// ServiceName is the name of the current microservice
-const ServiceName = types.GenDNS
+const (
+	// ServiceName is the name of the current microservice
+	ServiceName = types.GenDNS
+
+	// DoHServiceName is the name of the service serving DNS-over-HTTPS
+	DoHServiceName = types.GenDNSDoH
+
+	// DoTServiceName is the name of the service serving DNS-over-TLS
+	DoTServiceName = types.GenDNSDoT
+
+	// recordTTL is the time to live (in seconds) of the served A records
+	recordTTL = 60
+)
 
 // storage stores the DNS A records in the form of Key : Value pairs
 // with Domain Name as the key and the IPv4 Address as the value
 var storage = types.NewRecordStorage()
 
-type handler struct{}
-
-func (h *handler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
-	msg := dns.Msg{}
+// resolve builds the reply for a DNS query using the DNS record storage
+// The boolean returned denotes whether the reply contains an answer
+func resolve(r *dns.Msg) (*dns.Msg, bool) {
+	msg := &dns.Msg{}
 	msg.SetReply(r)
+	if len(r.Question) == 0 {
+		msg.Rcode = dns.RcodeFormatError
+		return msg, false
+	}
+	msg.Authoritative = true
+	domain := msg.Question[0].Name
+	address, ok := storage.Get(domain)
+	if !ok {
+		msg.Rcode = dns.RcodeNameError
+		return msg, false
+	}
 	// Only serve A records
-	if r.Question[0].Qtype == dns.TypeA {
-		msg.Authoritative = true
-		domain := msg.Question[0].Name
-		if address, ok := storage.Get(domain); ok {
-			msg.Answer = append(msg.Answer, &dns.A{
-				Hdr: dns.RR_Header{Name: domain, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
-				A:   net.ParseIP(address),
-			})
-			w.WriteMsg(&msg)
-		}
+	if r.Question[0].Qtype != dns.TypeA {
+		return msg, false
+	}
+	msg.Answer = append(msg.Answer, &dns.A{
+		Hdr: dns.RR_Header{Name: domain, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: recordTTL},
+		A:   net.ParseIP(address),
+	})
+	return msg, true
+}
+
+type handler struct {
+	// alwaysReply makes the handler reply to queries which have no answer
+	// Connection oriented transports like DNS-over-TLS require this
+	alwaysReply bool
+}
+
+func (h *handler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	msg, ok := resolve(r)
+	metrics.ObserveDNSQuery(r, msg)
+	if ok || h.alwaysReply {
+		w.WriteMsg(msg)
 	}
 }
 
@@ -44,3 +79,21 @@ func NewService() *dns.Server {
 	server.Handler = &handler{}
 	return server
 }
+
+// NewDoTService returns a new instance of the current microservice serving DNS-over-TLS
+// It uses the same certificate and private key as GenProxy with SSL
+func NewDoTService() (*dns.Server, error) {
+	certificate, err := tls.LoadX509KeyPair(
+		configs.ServiceConfig.GenProxy.SSL.Certificate,
+		configs.ServiceConfig.GenProxy.SSL.PrivateKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &dns.Server{
+		Addr:      fmt.Sprintf(":%d", configs.ServiceConfig.GenDNS.DoT.Port),
+		Net:       "tcp-tls",
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{certificate}},
+		Handler:   &handler{alwaysReply: true},
+	}, nil
+}
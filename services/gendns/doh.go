@@ -0,0 +1,84 @@
+package gendns
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miekg/dns"
+	"github.com/sdslabs/gasper/lib/metrics"
+)
+
+// dohMediaType is the media type of DNS messages exchanged over HTTPS as defined in RFC 8484
+const dohMediaType = "application/dns-message"
+
+// readDoHQuery extracts the wire format DNS query from a DNS-over-HTTPS request
+func readDoHQuery(c *gin.Context) ([]byte, int, error) {
+	if c.Request.Method == http.MethodGet {
+		// The `dns` parameter is base64url encoded without padding but some clients pad it anyway
+		query := strings.TrimRight(c.Query("dns"), "=")
+		if query == "" {
+			return nil, 400, fmt.Errorf("query parameter `dns` is required")
+		}
+		packet, err := base64.RawURLEncoding.DecodeString(query)
+		if err != nil {
+			return nil, 400, err
+		}
+		return packet, 0, nil
+	}
+	if c.ContentType() != dohMediaType {
+		return nil, 415, fmt.Errorf("content type must be %s", dohMediaType)
+	}
+	packet, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, 400, err
+	}
+	return packet, 0, nil
+}
+
+// dohHandler serves DNS queries received over HTTPS (RFC 8484)
+func dohHandler(c *gin.Context) {
+	packet, code, err := readDoHQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(code, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	query := &dns.Msg{}
+	if err := query.Unpack(packet); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "Malformed DNS message",
+		})
+		return
+	}
+
+	msg, _ := resolve(query)
+	metrics.ObserveDNSQuery(query, msg)
+	response, err := msg.Pack()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{
+			"success": false,
+			"error":   "Failed to pack DNS message",
+		})
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("max-age=%d", recordTTL))
+	c.Data(200, dohMediaType, response)
+}
+
+// NewDoHService returns a new instance of the current microservice serving DNS-over-HTTPS
+func NewDoHService() http.Handler {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/dns-query", dohHandler)
+	router.POST("/dns-query", dohHandler)
+	return router
+}
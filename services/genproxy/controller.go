@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/metrics"
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/types"
 )
@@ -68,7 +70,9 @@ func reverseProxy(c *gin.Context) {
 		})
 		return
 	}
+	start := time.Now()
 	proxy.Serve(c)
+	metrics.ObserveProxyRequest(name, c.Writer.Status(), time.Since(start))
 }
 
 // NewService returns a new instance of the current microservice
@@ -0,0 +1,181 @@
+// +build !windows
+
+package genssh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const (
+	// bridgeKey is the key of the SSH context denoting that the connection is a SSH bridge
+	// connection from another GenSSH instance
+	bridgeKey contextKey = "bridge"
+
+	// bridgeTimeout is the timeout for establishing a SSH bridge connection
+	bridgeTimeout = 10 * time.Second
+)
+
+// bridgeSigner is the key used by GenSSH instances for authenticating with each other
+// It is derived from the cluster secret hence all nodes of the cluster share it
+var bridgeSigner = newBridgeSigner()
+
+func newBridgeSigner() gossh.Signer {
+	seed := sha256.Sum256([]byte("genssh-bridge:" + configs.GasperConfig.Secret))
+	signer, err := gossh.NewSignerFromKey(ed25519.NewKeyFromSeed(seed[:]))
+	if err != nil {
+		utils.LogError("GenSSH-Bridge-1", err)
+	}
+	return signer
+}
+
+// isBridgeKey checks whether a public key belongs to another GenSSH instance
+func isBridgeKey(key ssh.PublicKey) bool {
+	return bridgeSigner != nil && ssh.KeysEqual(key, bridgeSigner.PublicKey())
+}
+
+// isBridged checks whether a connection is a SSH bridge connection from another GenSSH instance
+func isBridged(ctx ssh.Context) bool {
+	bridged, _ := ctx.Value(bridgeKey).(bool)
+	return bridged
+}
+
+// pinnedHostKeyCallback verifies the host key of a node against its host keys pinned in redis
+func pinnedHostKeyCallback(hostname string, remote net.Addr, key gossh.PublicKey) error {
+	hostKeys, err := redis.FetchSSHHostKeys(hostname)
+	if err != nil {
+		return fmt.Errorf("no host keys pinned for %s", hostname)
+	}
+	for _, hostKey := range hostKeys {
+		pinned, _, _, _, err := gossh.ParseAuthorizedKey([]byte(hostKey))
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(pinned.Marshal(), key.Marshal()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host key of %s doesn't match its pinned host keys", hostname)
+}
+
+// dialBridge creates a SSH bridge connection with the node where the application's container is deployed
+func dialBridge(s ssh.Session) (*gossh.Client, bool) {
+	ip, port, ok := bridgeAddress(s)
+	if !ok {
+		return nil, false
+	}
+	client, err := gossh.Dial("tcp", fmt.Sprintf("%s:%s", ip, port), &gossh.ClientConfig{
+		User:            s.User(),
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(bridgeSigner)},
+		HostKeyCallback: pinnedHostKeyCallback,
+		Timeout:         bridgeTimeout,
+	})
+	if err != nil {
+		utils.LogError("GenSSH-Bridge-2", err)
+		fmt.Fprintln(s.Stderr(), "Sorry, we are experiencing some technical difficulties at the moment")
+		s.Exit(1)
+		return nil, false
+	}
+	return client, true
+}
+
+// bridgeSession relays a ssh session to the node where the application's container is deployed
+// The session is relayed as a subsystem if one is specified, otherwise as a shell or a command
+// along with the PTY and its window changes if requested
+// The output of the session is recorded by the recorder if required
+func bridgeSession(s ssh.Session, subsystem string, recorder *sessionRecorder) {
+	// A bridged connection landing on a node without the container means that the
+	// registry is stale, bridging again would loop back to the same node
+	if isBridged(s.Context()) {
+		fmt.Fprintln(s.Stderr(), fmt.Sprintf("Application %s is not deployed at the moment", s.User()))
+		s.Exit(1)
+		return
+	}
+
+	client, ok := dialBridge(s)
+	if !ok {
+		return
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		fmt.Fprintf(s.Stderr(), "ERROR: %s\n", err.Error())
+		s.Exit(1)
+		return
+	}
+	defer session.Close()
+
+	for _, env := range sessionEnviron(s) {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		session.Setenv(kv[0], kv[1])
+	}
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		fmt.Fprintf(s.Stderr(), "ERROR: %s\n", err.Error())
+		s.Exit(1)
+		return
+	}
+	session.Stdout = recorder.output(s)
+	session.Stderr = s.Stderr()
+
+	ptyReq, winCh, isPty := s.Pty()
+	switch {
+	case subsystem != "":
+		err = session.RequestSubsystem(subsystem)
+	case isPty:
+		err = session.RequestPty(ptyReq.Term, ptyReq.Window.Height, ptyReq.Window.Width, gossh.TerminalModes{})
+		if err != nil {
+			break
+		}
+		go func() {
+			for win := range winCh {
+				session.WindowChange(win.Height, win.Width)
+				recorder.resize(win)
+			}
+		}()
+		if len(s.Command()) == 0 {
+			err = session.Shell()
+		} else {
+			err = session.Start(s.RawCommand())
+		}
+	default:
+		err = session.Start(s.RawCommand())
+	}
+	if err != nil {
+		fmt.Fprintf(s.Stderr(), "ERROR: %s\n", err.Error())
+		s.Exit(1)
+		return
+	}
+
+	go func() {
+		io.Copy(stdin, s) // STDIN
+		stdin.Close()
+	}()
+
+	if err := session.Wait(); err != nil {
+		if exitErr, ok := err.(*gossh.ExitError); ok {
+			s.Exit(exitErr.ExitStatus())
+			return
+		}
+		s.Exit(1)
+		return
+	}
+	s.Exit(0)
+}
@@ -13,17 +13,26 @@ import (
 
 	"github.com/gliderlabs/ssh"
 	"github.com/kr/pty"
+	"github.com/pkg/sftp"
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/docker"
 	"github.com/sdslabs/gasper/lib/mongo"
 	"github.com/sdslabs/gasper/lib/redis"
 	"github.com/sdslabs/gasper/lib/utils"
 	"github.com/sdslabs/gasper/types"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 // ServiceName is the name of the current microservice
 const ServiceName = types.GenSSH
 
+// contextKey is the type of the keys used for storing values in the SSH context
+type contextKey string
+
+// principalKey is the key of the SSH context holding the email of the user authenticated
+// with a SSH public key
+const principalKey contextKey = "principal"
+
 // setWinsize uses low-level system call to resize the PTY device "which is just a FD in unix systems".
 // See -- https://github.com/gliderlabs/ssh/blob/master/_examples/ssh-pty/pty.go
 func setWinsize(f *os.File, w, h int) {
@@ -31,45 +40,97 @@ func setWinsize(f *os.File, w, h int) {
 		uintptr(unsafe.Pointer(&struct{ h, w, x, y uint16 }{uint16(h), uint16(w), 0, 0})))
 }
 
-// sessionHandler manages the ssh session.
-func sessionHandler(s ssh.Session) {
-	ptyReq, winCh, isPty := s.Pty()
-	if !isPty {
-		fmt.Fprintln(s, "PTY not requested")
-		s.Exit(1)
-		return
+// isContainerLocal checks whether an application's container is present in the current node
+func isContainerLocal(app string) bool {
+	if _, err := docker.InspectContainerState(app); err != nil {
+		utils.LogError("GenSSH-Controller-1", err)
+		utils.LogInfo("GenSSH-Controller-2", "Application %s's container not present in the current node", app)
+		return false
 	}
+	return true
+}
 
-	var cmd *exec.Cmd
+// bridgeAddress returns the IP address and the SSH port of the node where an application's
+// container is deployed, the session is terminated if the node cannot be found
+func bridgeAddress(s ssh.Session) (string, string, bool) {
+	utils.LogInfo("GenSSH-Controller-3", "Attempting to a create a SSH bridge connection with the desired node")
 
-	if _, err := docker.InspectContainerState(s.User()); err != nil {
-		utils.LogError("GenSSH-Controller-1", err)
-		utils.LogInfo("GenSSH-Controller-2", "Application %s's container not present in the current node", s.User())
-		utils.LogInfo("GenSSH-Controller-3", "Attempting to a create a SSH bridge connection with the desired node")
+	instanceURL, err := redis.FetchAppNode(s.User())
+	if err != nil {
+		fmt.Fprintln(s.Stderr(), fmt.Sprintf("Application %s is not deployed at the moment", s.User()))
+		s.Exit(1)
+		return "", "", false
+	}
+	instanceURL = strings.Split(instanceURL, ":")[0]
+	port, err := redis.GetSSHPort(instanceURL)
+	if err != nil {
+		fmt.Fprintln(s.Stderr(), "Sorry, we are experiencing some technical difficulties at the moment")
+		s.Exit(1)
+		return "", "", false
+	}
+	if port == redis.ErrEmptySet {
+		fmt.Fprintln(s.Stderr(), fmt.Sprintf("Instance %s doesn't have the SSH service deployed", instanceURL))
+		s.Exit(1)
+		return "", "", false
+	}
+	return instanceURL, port, true
+}
 
-		instanceURL, err := redis.FetchAppNode(s.User())
-		if err != nil {
-			fmt.Fprintln(s, fmt.Sprintf("Application %s is not deployed at the moment", s.User()))
-			s.Exit(1)
-			return
-		}
-		instanceURL = strings.Split(instanceURL, ":")[0]
-		port, err := redis.GetSSHPort(instanceURL)
-		if err != nil {
-			fmt.Fprintln(s, "Sorry, we are experiencing some technical difficulties at the moment")
-			s.Exit(1)
+// runCommand runs a command without a PTY with its standard streams attached to the ssh session
+// and exits the session with the command's exit status
+func runCommand(s ssh.Session, cmd *exec.Cmd) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		fmt.Fprintf(s.Stderr(), "ERROR: %s\n", err.Error())
+		s.Exit(1)
+		return
+	}
+	cmd.Stdout = s
+	cmd.Stderr = s.Stderr()
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(s.Stderr(), "ERROR: %s\n", err.Error())
+		s.Exit(1)
+		return
+	}
+	go func() {
+		io.Copy(stdin, s) // STDIN
+		stdin.Close()
+	}()
+	if err := cmd.Wait(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			s.Exit(exitErr.ExitCode())
 			return
 		}
-		if port == redis.ErrEmptySet {
-			fmt.Fprintln(s, fmt.Sprintf("Instance %s doesn't have the SSH service deployed", instanceURL))
+		fmt.Fprintf(s.Stderr(), "ERROR: %s\n", err.Error())
+		s.Exit(1)
+		return
+	}
+	s.Exit(0)
+}
+
+// sessionHandler manages the ssh session.
+func sessionHandler(s ssh.Session) {
+	ptyReq, winCh, isPty := s.Pty()
+	if !isPty {
+		if len(s.Command()) == 0 {
+			fmt.Fprintln(s, "PTY not requested")
 			s.Exit(1)
 			return
 		}
-		cmd = exec.Command("ssh", "-o", "StrictHostKeyChecking=no", "-p", port, fmt.Sprintf("%s@%s", s.User(), instanceURL))
-	} else {
-		cmd = exec.Command("docker", "exec", "-it", s.User(), "/bin/sh")
+		execHandler(s)
+		return
 	}
-	cmd.Env = append(cmd.Env, s.Environ()...)
+
+	recorder := startRecording(s, shellSession)
+	defer recorder.stop()
+
+	if !isContainerLocal(s.User()) {
+		bridgeSession(s, "", recorder)
+		return
+	}
+
+	cmd := exec.Command("docker", "exec", "-it", s.User(), "/bin/sh")
+	cmd.Env = append(cmd.Env, sessionEnviron(s)...)
 	termEnv := fmt.Sprintf("TERM=%s", ptyReq.Term)
 	cmd.Env = append(cmd.Env, termEnv)
 
@@ -84,17 +145,95 @@ func sessionHandler(s ssh.Session) {
 	go func() {
 		for win := range winCh {
 			setWinsize(ptmx, win.Width, win.Height)
+			recorder.resize(win)
 		}
 	}()
 
 	go func() {
 		io.Copy(ptmx, s) // STDIN
 	}()
-	io.Copy(s, ptmx) // STDOUT
+	io.Copy(recorder.output(s), ptmx) // STDOUT
+}
+
+// execHandler manages the non-interactive ssh sessions used by tools like scp and rsync
+// scp is served natively by copying files to and from the container's filesystem while
+// other commands are executed inside the container
+func execHandler(s ssh.Session) {
+	recorder := startRecording(s, execSession)
+	defer recorder.stop()
+
+	if !isContainerLocal(s.User()) {
+		bridgeSession(s, "", recorder)
+		return
+	}
+	if s.Command()[0] == "scp" {
+		scpHandler(s)
+		return
+	}
+	runCommand(s, exec.Command("docker", "exec", "-i", s.User(), "/bin/sh", "-c", s.RawCommand()))
+}
+
+// sftpHandler serves the SFTP subsystem from the application's store directory
+func sftpHandler(s ssh.Session) {
+	recorder := startRecording(s, subsystemSession)
+	defer recorder.stop()
+
+	if !isContainerLocal(s.User()) {
+		bridgeSession(s, "sftp", recorder)
+		return
+	}
+	handler, err := newStoreHandler(s.User())
+	if err != nil {
+		utils.LogError("GenSSH-Controller-19", err)
+		fmt.Fprintf(s.Stderr(), "Storage of application %s is not available at the moment\n", s.User())
+		s.Exit(1)
+		return
+	}
+	server := sftp.NewRequestServer(s, sftp.Handlers{
+		FileGet:  handler,
+		FilePut:  handler,
+		FileCmd:  handler,
+		FileList: handler,
+	})
+	if err := server.Serve(); err != nil && err != io.EOF {
+		utils.LogError("GenSSH-Controller-20", err)
+	}
+	server.Close()
 }
 
 // publicKeyHandler handles the public key authentication
 func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
+	eventLog := "SSH public key login attempt `%s` with key %s on application container %s deployed at %s from IP %s"
+	if isBridgeKey(key) {
+		ctx.SetValue(bridgeKey, true)
+		ctx.SetValue(authMethodKey, "bridge")
+		utils.LogInfo("GenSSH-Controller-21", "SSH bridge connection for application container %s at %s from IP %s", ctx.User(), ctx.LocalAddr(), ctx.RemoteAddr())
+		return true
+	}
+	fingerprint := gossh.FingerprintSHA256(key)
+	sshKey, err := mongo.FetchSingleSSHKey(fingerprint)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			utils.LogInfo("GenSSH-Controller-12", "SSH login attempt failed due to unavailability of mongoDB service on host %s from IP %s", ctx.LocalAddr(), ctx.RemoteAddr())
+			utils.LogError("GenSSH-Controller-13", err)
+			return false
+		}
+		utils.LogInfo("GenSSH-Controller-14", eventLog, "failed", fingerprint, ctx.User(), ctx.LocalAddr(), ctx.RemoteAddr())
+		return false
+	}
+	authorized, err := isKeyAuthorized(sshKey, ctx.User())
+	if err != nil {
+		utils.LogInfo("GenSSH-Controller-15", "SSH login attempt failed due to unavailability of mongoDB service on host %s from IP %s", ctx.LocalAddr(), ctx.RemoteAddr())
+		utils.LogError("GenSSH-Controller-16", err)
+		return false
+	}
+	if authorized {
+		ctx.SetValue(principalKey, sshKey.GetOwner())
+		ctx.SetValue(authMethodKey, "publickey")
+		utils.LogInfo("GenSSH-Controller-17", eventLog, "successful", fingerprint, ctx.User(), ctx.LocalAddr(), ctx.RemoteAddr())
+		return true
+	}
+	utils.LogInfo("GenSSH-Controller-18", eventLog, "failed", fingerprint, ctx.User(), ctx.LocalAddr(), ctx.RemoteAddr())
 	return false
 }
 
@@ -112,6 +251,7 @@ func passwordHandler(ctx ssh.Context, password string) bool {
 		return false
 	}
 	if count == 1 {
+		ctx.SetValue(authMethodKey, "password")
 		utils.LogInfo("GenSSH-Controller-6", eventLog, "successful", ctx.User(), ctx.LocalAddr(), ctx.RemoteAddr())
 		return true
 	}
@@ -139,5 +279,12 @@ func NewService() *ssh.Server {
 		Handler:          sessionHandler,
 		PasswordHandler:  passwordHandler,
 		PublicKeyHandler: publicKeyHandler,
+		SubsystemHandlers: map[string]ssh.SubsystemHandler{
+			"sftp": sftpHandler,
+		},
+		ChannelHandlers: map[string]ssh.ChannelHandler{
+			"session":      ssh.DefaultSessionHandler,
+			"direct-tcpip": directTCPIPHandler,
+		},
 	}
 }
@@ -0,0 +1,145 @@
+// +build !windows
+
+package genssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// forwardRequest is the payload of a direct-tcpip channel request as specified in RFC 4254, Section 7.2
+type forwardRequest struct {
+	DestAddr   string
+	DestPort   uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+// parseForwardDestination returns the name and the instance type of the application or database
+// referred by the destination of a direct-tcpip request
+// Destinations are of the form `<app>.app.<domain>` or `<db>.db.<domain>` where the domain is optional
+func parseForwardDestination(destination string) (string, string, bool) {
+	destination = strings.TrimSuffix(destination, ".")
+	destination = strings.TrimSuffix(destination, "."+configs.GasperConfig.Domain)
+	if name := strings.TrimSuffix(destination, ".app"); name != destination && name != "" {
+		return name, mongo.AppInstance, true
+	}
+	if name := strings.TrimSuffix(destination, ".db"); name != destination && name != "" {
+		return name, mongo.DBInstance, true
+	}
+	return "", "", false
+}
+
+// isForwardAllowed checks whether a connection is entitled to forward ports to an instance
+// Connections can always forward to the application they authenticated for, other instances
+// must be owned by the user authenticated with a SSH public key
+// Connections authenticated with the application's password are limited to the application
+func isForwardAllowed(ctx ssh.Context, name, instanceType string) (bool, error) {
+	// Bridge connections only relay sessions, forwarding is done by the node they originated from
+	if isBridged(ctx) {
+		return false, nil
+	}
+	if instanceType == mongo.AppInstance && name == ctx.User() {
+		return true, nil
+	}
+	owner, ok := ctx.Value(principalKey).(string)
+	if !ok {
+		return false, nil
+	}
+	user, err := mongo.FetchSingleUser(owner)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+	if user.IsAdmin() {
+		return true, nil
+	}
+	count, err := mongo.CountInstances(types.M{
+		mongo.NameKey:         name,
+		mongo.InstanceTypeKey: instanceType,
+		mongo.OwnerKey:        owner,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// directTCPIPHandler handles local port forwarding requests (`ssh -L`) to applications and databases
+// The connections are forwarded only to the server address registered in redis for the instance,
+// hence the destination port of the request is ignored
+func directTCPIPHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	req := &forwardRequest{}
+	if err := gossh.Unmarshal(newChan.ExtraData(), req); err != nil {
+		newChan.Reject(gossh.ConnectionFailed, "error parsing forward data: "+err.Error())
+		return
+	}
+
+	name, instanceType, ok := parseForwardDestination(req.DestAddr)
+	if !ok {
+		newChan.Reject(gossh.ConnectionFailed, "destination must be of the form <app>.app.<domain> or <db>.db.<domain>")
+		return
+	}
+
+	allowed, err := isForwardAllowed(ctx, name, instanceType)
+	if err != nil {
+		utils.LogError("GenSSH-Forward-1", err)
+		newChan.Reject(gossh.ConnectionFailed, "Sorry, we are experiencing some technical difficulties at the moment")
+		return
+	}
+	if !allowed {
+		utils.LogInfo("GenSSH-Forward-2", "Port forwarding to %s %s denied for application container %s from IP %s", instanceType, name, ctx.User(), ctx.RemoteAddr())
+		newChan.Reject(gossh.Prohibited, fmt.Sprintf("not entitled to forward ports to %s %s", instanceType, name))
+		return
+	}
+
+	var server string
+	if instanceType == mongo.AppInstance {
+		server, err = redis.FetchAppServer(name)
+	} else {
+		server, err = redis.FetchDbServer(name)
+	}
+	if err != nil || !strings.Contains(server, ":") {
+		newChan.Reject(gossh.ConnectionFailed, fmt.Sprintf("%s %s is not deployed at the moment", instanceType, name))
+		return
+	}
+
+	dconn, err := net.DialTimeout("tcp", server, bridgeTimeout)
+	if err != nil {
+		utils.LogError("GenSSH-Forward-3", err)
+		newChan.Reject(gossh.ConnectionFailed, fmt.Sprintf("%s %s is not reachable at the moment", instanceType, name))
+		return
+	}
+
+	ch, reqs, err := newChan.Accept()
+	if err != nil {
+		dconn.Close()
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+
+	utils.LogInfo("GenSSH-Forward-4", "Forwarding ports to %s %s for application container %s from IP %s", instanceType, name, ctx.User(), ctx.RemoteAddr())
+
+	go func() {
+		defer ch.Close()
+		defer dconn.Close()
+		io.Copy(ch, dconn)
+	}()
+	go func() {
+		defer ch.Close()
+		defer dconn.Close()
+		io.Copy(dconn, ch)
+	}()
+}
@@ -2,9 +2,13 @@ package genssh
 
 import (
 	"io/ioutil"
+	"strings"
+	"sync"
 
 	"github.com/gliderlabs/ssh"
 	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/types"
 	gossh "golang.org/x/crypto/ssh"
 )
 
@@ -50,6 +54,29 @@ func getHostSigners(filepaths []string) ([]ssh.Signer, error) {
 	return signers, nil
 }
 
+var (
+	hostPublicKeys    []string
+	hostPublicKeysErr error
+	hostPublicKeysSet sync.Once
+)
+
+// HostPublicKeys returns the public keys of GenSSH's host signers in the authorized_keys format
+// These are pinned by other GenSSH instances while creating SSH bridge connections
+func HostPublicKeys() ([]string, error) {
+	hostPublicKeysSet.Do(func() {
+		signers, err := getHostSigners(configs.ServiceConfig.GenSSH.HostSigners)
+		if err != nil {
+			hostPublicKeysErr = err
+			return
+		}
+		for _, signer := range signers {
+			key := strings.TrimSpace(string(gossh.MarshalAuthorizedKey(signer.PublicKey())))
+			hostPublicKeys = append(hostPublicKeys, key)
+		}
+	})
+	return hostPublicKeys, hostPublicKeysErr
+}
+
 // getPublicKey returns a PublicKey interface for the key
 // specified from the filepath
 func getPublicKey(filepath string) (ssh.PublicKey, error) {
@@ -65,3 +92,36 @@ func getPublicKey(filepath string) (ssh.PublicKey, error) {
 
 	return publicKey.(ssh.PublicKey), nil
 }
+
+// isKeyAuthorized checks whether a SSH public key is entitled to access an application
+// A key is entitled if its owner owns the application or is an admin, or if the key
+// has been granted access to the application by the application's owner
+func isKeyAuthorized(key *types.SSHKey, app string) (bool, error) {
+	filter := types.M{
+		mongo.NameKey:         app,
+		mongo.InstanceTypeKey: mongo.AppInstance,
+	}
+	count, err := mongo.CountInstances(filter)
+	if err != nil || count == 0 {
+		return false, err
+	}
+	if key.HasAccess(app) {
+		return true, nil
+	}
+	user, err := mongo.FetchSingleUser(key.GetOwner())
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+	if user.IsAdmin() {
+		return true, nil
+	}
+	filter[mongo.OwnerKey] = key.GetOwner()
+	count, err = mongo.CountInstances(filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
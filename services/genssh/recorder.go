@@ -0,0 +1,207 @@
+// +build !windows
+
+package genssh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// authMethodKey is the key of the SSH context holding the method used for authentication
+	authMethodKey contextKey = "auth_method"
+
+	// Types of SSH sessions
+	shellSession     = "shell"
+	execSession      = "exec"
+	subsystemSession = "subsystem"
+
+	// recordingChunkSize is the size of the transcript after which it is flushed to mongoDB
+	recordingChunkSize = 256 * 1024
+)
+
+// sessionRecorder records the audit information of a SSH session along with an
+// asciinema v2 transcript of its PTY output
+type sessionRecorder struct {
+	mutex     sync.Mutex
+	session   *types.SSHSession
+	buffer    bytes.Buffer
+	sequence  int
+	expiresAt time.Time
+}
+
+// startRecording registers a SSH session for auditing and returns its recorder
+// Sessions bridged from other GenSSH instances are recorded by the instance they
+// originated from, hence nil is returned for them
+func startRecording(s ssh.Session, sessionType string) *sessionRecorder {
+	if !configs.ServiceConfig.GenSSH.Recording.PlugIn || isBridged(s.Context()) {
+		return nil
+	}
+	now := time.Now()
+	ptyReq, _, isPty := s.Pty()
+	remoteIP, _, _ := net.SplitHostPort(s.RemoteAddr().String())
+	user := sessionUser(s)
+	authMethod, _ := s.Context().Value(authMethodKey).(string)
+
+	r := &sessionRecorder{
+		session: &types.SSHSession{
+			SessionID:  uuid.New().String(),
+			App:        s.User(),
+			User:       user,
+			AuthMethod: authMethod,
+			RemoteIP:   remoteIP,
+			HostIP:     utils.HostIP,
+			Type:       sessionType,
+			Command:    s.RawCommand(),
+			Recorded:   isPty && sessionType == shellSession,
+			Start:      now,
+		},
+		expiresAt: now.AddDate(0, 0, configs.ServiceConfig.GenSSH.Recording.RetentionDays()),
+	}
+	if sessionType == subsystemSession {
+		r.session.Command = s.Subsystem()
+	}
+	r.session.ExpiresAt = r.expiresAt
+	if _, err := mongo.RegisterSession(r.session); err != nil {
+		utils.LogError("GenSSH-Recorder-1", err)
+	}
+
+	if r.session.Recorded {
+		header, _ := json.Marshal(types.M{
+			"version":   2,
+			"width":     ptyReq.Window.Width,
+			"height":    ptyReq.Window.Height,
+			"timestamp": now.Unix(),
+			"title":     s.User(),
+			"env": types.M{
+				"TERM":  ptyReq.Term,
+				"SHELL": "/bin/sh",
+			},
+		})
+		r.buffer.Write(header)
+		r.buffer.WriteByte('\n')
+	}
+	return r
+}
+
+// sessionUser returns the email of the user behind a SSH session
+// Sessions authenticated with a SSH public key belong to the key's owner while web terminal
+// sessions belong to the user the ticket passed in their environment was issued for
+func sessionUser(s ssh.Session) string {
+	if user, ok := s.Context().Value(principalKey).(string); ok {
+		return user
+	}
+	for _, env := range s.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 || kv[0] != types.WebTerminalTicketEnv {
+			continue
+		}
+		user, err := redis.RedeemWebTerminalTicket(kv[1], s.User())
+		if err != nil {
+			utils.LogError("GenSSH-Recorder-4", err)
+			return ""
+		}
+		return user
+	}
+	return ""
+}
+
+// sessionEnviron returns the environment requested by a SSH session without the web terminal ticket
+func sessionEnviron(s ssh.Session) []string {
+	environ := []string{}
+	for _, env := range s.Environ() {
+		if strings.HasPrefix(env, types.WebTerminalTicketEnv+"=") {
+			continue
+		}
+		environ = append(environ, env)
+	}
+	return environ
+}
+
+// event appends an event to the asciinema transcript
+func (r *sessionRecorder) event(code, data string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	line, err := json.Marshal([]interface{}{time.Since(r.session.Start).Seconds(), code, data})
+	if err != nil {
+		return
+	}
+	r.buffer.Write(line)
+	r.buffer.WriteByte('\n')
+	if r.buffer.Len() >= recordingChunkSize {
+		r.flush()
+	}
+}
+
+// flush stores the buffered transcript in mongoDB
+// The caller must hold the mutex
+func (r *sessionRecorder) flush() {
+	if r.buffer.Len() == 0 {
+		return
+	}
+	chunk := &types.SessionRecordingChunk{
+		SessionID: r.session.SessionID,
+		Sequence:  r.sequence,
+		Data:      r.buffer.String(),
+		ExpiresAt: r.expiresAt,
+	}
+	r.sequence++
+	r.buffer.Reset()
+	go func() {
+		if _, err := mongo.RegisterSessionRecording(chunk); err != nil {
+			utils.LogError("GenSSH-Recorder-2", err)
+		}
+	}()
+}
+
+// Write records the PTY output of the session
+func (r *sessionRecorder) Write(p []byte) (int, error) {
+	r.event("o", string(p))
+	return len(p), nil
+}
+
+// output returns the writer for the output of the session which records it if required
+func (r *sessionRecorder) output(s ssh.Session) io.Writer {
+	if r == nil || !r.session.Recorded {
+		return s
+	}
+	return io.MultiWriter(s, r)
+}
+
+// resize records the change in the size of the session's PTY
+func (r *sessionRecorder) resize(win ssh.Window) {
+	if r == nil || !r.session.Recorded {
+		return
+	}
+	r.event("r", fmt.Sprintf("%dx%d", win.Width, win.Height))
+}
+
+// stop flushes the remaining transcript and marks the end of the session
+func (r *sessionRecorder) stop() {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	r.flush()
+	r.mutex.Unlock()
+	if err := mongo.UpdateSession(
+		types.M{mongo.SessionIDKey: r.session.SessionID},
+		types.M{"end": time.Now()},
+	); err != nil {
+		utils.LogError("GenSSH-Recorder-3", err)
+	}
+}
@@ -0,0 +1,347 @@
+// +build !windows
+
+package genssh
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/docker"
+)
+
+// Response codes of the scp protocol
+const (
+	scpOK    = 0
+	scpWarn  = 1
+	scpError = 2
+)
+
+// scpOptions stores the options of a scp command invoked in the remote mode
+type scpOptions struct {
+	// sink denotes that files are being copied into the container (-t)
+	sink bool
+	// source denotes that files are being copied from the container (-f)
+	source bool
+	// recursive denotes that directories are copied recursively (-r)
+	recursive bool
+	// targetDir denotes that the target must be a directory (-d)
+	targetDir bool
+	// preserve denotes that modification times are preserved (-p)
+	preserve bool
+	// target is the path of the file or directory to copy to or from
+	target string
+}
+
+// parseSCPCommand parses the arguments of a scp command invoked by a scp client
+func parseSCPCommand(args []string) (*scpOptions, error) {
+	opts := &scpOptions{}
+	paths := []string{}
+	for _, arg := range args[1:] {
+		if arg == "--" || len(paths) > 0 || !strings.HasPrefix(arg, "-") {
+			if arg != "--" {
+				paths = append(paths, arg)
+			}
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 't':
+				opts.sink = true
+			case 'f':
+				opts.source = true
+			case 'r':
+				opts.recursive = true
+			case 'd':
+				opts.targetDir = true
+			case 'p':
+				opts.preserve = true
+			case 'v', 'q':
+			default:
+				return nil, fmt.Errorf("unsupported option -%c", flag)
+			}
+		}
+	}
+	if opts.sink == opts.source {
+		return nil, errors.New("exactly one of -t and -f must be specified")
+	}
+	if len(paths) != 1 {
+		return nil, errors.New("exactly one path must be specified")
+	}
+	opts.target = paths[0]
+	return opts, nil
+}
+
+// containerPath returns the absolute path inside an application's container
+// Relative paths are resolved with respect to the application's working directory
+func containerPath(app, target string) string {
+	if path.IsAbs(target) {
+		return path.Clean(target)
+	}
+	return path.Join(configs.GasperConfig.ProjectRoot, app, target)
+}
+
+// scpHandler serves scp clients copying files into or out of an application's container
+func scpHandler(s ssh.Session) {
+	opts, err := parseSCPCommand(s.Command())
+	if err != nil {
+		fmt.Fprintf(s.Stderr(), "scp: %s\n", err)
+		s.Exit(1)
+		return
+	}
+	if opts.sink {
+		err = scpSink(s, s.User(), opts)
+	} else {
+		err = scpSource(s, s.User(), opts)
+	}
+	if err != nil {
+		fmt.Fprintf(s, "%cscp: %s\n", scpError, err)
+		s.Exit(1)
+		return
+	}
+	s.Exit(0)
+}
+
+// scpReadResponse reads the response of the scp client to the last message
+func scpReadResponse(reader *bufio.Reader) error {
+	code, err := reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	if code == scpOK {
+		return nil
+	}
+	msg, _ := reader.ReadString('\n')
+	return errors.New(strings.TrimSpace(msg))
+}
+
+// parseSCPHeader parses the mode, size and name from a `C` or `D` message
+func parseSCPHeader(line string) (os.FileMode, int64, string, error) {
+	fields := strings.SplitN(strings.TrimSuffix(line[1:], "\n"), " ", 3)
+	if len(fields) != 3 {
+		return 0, 0, "", fmt.Errorf("protocol error: %q", line)
+	}
+	mode, err := strconv.ParseUint(fields[0], 8, 32)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("bad mode: %s", fields[0])
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || size < 0 {
+		return 0, 0, "", fmt.Errorf("bad size: %s", fields[1])
+	}
+	name := fields[2]
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return 0, 0, "", fmt.Errorf("unexpected filename: %s", name)
+	}
+	return os.FileMode(mode).Perm(), size, name, nil
+}
+
+// copyEntry copies a single file or directory into a directory of the container's filesystem
+func copyEntry(app, destination string, header *tar.Header, content io.Reader) error {
+	reader, writer := io.Pipe()
+	go func() {
+		archive := tar.NewWriter(writer)
+		err := archive.WriteHeader(header)
+		if err == nil && content != nil {
+			_, err = io.CopyN(archive, content, header.Size)
+		}
+		if err == nil {
+			err = archive.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	err := docker.CopyToContainer(app, destination, reader)
+	reader.Close()
+	return err
+}
+
+// scpSink receives files from a scp client and copies them into the application's container
+func scpSink(s ssh.Session, app string, opts *scpOptions) error {
+	target := containerPath(app, opts.target)
+	stat, err := docker.StatContainerPath(app, target)
+	targetIsDir := err == nil && stat.Mode.IsDir()
+	if opts.targetDir && !targetIsDir {
+		return fmt.Errorf("%s: Not a directory", opts.target)
+	}
+
+	reader := bufio.NewReader(s)
+	// dirs is the stack of directories the client has entered
+	dirs := []string{}
+	var modTime time.Time
+
+	s.Write([]byte{scpOK})
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch line[0] {
+		case 'T':
+			fields := strings.Fields(line[1:])
+			if len(fields) != 4 {
+				return fmt.Errorf("protocol error: %q", line)
+			}
+			mtime, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("bad modification time: %s", fields[0])
+			}
+			modTime = time.Unix(mtime, 0)
+			s.Write([]byte{scpOK})
+			continue
+		case 'E':
+			if len(dirs) == 0 {
+				return fmt.Errorf("protocol error: %q", line)
+			}
+			dirs = dirs[:len(dirs)-1]
+			s.Write([]byte{scpOK})
+			continue
+		case scpWarn:
+			// The client failed to read one of its files
+			continue
+		case scpError:
+			return errors.New(strings.TrimSpace(line[1:]))
+		case 'C', 'D':
+		default:
+			return fmt.Errorf("protocol error: %q", line)
+		}
+
+		mode, size, name, err := parseSCPHeader(line)
+		if err != nil {
+			return err
+		}
+		if modTime.IsZero() {
+			modTime = time.Now()
+		}
+
+		// Top level entries are renamed to the target if it isn't an existing directory
+		destination := target
+		if len(dirs) > 0 {
+			destination = dirs[len(dirs)-1]
+		} else if !targetIsDir {
+			destination, name = path.Dir(target), path.Base(target)
+		}
+		header := &tar.Header{
+			Name:    name,
+			Mode:    int64(mode),
+			ModTime: modTime,
+		}
+		modTime = time.Time{}
+
+		if line[0] == 'D' {
+			if !opts.recursive {
+				return fmt.Errorf("%s: received directory without -r", name)
+			}
+			header.Typeflag = tar.TypeDir
+			if err := copyEntry(app, destination, header, nil); err != nil {
+				return err
+			}
+			dirs = append(dirs, path.Join(destination, name))
+			s.Write([]byte{scpOK})
+			continue
+		}
+
+		header.Typeflag = tar.TypeReg
+		header.Size = size
+		s.Write([]byte{scpOK})
+		if err := copyEntry(app, destination, header, reader); err != nil {
+			return err
+		}
+		if err := scpReadResponse(reader); err != nil {
+			return err
+		}
+		s.Write([]byte{scpOK})
+	}
+}
+
+// scpSource sends files from the application's container to a scp client
+func scpSource(s ssh.Session, app string, opts *scpOptions) error {
+	reader := bufio.NewReader(s)
+	if err := scpReadResponse(reader); err != nil {
+		return err
+	}
+
+	content, stat, err := docker.CopyFromContainer(app, containerPath(app, opts.target))
+	if err != nil {
+		return fmt.Errorf("%s: No such file or directory", opts.target)
+	}
+	defer content.Close()
+	if stat.Mode.IsDir() && !opts.recursive {
+		return fmt.Errorf("%s: not a regular file", opts.target)
+	}
+
+	send := func(format string, args ...interface{}) error {
+		fmt.Fprintf(s, format, args...)
+		return scpReadResponse(reader)
+	}
+
+	archive := tar.NewReader(content)
+	// dirs is the stack of directories in the archive which the client has entered
+	dirs := []string{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(header.Name, "/")
+
+		// Leave the directories which don't contain the current entry
+		for len(dirs) > 0 && !strings.HasPrefix(name, dirs[len(dirs)-1]+"/") {
+			if err := send("E\n"); err != nil {
+				return err
+			}
+			dirs = dirs[:len(dirs)-1]
+		}
+
+		// Symbolic links and special files are skipped
+		if header.Typeflag != tar.TypeDir && header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if opts.preserve {
+			if err := send("T%d 0 %d 0\n", header.ModTime.Unix(), header.ModTime.Unix()); err != nil {
+				return err
+			}
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		if header.Typeflag == tar.TypeDir {
+			if err := send("D%04o 0 %s\n", mode, path.Base(name)); err != nil {
+				return err
+			}
+			dirs = append(dirs, name)
+			continue
+		}
+		if err := send("C%04o %d %s\n", mode, header.Size, path.Base(name)); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(s, archive, header.Size); err != nil {
+			return err
+		}
+		if _, err := s.Write([]byte{scpOK}); err != nil {
+			return err
+		}
+		if err := scpReadResponse(reader); err != nil {
+			return err
+		}
+	}
+	for range dirs {
+		if err := send("E\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,461 @@
+// +build !windows
+
+package genssh
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/sys/unix"
+)
+
+// storepath is the directory holding the storage directories of the applications
+var storepath, _ = os.Getwd()
+
+// maxSymlinks is the number of symbolic links followed while resolving a request path, like the kernel
+const maxSymlinks = 40
+
+// storeHandler serves SFTP requests from the store directory of an application
+// which is mounted as the working directory of the application's container
+// The store directory is writable from inside the container, hence every element of a request path is
+// opened relative to its parent directory without following symbolic links, which are resolved inside
+// the store directory like they are inside the container
+type storeHandler struct {
+	root string
+}
+
+// newStoreHandler returns a SFTP request handler rooted at an application's store directory
+func newStoreHandler(app string) (*storeHandler, error) {
+	root, err := filepath.EvalSymlinks(filepath.Join(storepath, "storage", app))
+	if err != nil {
+		return nil, err
+	}
+	return &storeHandler{root: root}, nil
+}
+
+// splitPath returns the elements of a path leaving out the empty ones
+func splitPath(p string) []string {
+	elements := []string{}
+	for _, element := range strings.Split(p, "/") {
+		if element != "" && element != "." {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// readlinkAt returns the destination of a symbolic link in a directory
+func readlinkAt(dir int, name string) (string, error) {
+	for size := 128; ; size *= 2 {
+		buf := make([]byte, size)
+		n, err := unix.Readlinkat(dir, name, buf)
+		if err != nil {
+			return "", err
+		}
+		if n < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
+
+// walk opens the directory holding the last element of a request path and returns it along with the
+// name of the element, which is "." if the path resolves to the directory itself
+// The last element is resolved too if follow is true, unless it does not exist
+func (h *storeHandler) walk(requestPath string, follow bool) (int, string, error) {
+	root, err := unix.Open(h.root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, "", err
+	}
+	dirs := []int{root}
+	release := func(keep int) {
+		for _, fd := range dirs {
+			if fd != keep {
+				unix.Close(fd)
+			}
+		}
+	}
+
+	elements := splitPath(path.Clean("/" + requestPath))
+	links := 0
+	for len(elements) > 0 {
+		name := elements[0]
+		elements = elements[1:]
+		dir := dirs[len(dirs)-1]
+		if name == ".." {
+			if len(dirs) > 1 {
+				unix.Close(dir)
+				dirs = dirs[:len(dirs)-1]
+			}
+			continue
+		}
+
+		last := len(elements) == 0
+		var stat unix.Stat_t
+		err := unix.Fstatat(dir, name, &stat, unix.AT_SYMLINK_NOFOLLOW)
+		if last && (!follow || err == unix.ENOENT) {
+			release(dir)
+			return dir, name, nil
+		}
+		if err != nil {
+			release(-1)
+			return -1, "", err
+		}
+
+		if stat.Mode&unix.S_IFMT == unix.S_IFLNK {
+			if links++; links > maxSymlinks {
+				release(-1)
+				return -1, "", unix.ELOOP
+			}
+			destination, err := readlinkAt(dir, name)
+			if err != nil {
+				release(-1)
+				return -1, "", err
+			}
+			if path.IsAbs(destination) {
+				for _, fd := range dirs[1:] {
+					unix.Close(fd)
+				}
+				dirs = dirs[:1]
+			}
+			elements = append(splitPath(destination), elements...)
+			continue
+		}
+		if last {
+			release(dir)
+			return dir, name, nil
+		}
+
+		// The directory might have been replaced by a symbolic link in the meantime
+		fd, err := unix.Openat(dir, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		if err != nil {
+			release(-1)
+			return -1, "", err
+		}
+		dirs = append(dirs, fd)
+	}
+
+	dir := dirs[len(dirs)-1]
+	release(dir)
+	return dir, ".", nil
+}
+
+// open opens the file at a request path resolving all its elements
+func (h *storeHandler) open(requestPath string, flags int, perm uint32) (*os.File, error) {
+	dir, name, err := h.walk(requestPath, true)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: requestPath, Err: err}
+	}
+	defer unix.Close(dir)
+
+	fd, err := unix.Openat(dir, name, flags|unix.O_NOFOLLOW|unix.O_CLOEXEC, perm)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: requestPath, Err: err}
+	}
+	return os.NewFile(uintptr(fd), requestPath), nil
+}
+
+// Fileread opens a file for reading
+func (h *storeHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	file, err := h.open(r.Filepath, unix.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filewrite opens a file for writing
+func (h *storeHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	// Files opened in append mode don't support WriteAt, the clients send
+	// the offsets to write at anyway
+	flags := unix.O_WRONLY
+	pflags := r.Pflags()
+	if pflags.Read {
+		flags = unix.O_RDWR
+	}
+	if pflags.Creat {
+		flags |= unix.O_CREAT
+	}
+	if pflags.Trunc {
+		flags |= unix.O_TRUNC
+	}
+	if pflags.Excl {
+		flags |= unix.O_EXCL
+	}
+	file, err := h.open(r.Filepath, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filecmd handles the file modification requests
+func (h *storeHandler) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat":
+		return h.setstat(r)
+	case "Rename":
+		sourceDir, source, err := h.walk(r.Filepath, false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(sourceDir)
+		targetDir, target, err := h.walk(r.Target, false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(targetDir)
+		return unix.Renameat(sourceDir, source, targetDir, target)
+	case "Rmdir", "Remove":
+		dir, name, err := h.walk(r.Filepath, false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(dir)
+		if name == "." {
+			return os.ErrPermission
+		}
+		if r.Method == "Rmdir" {
+			return unix.Unlinkat(dir, name, unix.AT_REMOVEDIR)
+		}
+		// Directories are removed too like os.Remove does
+		err = unix.Unlinkat(dir, name, 0)
+		if err == nil {
+			return nil
+		}
+		if dirErr := unix.Unlinkat(dir, name, unix.AT_REMOVEDIR); dirErr == nil {
+			return nil
+		} else if dirErr != unix.ENOTDIR {
+			err = dirErr
+		}
+		return err
+	case "Mkdir":
+		dir, name, err := h.walk(r.Filepath, false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(dir)
+		return unix.Mkdirat(dir, name, 0755)
+	case "Link":
+		sourceDir, source, err := h.walk(r.Filepath, true)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(sourceDir)
+		targetDir, target, err := h.walk(r.Target, false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(targetDir)
+		return unix.Linkat(sourceDir, source, targetDir, target, 0)
+	case "Symlink":
+		dir, name, err := h.walk(r.Target, false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(dir)
+		// Links are made relative so that they resolve the same way
+		// inside the application's container
+		destination, err := filepath.Rel(path.Dir(r.Target), r.Filepath)
+		if err != nil {
+			return err
+		}
+		return unix.Symlinkat(destination, dir, name)
+	}
+	return sftp.ErrSSHFxOpUnsupported
+}
+
+// setstat changes the attributes of a file
+// The file is changed through a descriptor since the path could be replaced by a symbolic link
+func (h *storeHandler) setstat(r *sftp.Request) error {
+	flags := r.AttrFlags()
+	attrs := r.Attributes()
+	if flags.Size {
+		file, err := h.open(r.Filepath, unix.O_WRONLY|unix.O_NONBLOCK, 0)
+		if err != nil {
+			return err
+		}
+		err = file.Truncate(int64(attrs.Size))
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Permissions {
+		file, err := h.open(r.Filepath, unix.O_RDONLY|unix.O_NONBLOCK, 0)
+		if err != nil {
+			return err
+		}
+		err = file.Chmod(attrs.FileMode().Perm())
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Acmodtime {
+		dir, name, err := h.walk(r.Filepath, true)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(dir)
+		times := []unix.Timespec{
+			unix.NsecToTimespec(time.Unix(int64(attrs.Atime), 0).UnixNano()),
+			unix.NsecToTimespec(time.Unix(int64(attrs.Mtime), 0).UnixNano()),
+		}
+		if err := unix.UtimesNanoAt(dir, name, times, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Filelist handles the file listing requests
+func (h *storeHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	switch r.Method {
+	case "List":
+		dir, err := h.open(r.Filepath, unix.O_RDONLY|unix.O_DIRECTORY, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer dir.Close()
+		names, err := dir.Readdirnames(-1)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(names)
+		files := make([]os.FileInfo, 0, len(names))
+		for _, name := range names {
+			info, err := lstatAt(int(dir.Fd()), name)
+			if err != nil {
+				// The file might have been removed in the meantime
+				continue
+			}
+			files = append(files, info)
+		}
+		return listerAt(files), nil
+	case "Stat":
+		file, err := h.open(r.Filepath, unix.O_RDONLY|unix.O_NONBLOCK, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return listerAt{info}, nil
+	case "Readlink":
+		dir, name, err := h.walk(r.Filepath, false)
+		if err != nil {
+			return nil, err
+		}
+		defer unix.Close(dir)
+		info, err := lstatAt(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := readlinkAt(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt{&linkInfo{FileInfo: info, destination: destination}}, nil
+	}
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
+
+// lstatAt returns the information of a file in a directory without following it if it is a symbolic link
+func lstatAt(dir int, name string) (os.FileInfo, error) {
+	info := &statInfo{name: name}
+	if err := unix.Fstatat(dir, name, &info.stat, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// statInfo implements os.FileInfo over the status of a file
+type statInfo struct {
+	name string
+	stat unix.Stat_t
+}
+
+func (info *statInfo) Name() string {
+	return info.name
+}
+
+func (info *statInfo) Size() int64 {
+	return info.stat.Size
+}
+
+// Mode converts the mode of the file like os.Lstat does
+func (info *statInfo) Mode() os.FileMode {
+	rawMode := uint32(info.stat.Mode)
+	mode := os.FileMode(rawMode & 0777)
+	switch rawMode & unix.S_IFMT {
+	case unix.S_IFDIR:
+		mode |= os.ModeDir
+	case unix.S_IFLNK:
+		mode |= os.ModeSymlink
+	case unix.S_IFIFO:
+		mode |= os.ModeNamedPipe
+	case unix.S_IFSOCK:
+		mode |= os.ModeSocket
+	case unix.S_IFCHR:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case unix.S_IFBLK:
+		mode |= os.ModeDevice
+	}
+	if rawMode&unix.S_ISUID != 0 {
+		mode |= os.ModeSetuid
+	}
+	if rawMode&unix.S_ISGID != 0 {
+		mode |= os.ModeSetgid
+	}
+	if rawMode&unix.S_ISVTX != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+func (info *statInfo) ModTime() time.Time {
+	return time.Unix(info.stat.Mtim.Unix())
+}
+
+func (info *statInfo) IsDir() bool {
+	return info.Mode().IsDir()
+}
+
+func (info *statInfo) Sys() interface{} {
+	return &info.stat
+}
+
+// listerAt implements the sftp.ListerAt interface over a slice of files
+type listerAt []os.FileInfo
+
+// ListAt copies the files starting from the offset into the slice
+func (l listerAt) ListAt(files []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(files, l[offset:])
+	if n < len(files) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// linkInfo describes a symbolic link whose name is the link's destination as required
+// by the SFTP readlink request
+type linkInfo struct {
+	os.FileInfo
+	destination string
+}
+
+// Name returns the destination of the symbolic link
+func (l *linkInfo) Name() string {
+	return l.destination
+}
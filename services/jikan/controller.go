@@ -14,12 +14,17 @@ import (
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/mongo"
 	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/jikan/middlewares"
 	"github.com/sdslabs/gasper/types"
 )
 
 // ServiceName is the name of the current microservice
 const ServiceName = types.Jikan
 
+// defaultDatabaseMetricsInterval is the interval in seconds at which DbMaker reads the metrics
+// of the databases unless configured otherwise
+const defaultDatabaseMetricsInterval = 300
+
 func streamHandler(c *gin.Context) {
 	appName := c.Param("app")
 
@@ -33,22 +38,89 @@ func streamHandler(c *gin.Context) {
 		metricsCount = 10
 	}
 
+	ctx := c.Request.Context()
 	chanStream := make(chan []types.M, 10)
 	go func() {
+		defer close(chanStream)
 		for {
-			defer close(chanStream)
+			// The timestamps are in seconds, the window spans the number of metrics requested
 			metrics := mongo.FetchContainerMetrics(types.M{
 				mongo.NameKey: appName,
 				mongo.TimestampKey: types.M{
-					"$gte": time.Now().Unix() - int64(configs.ServiceConfig.AppMaker.MetricsInterval*time.Second),
+					"$gte": time.Now().Unix() - metricsCount*int64(configs.ServiceConfig.AppMaker.MetricsInterval),
 				},
+				mongo.InstanceTypeKey: types.M{"$ne": mongo.DBInstance},
 			}, metricsCount)
-			chanStream <- metrics
+			select {
+			case chanStream <- metrics:
+			case <-ctx.Done():
+				return
+			}
 			if metricsInterval < int64(configs.ServiceConfig.AppMaker.MetricsInterval) {
 				metricsInterval = 2 * int64(configs.ServiceConfig.AppMaker.MetricsInterval)
 			}
 
-			time.Sleep(time.Second * time.Duration(metricsInterval))
+			select {
+			case <-time.After(time.Second * time.Duration(metricsInterval)):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	c.Stream(func(w io.Writer) bool {
+		if metrics, ok := <-chanStream; ok {
+			c.SSEvent("metrics", metrics)
+			return true
+		}
+
+		return false
+	})
+}
+
+// databaseMetricsInterval returns the interval in seconds at which the metrics of the databases are read
+func databaseMetricsInterval() int64 {
+	if interval := int64(configs.ServiceConfig.DbMaker.MetricsInterval); interval > 0 {
+		return interval
+	}
+	return defaultDatabaseMetricsInterval
+}
+
+func streamDatabaseHandler(c *gin.Context) {
+	dbName := c.Param("db")
+	readInterval := databaseMetricsInterval()
+
+	metricsInterval, err := strconv.ParseInt(c.Query("interval"), 10, 64)
+	if err != nil || metricsInterval < readInterval {
+		metricsInterval = readInterval
+	}
+
+	metricsCount, err := strconv.ParseInt(c.Query("count"), 10, 64)
+	if err != nil {
+		metricsCount = 10
+	}
+
+	ctx := c.Request.Context()
+	chanStream := make(chan []types.M, 10)
+	go func() {
+		defer close(chanStream)
+		for {
+			metrics := mongo.FetchDatabaseMetrics(types.M{
+				mongo.NameKey: dbName,
+				mongo.TimestampKey: types.M{
+					"$gte": time.Now().Unix() - 2*readInterval,
+				},
+			}, metricsCount)
+			select {
+			case chanStream <- metrics:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-time.After(time.Second * time.Duration(metricsInterval)):
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 	c.Stream(func(w io.Writer) bool {
@@ -79,7 +151,9 @@ func NewService() *http.Server {
 	}
 	router.Use(cors.New(corsConfig))
 
-	router.GET("/stream/:app/metrics", streamHandler)
+	router.GET("/stream/:app/metrics", middlewares.JWT.MiddlewareFunc(), middlewares.IsAppOwner, streamHandler)
+	router.GET("/dbs/:db/metrics", middlewares.JWT.MiddlewareFunc(), middlewares.IsDatabaseOwner, streamDatabaseHandler)
+	router.GET("/ws", middlewares.JWT.MiddlewareFunc(), hubHandler)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", configs.ServiceConfig.Jikan.Port),
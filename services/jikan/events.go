@@ -0,0 +1,71 @@
+package jikan
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// eventBroker fans out the deploy events published in Redis to the listeners of each application
+// A single Redis subscription is shared by all the connections served by the current instance
+type eventBroker struct {
+	once      sync.Once
+	mutex     sync.RWMutex
+	listeners map[string]map[chan *types.DeployEvent]struct{}
+}
+
+var broker = &eventBroker{
+	listeners: make(map[string]map[chan *types.DeployEvent]struct{}),
+}
+
+// run receives the deploy events from Redis and broadcasts them to the listeners
+func (b *eventBroker) run() {
+	pubsub := redis.SubscribeDeployEvents()
+	defer pubsub.Close()
+
+	for msg := range pubsub.Channel() {
+		event := &types.DeployEvent{}
+		if err := json.Unmarshal([]byte(msg.Payload), event); err != nil {
+			utils.LogError("Jikan-Events-1", err)
+			continue
+		}
+		b.mutex.RLock()
+		for listener := range b.listeners[event.App] {
+			// Slow listeners miss events instead of blocking the others
+			select {
+			case listener <- event:
+			default:
+			}
+		}
+		b.mutex.RUnlock()
+	}
+}
+
+// subscribe returns a channel receiving the deploy events of an application
+func (b *eventBroker) subscribe(app string) chan *types.DeployEvent {
+	b.once.Do(func() {
+		go b.run()
+	})
+	listener := make(chan *types.DeployEvent, 10)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.listeners[app] == nil {
+		b.listeners[app] = make(map[chan *types.DeployEvent]struct{})
+	}
+	b.listeners[app][listener] = struct{}{}
+	return listener
+}
+
+// unsubscribe stops sending the deploy events of an application to the channel
+func (b *eventBroker) unsubscribe(app string, listener chan *types.DeployEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.listeners[app], listener)
+	if len(b.listeners[app]) == 0 {
+		delete(b.listeners, app)
+	}
+}
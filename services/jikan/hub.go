@@ -0,0 +1,337 @@
+package jikan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/jikan/middlewares"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	metricsTopic = "metrics"
+	logsTopic    = "logs"
+	eventsTopic  = "events"
+
+	subscribeAction   = "subscribe"
+	unsubscribeAction = "unsubscribe"
+
+	// Time allowed to write a message to the peer
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer
+	pongWait = 60 * time.Second
+
+	// Send pings to the peer with this period, must be less than pongWait
+	pingPeriod = (pongWait * 9) / 10
+
+	// Number of messages buffered for a connection before they start getting dropped
+	sendBufferSize = 256
+
+	// Number of metrics sent in a single message of the metrics topic
+	metricsCount = 10
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Connections are authenticated with the JWT token, hence requests from all origins are allowed
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// subscription is a request from the client to (un)subscribe from a topic of an application
+// or a database, only the metrics topic is streamed for databases
+type subscription struct {
+	Action   string `json:"action"`
+	App      string `json:"app,omitempty"`
+	Database string `json:"database,omitempty"`
+	Topic    string `json:"topic"`
+	Interval int64  `json:"interval,omitempty"`
+
+	// Filters for the logs topic
+	types.LogQuery
+}
+
+// key returns the identifier of the subscription in a connection
+func (s *subscription) key() string {
+	if s.Database != "" {
+		return fmt.Sprintf("dbs/%s/%s", s.Database, s.Topic)
+	}
+	return fmt.Sprintf("%s/%s", s.App, s.Topic)
+}
+
+// message is sent to the client for every update in a subscribed topic
+type message struct {
+	App      string      `json:"app,omitempty"`
+	Database string      `json:"database,omitempty"`
+	Topic    string      `json:"topic,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// client is a WebSocket connection multiplexing the subscriptions of a user
+type client struct {
+	ctx           context.Context
+	conn          *websocket.Conn
+	user          *types.User
+	send          chan *message
+	subscriptions map[string]context.CancelFunc
+}
+
+// emit queues a message for the client, the message is dropped if the client is too slow to receive it
+func (c *client) emit(msg *message) {
+	select {
+	case c.send <- msg:
+	default:
+	}
+}
+
+// fail sends an error message to the client
+func (c *client) fail(sub *subscription, err string) {
+	c.emit(&message{App: sub.App, Database: sub.Database, Topic: sub.Topic, Error: err})
+}
+
+// writePump writes the queued messages and pings to the connection until the client disconnects
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// readPump handles the subscription requests of the client until it disconnects
+func (c *client) readPump() {
+	c.conn.SetReadLimit(4096)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		sub := &subscription{}
+		if err := json.Unmarshal(data, sub); err != nil {
+			c.emit(&message{Error: "invalid subscription request"})
+			continue
+		}
+		switch sub.Action {
+		case subscribeAction:
+			c.subscribe(sub)
+		case unsubscribeAction:
+			c.unsubscribe(sub)
+		default:
+			c.fail(sub, fmt.Sprintf("action `%s` is not supported", sub.Action))
+		}
+	}
+}
+
+// subscribe starts streaming a topic of an application to the client if the user is entitled to it
+func (c *client) subscribe(sub *subscription) {
+	if _, ok := c.subscriptions[sub.key()]; ok {
+		c.fail(sub, "already subscribed")
+		return
+	}
+	streams, instance, instanceType := topicStreams, sub.App, mongo.AppInstance
+	if sub.Database != "" {
+		streams, instance, instanceType = databaseTopicStreams, sub.Database, mongo.DBInstance
+	}
+	stream, ok := streams[sub.Topic]
+	if !ok {
+		c.fail(sub, fmt.Sprintf("topic `%s` is not supported", sub.Topic))
+		return
+	}
+
+	entitled, err := middlewares.IsEntitled(c.user, instance, instanceType)
+	if err != nil {
+		utils.LogError("Jikan-Hub-1", err)
+		c.fail(sub, fmt.Sprintf("failed to verify ownership of the %s", instanceType))
+		return
+	}
+	if !entitled {
+		c.fail(sub, fmt.Sprintf("User %s is not entitled to access %s %s", c.user.GetEmail(), instanceType, instance))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.subscriptions[sub.key()] = cancel
+	go stream(ctx, c, sub)
+}
+
+// unsubscribe stops streaming a topic of an application to the client
+func (c *client) unsubscribe(sub *subscription) {
+	cancel, ok := c.subscriptions[sub.key()]
+	if !ok {
+		c.fail(sub, "not subscribed")
+		return
+	}
+	cancel()
+	delete(c.subscriptions, sub.key())
+}
+
+// topicStreams maps the topics to the functions streaming them to a client
+var topicStreams = map[string]func(context.Context, *client, *subscription){
+	metricsTopic: streamMetrics,
+	logsTopic:    streamLogs,
+	eventsTopic:  streamEvents,
+}
+
+// databaseTopicStreams maps the topics of a database to the functions streaming them to a client
+var databaseTopicStreams = map[string]func(context.Context, *client, *subscription){
+	metricsTopic: streamDatabaseMetrics,
+}
+
+// streamMetrics sends the latest metrics of an application periodically
+func streamMetrics(ctx context.Context, c *client, sub *subscription) {
+	metricsInterval := configs.ServiceConfig.AppMaker.MetricsInterval
+	interval := time.Duration(sub.Interval) * time.Second
+	if interval < metricsInterval*time.Second {
+		interval = 2 * metricsInterval * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// The timestamps are in seconds, the window spans the number of metrics sent
+		metrics := mongo.FetchContainerMetrics(types.M{
+			mongo.NameKey: sub.App,
+			mongo.TimestampKey: types.M{
+				"$gte": time.Now().Unix() - metricsCount*int64(metricsInterval),
+			},
+			mongo.InstanceTypeKey: types.M{"$ne": mongo.DBInstance},
+		}, metricsCount)
+		c.emit(&message{App: sub.App, Topic: sub.Topic, Data: metrics})
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// streamDatabaseMetrics sends the latest metrics of a database periodically
+func streamDatabaseMetrics(ctx context.Context, c *client, sub *subscription) {
+	readInterval := databaseMetricsInterval()
+	interval := time.Duration(sub.Interval) * time.Second
+	if interval < time.Duration(readInterval)*time.Second {
+		interval = time.Duration(readInterval) * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		metrics := mongo.FetchDatabaseMetrics(types.M{
+			mongo.NameKey: sub.Database,
+			mongo.TimestampKey: types.M{
+				"$gte": time.Now().Unix() - 2*readInterval,
+			},
+		}, 10)
+		c.emit(&message{Database: sub.Database, Topic: sub.Topic, Data: metrics})
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// streamLogs follows the logs of an application's container from the node where it is deployed
+func streamLogs(ctx context.Context, c *client, sub *subscription) {
+	node, err := redis.FetchAppNode(sub.App)
+	if err != nil {
+		c.fail(sub, fmt.Sprintf("application %s is not deployed at the moment", sub.App))
+		return
+	}
+
+	query := sub.LogQuery
+	if query.Tail == "" {
+		query.Tail = "20"
+	}
+	err = factory.FollowApplicationLogs(ctx, sub.App, &query, node, func(stream, line string) bool {
+		c.emit(&message{App: sub.App, Topic: sub.Topic, Data: types.M{
+			"stream": stream,
+			"line":   line,
+		}})
+		return true
+	})
+	if err != nil && ctx.Err() == nil {
+		utils.LogError("Jikan-Hub-2", err)
+		c.fail(sub, fmt.Sprintf("failed to follow the logs of application %s", sub.App))
+	}
+}
+
+// streamEvents sends the deploy events of an application as they occur
+func streamEvents(ctx context.Context, c *client, sub *subscription) {
+	listener := broker.subscribe(sub.App)
+	defer broker.unsubscribe(sub.App, listener)
+
+	for {
+		select {
+		case event := <-listener:
+			c.emit(&message{App: sub.App, Topic: sub.Topic, Data: event})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// hubHandler upgrades the request to a WebSocket connection multiplexing
+// the metrics, logs and deploy events of the user's applications and the metrics of their databases
+func hubHandler(c *gin.Context) {
+	user := middlewares.ExtractClaims(c)
+	if user == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		utils.LogError("Jikan-Hub-3", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cl := &client{
+		ctx:           ctx,
+		conn:          conn,
+		user:          user,
+		send:          make(chan *message, sendBufferSize),
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+	go cl.writePump()
+	cl.readPump()
+}
@@ -10,30 +10,37 @@ import (
 	"github.com/sdslabs/gasper/types"
 )
 
-// IsAppOwner checks if a user is entitled to perform operations on an application
-func isAppOwner(c *gin.Context, instanceType string) {
-	instance := c.Param("app")
-	user := ExtractClaims(c)
-	if user == nil {
-		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
-		return
-	}
+// IsEntitled checks if a user is entitled to access an instance
+func IsEntitled(user *types.User, instance, instanceType string) (bool, error) {
 	if user.IsAdmin() {
-		c.Next()
-		return
+		return true, nil
 	}
-
 	count, err := mongo.CountInstances(types.M{
 		mongo.NameKey:         instance,
 		mongo.InstanceTypeKey: instanceType,
 		mongo.OwnerKey:        user.GetEmail(),
 	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func isInstanceOwner(c *gin.Context, param, instanceType string) {
+	instance := c.Param(param)
+	user := ExtractClaims(c)
+	if user == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+
+	entitled, err := IsEntitled(user, instance, instanceType)
 	if err != nil {
 		utils.SendServerErrorResponse(c, err)
 		return
 	}
 
-	if count == 0 {
+	if !entitled {
 		c.AbortWithStatusJSON(401, gin.H{
 			"success": false,
 			"error":   fmt.Sprintf("User %s is not entitled to perform operations on %s %s", user.GetEmail(), instanceType, instance),
@@ -42,3 +49,13 @@ func isAppOwner(c *gin.Context, instanceType string) {
 	}
 	c.Next()
 }
+
+// IsAppOwner checks if a user is entitled to perform operations on an application
+func IsAppOwner(c *gin.Context) {
+	isInstanceOwner(c, "app", mongo.AppInstance)
+}
+
+// IsDatabaseOwner checks if a user is entitled to perform operations on a database
+func IsDatabaseOwner(c *gin.Context) {
+	isInstanceOwner(c, "db", mongo.DBInstance)
+}
@@ -0,0 +1,168 @@
+package master
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/alerting"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const defaultEvaluationInterval = 60 * time.Second
+
+// fetchSamples returns the raw metrics of an application required to evaluate an alert rule
+// sorted from the latest to the oldest
+func fetchSamples(rule *types.AlertRule, now time.Time, interval time.Duration) ([]*types.Metrics, error) {
+	return mongo.FetchMetricsSamples(types.M{
+		mongo.NameKey: rule.App,
+		mongo.TimestampKey: types.M{
+			"$gte": now.Add(-alerting.Window(rule, interval)).Unix(),
+		},
+		mongo.InstanceTypeKey: types.M{"$ne": mongo.DBInstance},
+	})
+}
+
+// notify sends a notification to all the channels of an alert rule which belong to its owner
+func notify(rule *types.AlertRule, notification *alerting.Notification) {
+	channels, err := mongo.FetchChannels(types.M{
+		mongo.ChannelIDKey: types.M{"$in": rule.Channels},
+		mongo.OwnerKey:     rule.Owner,
+	})
+	if err != nil {
+		utils.LogError("Master-Alerting-1", err)
+		return
+	}
+	for _, channel := range channels {
+		if err := alerting.Send(channel, notification); err != nil {
+			utils.LogError("Master-Alerting-2", err)
+		}
+	}
+}
+
+// recordAlert stores a change in the state of an alert rule in the history of its application
+func recordAlert(rule *types.AlertRule, notification *alerting.Notification, silenced bool) {
+	retention := time.Duration(configs.ServiceConfig.Master.Alerting.Retention) * 24 * time.Hour
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	alert := &types.Alert{
+		ID:        uuid.New().String(),
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		App:       rule.App,
+		State:     notification.State,
+		Value:     notification.Value,
+		Message:   notification.Message,
+		Silenced:  silenced,
+		Timestamp: notification.Timestamp,
+		ExpiresAt: notification.Timestamp.Add(retention),
+	}
+	if _, err := mongo.RegisterAlert(alert); err != nil {
+		utils.LogError("Master-Alerting-3", err)
+	}
+}
+
+// transition moves an alert rule from its current state and reports whether the current instance won it
+// The rule is matched on its state and the time it was last notified so that concurrent
+// evaluations by multiple Master instances notify only once
+func transition(rule *types.AlertRule, update types.M) bool {
+	changed, err := mongo.TransitionAlertRule(types.M{
+		mongo.RuleIDKey:       rule.ID,
+		mongo.StateKey:        rule.State,
+		mongo.LastNotifiedKey: rule.LastNotified,
+	}, update)
+	if err != nil {
+		utils.LogError("Master-Alerting-4", err)
+		return false
+	}
+	return changed
+}
+
+// evaluateRule evaluates an alert rule and notifies the changes in its state
+// A firing rule is notified again after the renotify interval and the notifications of
+// silenced rules are suppressed, though their state changes are still recorded
+func evaluateRule(rule *types.AlertRule, now time.Time, interval time.Duration) {
+	samples, err := fetchSamples(rule, now, interval)
+	if err != nil {
+		utils.LogError("Master-Alerting-5", err)
+		return
+	}
+	firing, value, ok := alerting.Evaluate(rule, samples, now, interval)
+	if !ok {
+		return
+	}
+
+	silenced := rule.IsSilenced(now)
+	wasFiring := rule.State == types.AlertFiring
+	renotify := configs.ServiceConfig.Master.Alerting.RenotifyInterval * time.Second
+
+	switch {
+	case firing && !wasFiring:
+		update := types.M{
+			mongo.StateKey: types.AlertFiring,
+			"value":        value,
+			"active_since": now,
+		}
+		if !silenced {
+			update[mongo.LastNotifiedKey] = now
+		}
+		if transition(rule, update) {
+			notification := alerting.NewNotification(rule, types.AlertFiring, value, now)
+			recordAlert(rule, notification, silenced)
+			if !silenced {
+				notify(rule, notification)
+			}
+		}
+
+	case firing && wasFiring:
+		// Notify the alerts which fired while silenced once the silence is over
+		due := rule.LastNotified.IsZero() || (renotify > 0 && now.Sub(rule.LastNotified) >= renotify)
+		if silenced || !due {
+			return
+		}
+		if transition(rule, types.M{"value": value, mongo.LastNotifiedKey: now}) {
+			notify(rule, alerting.NewNotification(rule, types.AlertFiring, value, now))
+		}
+
+	case !firing && wasFiring:
+		if transition(rule, types.M{
+			mongo.StateKey:        types.AlertOK,
+			"value":               value,
+			mongo.LastNotifiedKey: time.Time{},
+		}) {
+			notification := alerting.NewNotification(rule, types.AlertResolved, value, now)
+			recordAlert(rule, notification, silenced)
+			// Only the owners who were told about the alert are told that it is resolved
+			if !silenced && !rule.LastNotified.IsZero() {
+				notify(rule, notification)
+			}
+		}
+	}
+}
+
+// evaluateAlertRules evaluates the alert rules of all applications
+func evaluateAlertRules() {
+	rules, err := mongo.FetchAlertRules(types.M{})
+	if err != nil {
+		utils.LogError("Master-Alerting-6", err)
+		return
+	}
+	now := time.Now()
+	interval := configs.ServiceConfig.AppMaker.MetricsInterval * time.Second
+	for _, rule := range rules {
+		evaluateRule(rule, now, interval)
+	}
+}
+
+// ScheduleAlerting runs evaluateAlertRules at the given evaluation interval
+func ScheduleAlerting() {
+	interval := configs.ServiceConfig.Master.Alerting.EvaluationInterval * time.Second
+	if interval <= 0 {
+		interval = defaultEvaluationInterval
+	}
+	scheduler := utils.NewScheduler(interval, evaluateAlertRules)
+	scheduler.RunAsync()
+}
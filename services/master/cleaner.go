@@ -99,6 +99,63 @@ func rescheduleApplications(apps []types.M) {
 	}
 }
 
+// databaseServices holds the services of DbMaker whose databases are moved to other nodes once their node is lost
+var databaseServices = make(map[string]bool)
+
+func init() {
+	for engine := range configs.ServiceConfig.DbMaker.Engines() {
+		databaseServices[engine] = true
+	}
+}
+
+// rescheduleDatabases restores the databases of a type present on lost nodes from their latest backups
+// in other least loaded nodes
+func rescheduleDatabases(language string, dbs []types.M) {
+	if len(dbs) == 0 {
+		return
+	}
+
+	// fetch the least loaded dbmaker instances of the database type
+	instances, err := redis.GetLeastLoadedInstancesWithScores(language, int64(len(dbs)))
+	if err != nil {
+		utils.LogError("Master-Cleaner-9", err)
+		return
+	}
+
+	if len(instances) == 0 {
+		utils.LogError("Master-Cleaner-10", fmt.Errorf("No %s instances available for re-scheduling", language))
+		return
+	}
+
+	loads := make([]float64, len(instances))
+	for idx, instance := range instances {
+		loads[idx] = instance.Score
+	}
+
+	// deploy each database on the instance with the least load including the databases assigned to it
+	for _, db := range dbs {
+		name, ok := db[mongo.NameKey].(string)
+		if !ok {
+			continue
+		}
+		index := 0
+		for idx := range loads {
+			if loads[idx] < loads[index] {
+				index = idx
+			}
+		}
+		loads[index]++
+		instanceURL := fmt.Sprintf("%v", instances[index].Member)
+		utils.LogInfo("Master-Cleaner-11", "Re-scheduling database %s to %s", name, instanceURL)
+
+		go func() {
+			if _, err := factory.MigrateDatabase(name, "", instanceURL); err != nil {
+				utils.LogError("Master-Cleaner-12", err)
+			}
+		}()
+	}
+}
+
 // inspectInstance checks whether a given instance is alive or not and deletes that instance
 // if it is dead
 func inspectInstance(service, instance string) {
@@ -115,18 +172,31 @@ func inspectInstance(service, instance string) {
 		if err := redis.RemoveServiceInstance(service, instance); err != nil {
 			utils.LogError("Master-Cleaner-6", err)
 		}
+		failover := databaseServices[service] && configs.ServiceConfig.Master.DatabaseFailover
+		if service != types.AppMaker && !failover {
+			return
+		}
+		if !strings.Contains(instance, ":") {
+			utils.LogError("Master-Cleaner-7", fmt.Errorf("Instance %s is in invalid format", instance))
+			return
+		}
+		instanceIP := strings.Split(instance, ":")[0]
+
 		// Re-schedule applications for AppMaker microservice
 		if service == types.AppMaker {
-			if !strings.Contains(instance, ":") {
-				utils.LogError("Master-Cleaner-7", fmt.Errorf("Instance %s is in invalid format", instance))
-				return
-			}
-			instanceIP := strings.Split(instance, ":")[0]
 			apps := mongo.FetchAppInfo(types.M{
 				mongo.HostIPKey: instanceIP,
 			})
 			go rescheduleApplications(apps)
+			return
 		}
+
+		// Restore the databases of DbMaker microservice in other nodes
+		dbs := mongo.FetchDBInfo(types.M{
+			mongo.HostIPKey:   instanceIP,
+			mongo.LanguageKey: service,
+		})
+		go rescheduleDatabases(service, dbs)
 	}
 }
 
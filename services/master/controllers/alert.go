@@ -0,0 +1,368 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/lib/alerting"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/master/middlewares"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// maxRulesPerApp is the maximum number of alert rules an application can have
+	maxRulesPerApp = 20
+
+	// maxChannelsPerUser is the maximum number of notification channels a user can have
+	maxChannelsPerUser = 10
+
+	// defaultAlertsLimit is the number of alerts returned from the history by default
+	defaultAlertsLimit = 100
+
+	// maxAlertsLimit is the maximum number of alerts returned from the history at once
+	maxAlertsLimit = 1000
+)
+
+// silenceRequest is the request body for silencing an alert rule
+type silenceRequest struct {
+	Duration string `form:"duration" json:"duration" binding:"required"`
+}
+
+// CreateChannel creates a notification channel to which the alerts of the user's applications are sent
+func CreateChannel(c *gin.Context) {
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	channel := &types.NotificationChannel{}
+	if err := c.ShouldBind(channel); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err := alerting.ValidateChannel(channel); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	count, err := mongo.CountChannels(types.M{mongo.OwnerKey: claims.GetEmail()})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count >= maxChannelsPerUser {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("cannot create more than %d notification channels", maxChannelsPerUser),
+		})
+		return
+	}
+
+	channel.SetID(uuid.New().String())
+	channel.SetOwner(claims.GetEmail())
+	channel.SetDateTime()
+
+	if _, err := mongo.RegisterChannel(channel); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "notification channel created",
+		"id":      channel.GetID(),
+	})
+}
+
+// FetchChannelsByUser returns all notification channels of the user
+func FetchChannelsByUser(c *gin.Context) {
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	channels, err := mongo.FetchChannels(types.M{mongo.OwnerKey: claims.GetEmail()})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    channels,
+	})
+}
+
+// fetchUserChannel returns a notification channel of the user
+func fetchUserChannel(c *gin.Context) *types.NotificationChannel {
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return nil
+	}
+	channels, err := mongo.FetchChannels(types.M{
+		mongo.ChannelIDKey: c.Param("channel"),
+		mongo.OwnerKey:     claims.GetEmail(),
+	})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return nil
+	}
+	if len(channels) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such notification channel exists",
+		})
+		return nil
+	}
+	return channels[0]
+}
+
+// TestChannel sends a test notification to a notification channel of the user
+func TestChannel(c *gin.Context) {
+	channel := fetchUserChannel(c)
+	if channel == nil {
+		return
+	}
+	rule := &types.AlertRule{
+		ID:       "test",
+		App:      "test",
+		Name:     "Test notification",
+		Metric:   "alive",
+		Operator: "==",
+	}
+	notification := alerting.NewNotification(rule, types.AlertFiring, 0, time.Now())
+	if err := alerting.Send(channel, notification); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to send the notification: %s", err.Error()),
+		})
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "test notification sent",
+	})
+}
+
+// DeleteChannel deletes a notification channel of the user
+func DeleteChannel(c *gin.Context) {
+	channel := fetchUserChannel(c)
+	if channel == nil {
+		return
+	}
+	if _, err := mongo.DeleteChannel(types.M{mongo.ChannelIDKey: channel.GetID()}); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "notification channel deleted",
+	})
+}
+
+// CreateAlertRule creates an alert rule on the metrics of an application
+func CreateAlertRule(c *gin.Context) {
+	appName := c.Param("app")
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	rule := &types.AlertRule{}
+	if err := c.ShouldBind(rule); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err := alerting.ValidateRule(rule); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	rule.Channels = utils.RemoveDuplicates(rule.Channels)
+	count, err := mongo.CountChannels(types.M{
+		mongo.ChannelIDKey: types.M{"$in": rule.Channels},
+		mongo.OwnerKey:     claims.GetEmail(),
+	})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count != int64(len(rule.Channels)) {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "All notification channels of the rule must belong to the user",
+		})
+		return
+	}
+
+	count, err = mongo.CountAlertRules(types.M{mongo.AppKey: appName})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count >= maxRulesPerApp {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("cannot create more than %d alert rules for an application", maxRulesPerApp),
+		})
+		return
+	}
+
+	rule.SetID(uuid.New().String())
+	rule.SetApp(appName)
+	rule.SetOwner(claims.GetEmail())
+	rule.State = types.AlertOK
+	rule.SetDateTime()
+
+	if _, err := mongo.RegisterAlertRule(rule); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "alert rule created",
+		"id":      rule.GetID(),
+	})
+}
+
+// FetchAlertRulesByApp returns all alert rules of an application
+func FetchAlertRulesByApp(c *gin.Context) {
+	rules, err := mongo.FetchAlertRules(types.M{mongo.AppKey: c.Param("app")})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    rules,
+	})
+}
+
+// ruleFilter returns the filter matching the alert rule in the request parameters
+// It aborts the request if no such rule exists
+func ruleFilter(c *gin.Context) types.M {
+	filter := types.M{
+		mongo.RuleIDKey: c.Param("rule"),
+		mongo.AppKey:    c.Param("app"),
+	}
+	count, err := mongo.CountAlertRules(filter)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return nil
+	}
+	if count == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such alert rule exists",
+		})
+		return nil
+	}
+	return filter
+}
+
+// DeleteAlertRule deletes an alert rule of an application
+func DeleteAlertRule(c *gin.Context) {
+	filter := ruleFilter(c)
+	if filter == nil {
+		return
+	}
+	if _, err := mongo.DeleteAlertRule(filter); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "alert rule deleted",
+	})
+}
+
+// SilenceAlertRule suppresses the notifications of an alert rule for the given duration
+func SilenceAlertRule(c *gin.Context) {
+	request := &silenceRequest{}
+	if err := c.ShouldBind(request); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	duration, err := time.ParseDuration(request.Duration)
+	if err != nil || duration <= 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "duration must be a positive duration like 2h",
+		})
+		return
+	}
+	filter := ruleFilter(c)
+	if filter == nil {
+		return
+	}
+	until := time.Now().Add(duration)
+	if _, err := mongo.TransitionAlertRule(filter, types.M{"silenced_until": until}); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("alert rule silenced until %s", until.Format(time.RFC3339)),
+	})
+}
+
+// UnsilenceAlertRule resumes the notifications of a silenced alert rule
+func UnsilenceAlertRule(c *gin.Context) {
+	filter := ruleFilter(c)
+	if filter == nil {
+		return
+	}
+	if _, err := mongo.TransitionAlertRule(filter, types.M{"silenced_until": time.Time{}}); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "alert rule unsilenced",
+	})
+}
+
+// FetchAlertsByApp returns the history of alerts of an application
+func FetchAlertsByApp(c *gin.Context) {
+	limit := int64(defaultAlertsLimit)
+	if val := c.Query("limit"); val != "" {
+		parsed, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || parsed <= 0 || parsed > maxAlertsLimit {
+			c.AbortWithStatusJSON(400, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("limit must be a number between 1 and %d", maxAlertsLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+	alerts, err := mongo.FetchAlerts(types.M{mongo.AppKey: c.Param("app")}, limit)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    alerts,
+	})
+}
@@ -3,9 +3,7 @@ package controllers
 import (
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sdslabs/gasper/lib/factory"
@@ -16,12 +14,6 @@ import (
 	"github.com/sdslabs/gasper/types"
 )
 
-type metricsRecord struct {
-	UptimeRecord []bool    `json:"uptime_record"`
-	CPURecord    []float64 `json:"cpu_record"`
-	MemoryRecord []float64 `json:"memory_record"`
-}
-
 // FetchAppsByUser returns all applications owned by a user
 func FetchAppsByUser(c *gin.Context) {
 	fetchInstancesByUser(c, mongo.AppInstance)
@@ -102,6 +94,7 @@ func UpdateAppByName(c *gin.Context) {
 }
 
 // CreateApp creates an application via gRPC
+// The language of the application is given by the `app` parameter of the route
 func CreateApp(c *gin.Context) {
 	instanceURL, err := redis.GetLeastLoadedWorker()
 	if err != nil {
@@ -128,7 +121,7 @@ func CreateApp(c *gin.Context) {
 		return
 	}
 
-	response, err := factory.CreateApplication(c.Param("language"), claims.GetEmail(), instanceURL, data)
+	response, err := factory.CreateApplication(c.Param("app"), claims.GetEmail(), instanceURL, data)
 	if err != nil {
 		utils.LogError("Master-Controller-Application-1", err)
 		if strings.Contains(err.Error(), "authentication required") {
@@ -166,6 +159,10 @@ func DeleteApp(c *gin.Context) {
 		utils.SendServerErrorResponse(c, err)
 		return
 	}
+	go mongo.RevokeAllSSHKeyAccess(appName)
+	go mongo.DeleteDrains(types.M{mongo.AppKey: appName})
+	go mongo.DeleteAlertRules(types.M{mongo.AppKey: appName})
+	go mongo.DeleteProbes(types.M{mongo.AppKey: appName})
 	c.JSON(200, response)
 }
 
@@ -231,75 +228,3 @@ func RebuildApp(c *gin.Context) {
 func TransferApplicationOwnership(c *gin.Context) {
 	transferOwnership(c, c.Param("app"), mongo.AppInstance, c.Param("user"))
 }
-
-// FetchMetrics retrieves the metrics of an application's container
-func FetchMetrics(c *gin.Context) {
-	appName := c.Param("app")
-	filter := utils.QueryToFilter(c.Request.URL.Query())
-	var timeSpan int64
-	var sparsity int64
-	for unit, converter := range timeConversionMap {
-		if val, ok := filter[unit].(string); ok {
-			timeVal, err := strconv.ParseInt(val, 10, 64)
-			if err != nil {
-				continue
-			}
-			timeSpan += timeVal * converter
-		}
-	}
-
-	metrics := mongo.FetchContainerMetrics(types.M{
-		mongo.NameKey: appName,
-		mongo.TimestampKey: types.M{
-			"$gte": time.Now().Unix() - timeSpan,
-		},
-	}, -1)
-
-	// handle empty metrics
-	if len(metrics) == 0 {
-		c.JSON(200, types.M{
-			"success": true,
-			"data":    []types.M{},
-		})
-		return
-	}
-
-	if val, ok := filter["sparsityvalue"].(string); ok {
-		sparsityVal, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			sparsity = sparsityVal * timeConversionMap[filter["sparsityunit"].(string)]
-		}
-	}
-
-	uptimeRecord := []bool{}
-	CPURecord := []float64{}
-	memoryRecord := []float64{}
-	baseTimestamp := metrics[0]["timestamp"].(int64)
-	var downtimeIntensity int = 0
-	var currTimestamp int64
-
-	for i := range metrics {
-		currTimestamp = metrics[i]["timestamp"].(int64)
-		if !metrics[i]["alive"].(bool) {
-			downtimeIntensity++
-		}
-		if (baseTimestamp - currTimestamp) >= sparsity {
-			baseTimestamp = currTimestamp
-			if downtimeIntensity > 0 {
-				uptimeRecord = append(uptimeRecord, false)
-			} else {
-				uptimeRecord = append(uptimeRecord, true)
-			}
-			downtimeIntensity = 0
-			CPURecord = append(CPURecord, metrics[i]["cpu_usage"].(float64))
-		    memoryRecord = append(memoryRecord, metrics[i]["memory_usage"].(float64))
-		}
-	}
-
-	metricsRecord := metricsRecord{uptimeRecord, CPURecord, memoryRecord}
-
-	c.JSON(200, gin.H{
-		"success": true,
-		"data":    metricsRecord,
-	})
-}
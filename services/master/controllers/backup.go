@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/master/middlewares"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// minBackupInterval is the minimum time between the scheduled backups of a database
+	minBackupInterval = time.Hour
+
+	// maxBackupRetention is the maximum number of scheduled backups retained for a database
+	maxBackupRetention = 100
+)
+
+// CreateBackup starts a backup of a database via gRPC
+func CreateBackup(c *gin.Context) {
+	db := c.Param("db")
+	instanceURL, err := redis.FetchDbNode(db)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such database exists",
+		})
+		return
+	}
+
+	response, err := factory.BackupDatabase(db, types.ManualBackup, instanceURL)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    json.RawMessage(response),
+	})
+}
+
+// FetchBackupsByDatabase returns the metadata of all backups of a database
+func FetchBackupsByDatabase(c *gin.Context) {
+	backups, err := mongo.FetchBackups(types.M{mongo.DatabaseKey: c.Param("db")})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    backups,
+	})
+}
+
+// DeleteBackup deletes a backup of a database via gRPC from the node storing it
+func DeleteBackup(c *gin.Context) {
+	db := c.Param("db")
+	backups, err := mongo.FetchBackups(types.M{
+		mongo.BackupIDKey: c.Param("backup"),
+		mongo.DatabaseKey: db,
+	})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if len(backups) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such backup exists",
+		})
+		return
+	}
+
+	response, err := factory.DeleteDatabaseBackup(db, backups[0].GetID(), backups[0].Node)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, response)
+}
+
+// GetBackupSchedule returns the backup schedule of a database
+func GetBackupSchedule(c *gin.Context) {
+	schedules, err := mongo.FetchBackupSchedules(types.M{mongo.DatabaseKey: c.Param("db")})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if len(schedules) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "Database has no backup schedule",
+		})
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    schedules[0],
+	})
+}
+
+// UpdateBackupSchedule sets the schedule according to which the backups of a database are taken
+// The first scheduled backup is taken one interval after the schedule is set
+func UpdateBackupSchedule(c *gin.Context) {
+	schedule := &types.BackupSchedule{}
+	if err := c.ShouldBind(schedule); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	interval, err := schedule.GetInterval()
+	if err != nil || interval < minBackupInterval {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("interval must be a duration like 24h and at least %s", minBackupInterval),
+		})
+		return
+	}
+	if schedule.Retention < 0 || schedule.Retention > maxBackupRetention {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("retention must be between 1 and %d backups, or 0 for the default", maxBackupRetention),
+		})
+		return
+	}
+
+	schedule.Database = c.Param("db")
+	schedule.NextRun = time.Now().Add(interval)
+	schedule.LastRun = time.Time{}
+	if err := mongo.UpsertBackupSchedule(schedule); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    schedule,
+	})
+}
+
+// DeleteBackupSchedule stops the scheduled backups of a database
+// The backups taken already are retained
+func DeleteBackupSchedule(c *gin.Context) {
+	if _, err := mongo.DeleteBackupSchedule(types.M{mongo.DatabaseKey: c.Param("db")}); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "backup schedule deleted",
+	})
+}
+
+// fetchRestorableBackup returns a completed backup of a database given by the `backup` parameter of the route
+// The response is sent if the backup cannot be restored
+func fetchRestorableBackup(c *gin.Context, db, backupID string) (*types.Backup, bool) {
+	backups, err := mongo.FetchBackups(types.M{
+		mongo.BackupIDKey: backupID,
+		mongo.DatabaseKey: db,
+	})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return nil, false
+	}
+	if len(backups) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such backup exists",
+		})
+		return nil, false
+	}
+	if !backups[0].IsCompleted() {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Backup is %s and cannot be restored", backups[0].Status),
+		})
+		return nil, false
+	}
+	return backups[0], true
+}
+
+// RestoreDatabase starts restoring a backup of a database into it via gRPC
+// The current contents of the database are replaced by those of the backup
+func RestoreDatabase(c *gin.Context) {
+	db := c.Param("db")
+	backup, ok := fetchRestorableBackup(c, db, c.Param("backup"))
+	if !ok {
+		return
+	}
+	instanceURL, err := redis.FetchDbNode(db)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such database exists",
+		})
+		return
+	}
+
+	response, err := factory.RestoreDatabase(db, backup.GetID(), instanceURL)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    json.RawMessage(response),
+	})
+}
+
+// CloneDatabase creates a database via gRPC from a backup of another database, or from a snapshot
+// of its live state if no backup is given
+// The new database's name and password are given in the request body like while creating a database
+func CloneDatabase(c *gin.Context) {
+	source := c.Param("db")
+	language, err := mongo.FetchDatabaseLanguage(source)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such database exists",
+		})
+		return
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract data from Request Body"))
+		return
+	}
+	request := &types.CloneRequest{}
+	if err := json.Unmarshal(data, request); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if request.Backup != "" {
+		if _, ok := fetchRestorableBackup(c, source, request.Backup); !ok {
+			return
+		}
+	}
+
+	instanceURL, err := redis.GetLeastLoadedInstance(language)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if instanceURL == redis.ErrEmptySet {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No worker instances available at the moment",
+		})
+		return
+	}
+
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+
+	response, err := factory.CloneDatabase(language, claims.GetEmail(), source, request.Backup, instanceURL, data)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    json.RawMessage(response),
+	})
+}
+
+// FetchRestoresByDatabase returns the states of the restores into a database
+func FetchRestoresByDatabase(c *gin.Context) {
+	restores, err := mongo.FetchRestores(types.M{mongo.DatabaseKey: c.Param("db")})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    restores,
+	})
+}
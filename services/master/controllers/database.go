@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/database"
 	"github.com/sdslabs/gasper/lib/factory"
 	"github.com/sdslabs/gasper/lib/mongo"
@@ -24,19 +28,31 @@ func GetAllDatabases(c *gin.Context) {
 }
 
 // GetDatabaseInfo gets info regarding a particular database
+// The resources consumed by the database are fetched via gRPC and left out if its node cannot be reached
 func GetDatabaseInfo(c *gin.Context) {
 	db := c.Param("db")
 	filter := make(types.M)
 	filter[mongo.NameKey] = db
+	data := mongo.FetchDBInfo(filter)
+	if len(data) > 0 {
+		if instanceURL, err := redis.FetchDbNode(db); err == nil {
+			if usage, err := factory.FetchDatabaseUsage(db, instanceURL); err == nil {
+				data[0]["usage"] = json.RawMessage(usage)
+			} else {
+				utils.LogError("Master-Controller-Database-1", err)
+			}
+		}
+	}
 	c.JSON(200, gin.H{
 		"success": true,
-		"data":    mongo.FetchDBInfo(filter),
+		"data":    data,
 	})
 }
 
 // CreateDatabase creates a database via gRPC
+// The type of the database is given by the `db` parameter of the route
 func CreateDatabase(c *gin.Context) {
-	database := c.Param("database")
+	database := c.Param("db")
 	instanceURL, err := redis.GetLeastLoadedInstance(database)
 	if err != nil {
 		c.AbortWithStatusJSON(400, gin.H{
@@ -98,6 +114,91 @@ func TransferDatabaseOwnership(c *gin.Context) {
 	transferOwnership(c, c.Param("db"), mongo.DBInstance, c.Param("user"))
 }
 
+// MigrateDatabase moves a database via gRPC to the node given by the `node` parameter of the route
+// which must deploy databases of the same type
+// A snapshot of the database is moved while its current node is alive, otherwise its latest backup
+// is restored in the new node
+func MigrateDatabase(c *gin.Context) {
+	db := c.Param("db")
+	language, err := mongo.FetchDatabaseLanguage(db)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such database exists",
+		})
+		return
+	}
+
+	node := c.Param("node")
+	if !strings.Contains(node, ":") {
+		node = fmt.Sprintf("%s:%d", node, configs.ServiceConfig.DbMaker.Port)
+	}
+	instances, err := redis.FetchServiceInstances(language)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if !utils.Contains(instances, node) {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Node %s does not deploy %s databases", node, language),
+		})
+		return
+	}
+
+	source, err := redis.FetchDbNode(db)
+	if err == nil && source == node {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "Database is already deployed in the node",
+		})
+		return
+	}
+	if err != nil || utils.NotAlive(source) {
+		source = ""
+	}
+
+	response, err := factory.MigrateDatabase(db, source, node)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    json.RawMessage(response),
+	})
+}
+
+// UpdateDatabaseQuota changes the quota of a database via gRPC
+func UpdateDatabaseQuota(c *gin.Context) {
+	db := c.Param("db")
+	quota := &types.DatabaseQuota{}
+	if err := c.ShouldBindJSON(quota); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	instanceURL, ok := fetchDatabaseNode(c, db)
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(quota)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if _, err := factory.UpdateDatabaseQuota(db, data, instanceURL); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    quota,
+	})
+}
+
 func GetRedisLogs(c *gin.Context) {
 	db := c.Param("db")
 	logs, err := database.GetLogs(db)
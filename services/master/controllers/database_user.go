@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// generatedPasswordLength is the length of the passwords generated for the users of databases
+const generatedPasswordLength = 24
+
+// databasePassword returns the password given for a user of a database, or a random one if none is given
+// The response is sent if the given password is invalid
+func databasePassword(c *gin.Context, password string) (string, bool) {
+	if password == "" {
+		generated, err := utils.GenerateRandomString(generatedPasswordLength)
+		if err != nil {
+			utils.SendServerErrorResponse(c, err)
+			return "", false
+		}
+		return generated, true
+	}
+	if !types.IsValidDatabasePassword(password) {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "password must have 8 to 128 printable characters without whitespaces, quotes, backticks and backslashes",
+		})
+		return "", false
+	}
+	return password, true
+}
+
+// fetchDatabaseNode returns the node in which a database is deployed
+// The response is sent if the database does not exist
+func fetchDatabaseNode(c *gin.Context, db string) (string, bool) {
+	instanceURL, err := redis.FetchDbNode(db)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such database exists",
+		})
+		return "", false
+	}
+	return instanceURL, true
+}
+
+// RotateDatabasePassword changes the password of a user of a database via gRPC, or of the user
+// of the database itself if the `user` parameter of the route is absent
+// A random password is generated if none is given in the request body
+func RotateDatabasePassword(c *gin.Context) {
+	db := c.Param("db")
+	request := &types.PasswordRotation{}
+	if err := c.ShouldBind(request); err != nil && err != io.EOF {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	password, ok := databasePassword(c, request.Password)
+	if !ok {
+		return
+	}
+	instanceURL, ok := fetchDatabaseNode(c, db)
+	if !ok {
+		return
+	}
+
+	if _, err := factory.RotateDatabasePassword(db, c.Param("user"), password, instanceURL); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data": gin.H{
+			"password": password,
+		},
+	})
+}
+
+// FetchDatabaseUsers returns the additional users of a database without their passwords
+func FetchDatabaseUsers(c *gin.Context) {
+	users, err := mongo.FetchDatabaseUsers(types.M{mongo.DatabaseKey: c.Param("db")})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	for _, user := range users {
+		user.Password = ""
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    users,
+	})
+}
+
+// CreateDatabaseUser creates an additional user of a database via gRPC
+// A random password is generated if none is given in the request body
+func CreateDatabaseUser(c *gin.Context) {
+	db := c.Param("db")
+	user := &types.DatabaseUser{}
+	if err := c.ShouldBind(user); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	password, ok := databasePassword(c, user.Password)
+	if !ok {
+		return
+	}
+	instanceURL, ok := fetchDatabaseNode(c, db)
+	if !ok {
+		return
+	}
+
+	user.Database = db
+	user.Password = password
+	if _, err := factory.CreateDatabaseUser(db, user.Username, user.Password, user.Role, instanceURL); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data": gin.H{
+			"username": user.Username,
+			"login":    user.GetUser(),
+			"password": user.Password,
+			"role":     user.Role,
+		},
+	})
+}
+
+// DeleteDatabaseUser deletes an additional user of a database via gRPC
+func DeleteDatabaseUser(c *gin.Context) {
+	db := c.Param("db")
+	instanceURL, ok := fetchDatabaseNode(c, db)
+	if !ok {
+		return
+	}
+
+	response, err := factory.DeleteDatabaseUser(db, c.Param("user"), instanceURL)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, response)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sdslabs/gasper/lib/logship"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// maxDrainsPerApp is the maximum number of log drains an application can have
+const maxDrainsPerApp = 5
+
+// CreateDrain creates a log drain shipping the logs of an application to an external sink
+func CreateDrain(c *gin.Context) {
+	appName := c.Param("app")
+	drain := &types.LogDrain{}
+	if err := c.ShouldBind(drain); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err := logship.ValidateDrain(drain); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	count, err := mongo.CountDrains(types.M{mongo.AppKey: appName})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count >= maxDrainsPerApp {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("cannot create more than %d drains for an application", maxDrainsPerApp),
+		})
+		return
+	}
+
+	drain.SetID(uuid.New().String())
+	drain.SetApp(appName)
+	drain.SetDateTime()
+
+	if _, err := mongo.RegisterDrain(drain); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "log drain created",
+		"id":      drain.GetID(),
+	})
+}
+
+// FetchDrainsByApp returns all log drains of an application
+func FetchDrainsByApp(c *gin.Context) {
+	drains, err := mongo.FetchDrains(types.M{mongo.AppKey: c.Param("app")})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    drains,
+	})
+}
+
+// DeleteDrain deletes a log drain of an application
+func DeleteDrain(c *gin.Context) {
+	filter := types.M{
+		mongo.DrainIDKey: c.Param("drain"),
+		mongo.AppKey:     c.Param("app"),
+	}
+	count, err := mongo.CountDrains(filter)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such log drain exists",
+		})
+		return
+	}
+	if _, err := mongo.DeleteDrain(filter); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "log drain deleted",
+	})
+}
@@ -44,7 +44,7 @@ func fetchInstances(c *gin.Context, instance string) {
 	filter[mongo.InstanceTypeKey] = instance
 	c.JSON(200, gin.H{
 		"success": true,
-		"data":    mongo.FetchDocs(mongo.InstanceCollection, filter, nil),
+		"data":    mongo.FetchInstances(filter),
 	})
 }
 
@@ -140,6 +140,8 @@ func deleteUser(c *gin.Context, userEmail string) {
 		"deleted": true,
 	}
 	go mongo.UpdateInstances(instanceFilter, update)
+	go mongo.DeleteSSHKeys(types.M{mongo.OwnerKey: userEmail})
+	go mongo.DeleteChannels(types.M{mongo.OwnerKey: userEmail})
 
 	err := mongo.UpdateUser(filter, update)
 	if err != nil {
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sdslabs/gasper/lib/docker"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// maxLogSearchLimit is the maximum number of lines returned by a search in the shipped logs
+const maxLogSearchLimit = 5000
+
+var logUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// parseLogTime parses a point in time given either as a unix timestamp, a RFC3339
+// timestamp or a duration like `10m` relative to the current time
+func parseLogTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp.Unix(), nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration).Unix(), nil
+	}
+	return 0, fmt.Errorf("time `%s` must be a unix timestamp, a RFC3339 timestamp or a duration", value)
+}
+
+// parseLogQuery extracts the log filters from the request's query parameters
+func parseLogQuery(c *gin.Context) (*types.LogQuery, error) {
+	query := &types.LogQuery{
+		Tail:   c.DefaultQuery("tail", "100"),
+		Stream: c.Query("stream"),
+		Grep:   c.Query("grep"),
+	}
+	var err error
+	if query.Since, err = parseLogTime(c.Query("since")); err != nil {
+		return nil, err
+	}
+	if query.Until, err = parseLogTime(c.Query("until")); err != nil {
+		return nil, err
+	}
+	// Validate the filters before sending them to the worker node
+	if _, err = docker.NewLogFilter(query.Tail, query.Since, query.Until, query.Stream, query.Grep); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// streamLogs relays the lines followed by the given function to the client over a
+// WebSocket connection if requested, else as server-sent events
+func streamLogs(c *gin.Context, follow func(context.Context, func(stream, line string) bool) error) {
+	utils.ClearWriteDeadline(c.Request)
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		streamLogsOverWebSocket(c, follow)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(200)
+	c.Writer.Flush()
+
+	err := follow(c.Request.Context(), func(stream, line string) bool {
+		c.SSEvent(stream, line)
+		c.Writer.Flush()
+		return true
+	})
+	if err != nil && c.Request.Context().Err() == nil {
+		utils.LogError("Master-Controller-Logs-1", err)
+		c.SSEvent("error", err.Error())
+		c.Writer.Flush()
+	}
+}
+
+// streamLogsOverWebSocket relays the followed lines to the client as JSON messages over a WebSocket connection
+func streamLogsOverWebSocket(c *gin.Context, follow func(context.Context, func(stream, line string) bool) error) {
+	conn, err := logUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		utils.LogError("Master-Controller-Logs-2", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The connection is only read for detecting its closure
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = follow(ctx, func(stream, line string) bool {
+		return conn.WriteJSON(gin.H{
+			"stream": stream,
+			"line":   line,
+		}) == nil
+	})
+	if err != nil && ctx.Err() == nil {
+		utils.LogError("Master-Controller-Logs-3", err)
+		conn.WriteJSON(gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+// FollowAppLogs streams the docker container logs of an application as they are written via gRPC
+func FollowAppLogs(c *gin.Context) {
+	appName := c.Param("app")
+	instanceURL, err := redis.FetchAppNode(appName)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Application %s is not deployed at the moment", appName),
+		})
+		return
+	}
+
+	query, err := parseLogQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	streamLogs(c, func(ctx context.Context, handler func(stream, line string) bool) error {
+		return factory.FollowApplicationLogs(ctx, appName, query, instanceURL, handler)
+	})
+}
+
+// FollowDatabaseServerLogs streams the docker logs of the server hosting a database as they are written via gRPC
+func FollowDatabaseServerLogs(c *gin.Context) {
+	dbName := c.Param("db")
+	db, err := mongo.FetchSingleDatabase(dbName)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.AbortWithStatusJSON(400, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("Database %s does not exist", dbName),
+			})
+			return
+		}
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	instanceURL, err := redis.FetchDbNode(dbName)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Database %s is not deployed at the moment", dbName),
+		})
+		return
+	}
+
+	query, err := parseLogQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	streamLogs(c, func(ctx context.Context, handler func(stream, line string) bool) error {
+		return factory.FollowDatabaseServerLogs(ctx, db.GetLanguage(), query, instanceURL, handler)
+	})
+}
+
+// SearchAppLogs searches the logs of an application shipped to MongoDB
+// Lines containing the query within the given time range are returned, latest first
+func SearchAppLogs(c *gin.Context) {
+	appName := c.Param("app")
+	from, err := parseLogTime(c.Query("from"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	to, err := parseLogTime(c.Query("to"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "500"), 10, 64)
+	if err != nil || limit <= 0 || limit > maxLogSearchLimit {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("limit must be an integer between 1 and %d", maxLogSearchLimit),
+		})
+		return
+	}
+
+	filter := types.M{
+		mongo.NameKey:         appName,
+		mongo.InstanceTypeKey: mongo.AppInstance,
+	}
+	if query := c.Query("q"); query != "" {
+		filter[mongo.MessageKey] = types.M{
+			"$regex":   regexp.QuoteMeta(query),
+			"$options": "i",
+		}
+	}
+	timeRange := types.M{}
+	if from > 0 {
+		timeRange["$gte"] = time.Unix(from, 0)
+	}
+	if to > 0 {
+		timeRange["$lte"] = time.Unix(to, 0)
+	}
+	if len(timeRange) > 0 {
+		filter[mongo.TimestampKey] = timeRange
+	}
+
+	logs := mongo.FetchLogs(filter, limit)
+	if logs == nil {
+		logs = []types.M{}
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    logs,
+	})
+}
@@ -0,0 +1,354 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// maxMetricsPoints is the maximum number of points a resolution is chosen to serve
+const maxMetricsPoints = 3000
+
+const (
+	rawResolution        = "raw"
+	fiveMinuteResolution = "5m"
+	hourlyResolution     = "1h"
+)
+
+type metricsRecord struct {
+	Resolution       string           `json:"resolution"`
+	TimestampRecord  []int64          `json:"timestamp_record"`
+	UptimeRecord     []bool           `json:"uptime_record"`
+	CPURecord        []float64        `json:"cpu_record"`
+	MemoryRecord     []float64        `json:"memory_record"`
+	CPUPercentRecord []float64        `json:"cpu_percent_record"`
+	NetworkRxRecord  []float64        `json:"network_rx_record"`
+	NetworkTxRecord  []float64        `json:"network_tx_record"`
+	BlockReadRecord  []float64        `json:"block_read_record"`
+	BlockWriteRecord []float64        `json:"block_write_record"`
+	PIDsRecord       []int64          `json:"pids_record"`
+	RestartRecord    []int64          `json:"restart_record"`
+	OOMKilledRecord  []bool           `json:"oom_killed_record"`
+	Max              *aggregateRecord `json:"max,omitempty"`
+	P95              *aggregateRecord `json:"p95,omitempty"`
+}
+
+// aggregateRecord holds the maximum or the 95th percentile of the metrics rolled up in every period
+type aggregateRecord struct {
+	CPURecord        []float64 `json:"cpu_record"`
+	MemoryRecord     []float64 `json:"memory_record"`
+	CPUPercentRecord []float64 `json:"cpu_percent_record"`
+	NetworkRxRecord  []float64 `json:"network_rx_record"`
+	NetworkTxRecord  []float64 `json:"network_tx_record"`
+	BlockReadRecord  []float64 `json:"block_read_record"`
+	BlockWriteRecord []float64 `json:"block_write_record"`
+	PIDsRecord       []float64 `json:"pids_record"`
+}
+
+func newMetricsRecord(resolution string) *metricsRecord {
+	record := &metricsRecord{
+		Resolution:       resolution,
+		TimestampRecord:  []int64{},
+		UptimeRecord:     []bool{},
+		CPURecord:        []float64{},
+		MemoryRecord:     []float64{},
+		CPUPercentRecord: []float64{},
+		NetworkRxRecord:  []float64{},
+		NetworkTxRecord:  []float64{},
+		BlockReadRecord:  []float64{},
+		BlockWriteRecord: []float64{},
+		PIDsRecord:       []int64{},
+		RestartRecord:    []int64{},
+		OOMKilledRecord:  []bool{},
+	}
+	if resolution != rawResolution {
+		record.Max = newAggregateRecord()
+		record.P95 = newAggregateRecord()
+	}
+	return record
+}
+
+func newAggregateRecord() *aggregateRecord {
+	return &aggregateRecord{
+		CPURecord:        []float64{},
+		MemoryRecord:     []float64{},
+		CPUPercentRecord: []float64{},
+		NetworkRxRecord:  []float64{},
+		NetworkTxRecord:  []float64{},
+		BlockReadRecord:  []float64{},
+		BlockWriteRecord: []float64{},
+		PIDsRecord:       []float64{},
+	}
+}
+
+// add appends a raw metrics document to the record
+// Documents stored before a field was collected default to the zero value of that field
+func (record *metricsRecord) add(metrics types.M, uptime bool) {
+	oomKilled, _ := metrics["oom_killed"].(bool)
+	record.TimestampRecord = append(record.TimestampRecord, int64(metricFloat(metrics, mongo.TimestampKey)))
+	record.UptimeRecord = append(record.UptimeRecord, uptime)
+	record.CPURecord = append(record.CPURecord, metricFloat(metrics, "cpu_usage"))
+	record.MemoryRecord = append(record.MemoryRecord, metricFloat(metrics, "memory_usage"))
+	record.CPUPercentRecord = append(record.CPUPercentRecord, metricFloat(metrics, "cpu_percent"))
+	record.NetworkRxRecord = append(record.NetworkRxRecord, metricFloat(metrics, "network_rx"))
+	record.NetworkTxRecord = append(record.NetworkTxRecord, metricFloat(metrics, "network_tx"))
+	record.BlockReadRecord = append(record.BlockReadRecord, metricFloat(metrics, "block_read"))
+	record.BlockWriteRecord = append(record.BlockWriteRecord, metricFloat(metrics, "block_write"))
+	record.PIDsRecord = append(record.PIDsRecord, int64(metricFloat(metrics, "pids")))
+	record.RestartRecord = append(record.RestartRecord, int64(metricFloat(metrics, "restart_count")))
+	record.OOMKilledRecord = append(record.OOMKilledRecord, oomKilled)
+}
+
+// addRollup appends a metrics rollup to the record
+// The averages are stored in the main record and a period counts as up only if the container was always alive
+func (record *metricsRecord) addRollup(rollup *types.MetricsRollup, uptime bool) {
+	record.TimestampRecord = append(record.TimestampRecord, rollup.Timestamp)
+	record.UptimeRecord = append(record.UptimeRecord, uptime)
+	record.CPURecord = append(record.CPURecord, rollup.CPUUsage.Avg)
+	record.MemoryRecord = append(record.MemoryRecord, rollup.MemoryUsage.Avg)
+	record.CPUPercentRecord = append(record.CPUPercentRecord, rollup.CPUPercent.Avg)
+	record.NetworkRxRecord = append(record.NetworkRxRecord, rollup.NetworkRx.Avg)
+	record.NetworkTxRecord = append(record.NetworkTxRecord, rollup.NetworkTx.Avg)
+	record.BlockReadRecord = append(record.BlockReadRecord, rollup.BlockRead.Avg)
+	record.BlockWriteRecord = append(record.BlockWriteRecord, rollup.BlockWrite.Avg)
+	record.PIDsRecord = append(record.PIDsRecord, int64(rollup.PIDs.Avg))
+	record.RestartRecord = append(record.RestartRecord, rollup.RestartCount)
+	record.OOMKilledRecord = append(record.OOMKilledRecord, rollup.OOMKilled)
+
+	record.Max.add(func(aggregate types.Aggregate) float64 { return aggregate.Max }, rollup)
+	record.P95.add(func(aggregate types.Aggregate) float64 { return aggregate.P95 }, rollup)
+}
+
+// add appends the value selected from every aggregate of a metrics rollup to the record
+func (record *aggregateRecord) add(value func(types.Aggregate) float64, rollup *types.MetricsRollup) {
+	record.CPURecord = append(record.CPURecord, value(rollup.CPUUsage))
+	record.MemoryRecord = append(record.MemoryRecord, value(rollup.MemoryUsage))
+	record.CPUPercentRecord = append(record.CPUPercentRecord, value(rollup.CPUPercent))
+	record.NetworkRxRecord = append(record.NetworkRxRecord, value(rollup.NetworkRx))
+	record.NetworkTxRecord = append(record.NetworkTxRecord, value(rollup.NetworkTx))
+	record.BlockReadRecord = append(record.BlockReadRecord, value(rollup.BlockRead))
+	record.BlockWriteRecord = append(record.BlockWriteRecord, value(rollup.BlockWrite))
+	record.PIDsRecord = append(record.PIDsRecord, value(rollup.PIDs))
+}
+
+// metricFloat returns the numeric value of a field in a metrics document
+func metricFloat(metrics types.M, key string) float64 {
+	switch value := metrics[key].(type) {
+	case float64:
+		return value
+	case int64:
+		return float64(value)
+	case int32:
+		return float64(value)
+	}
+	return 0
+}
+
+// metricsResolution is a resolution at which the metrics of an application are stored
+type metricsResolution struct {
+	name       string
+	collection string
+	step       int64
+	retention  time.Duration
+}
+
+// metricsResolutions returns the resolutions at which the metrics are stored from the finest to the coarsest
+func metricsResolutions() []metricsResolution {
+	retention := configs.ServiceConfig.MetricsRetention
+	rawStep := int64(configs.ServiceConfig.AppMaker.MetricsInterval)
+	if rawStep <= 0 {
+		rawStep = 1
+	}
+	return []metricsResolution{
+		{rawResolution, mongo.MetricsCollection, rawStep, retention.RawWindow()},
+		{fiveMinuteResolution, mongo.FiveMinuteMetricsCollection, 5 * 60, retention.FiveMinuteWindow()},
+		{hourlyResolution, mongo.HourlyMetricsCollection, 60 * 60, retention.HourlyWindow()},
+	}
+}
+
+// selectResolution returns the finest resolution which retains the entire time span
+// without exceeding maxMetricsPoints, falling back to the coarsest resolution
+func selectResolution(timeSpan int64) metricsResolution {
+	resolutions := metricsResolutions()
+	for _, resolution := range resolutions {
+		if timeSpan <= int64(resolution.retention/time.Second) && timeSpan/resolution.step <= maxMetricsPoints {
+			return resolution
+		}
+	}
+	return resolutions[len(resolutions)-1]
+}
+
+// lookupResolution returns the resolution with the given name
+func lookupResolution(name string) (metricsResolution, bool) {
+	for _, resolution := range metricsResolutions() {
+		if resolution.name == name {
+			return resolution, true
+		}
+	}
+	return metricsResolution{}, false
+}
+
+// metricsTimeSpan returns the time span and the sparsity of the requested metrics in seconds
+func metricsTimeSpan(filter types.M) (int64, int64) {
+	var timeSpan int64
+	var sparsity int64
+	for unit, converter := range timeConversionMap {
+		if val, ok := filter[unit].(string); ok {
+			timeVal, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				continue
+			}
+			timeSpan += timeVal * converter
+		}
+	}
+
+	if val, ok := filter["sparsityvalue"].(string); ok {
+		sparsityVal, err := strconv.ParseInt(val, 10, 64)
+		if unit, ok := filter["sparsityunit"].(string); ok && err == nil {
+			sparsity = sparsityVal * timeConversionMap[unit]
+		}
+	}
+	return timeSpan, sparsity
+}
+
+// FetchMetrics retrieves the metrics of an application's container
+// The resolution of the metrics is chosen according to the requested time span
+// unless it is provided with the `resolution` query parameter
+func FetchMetrics(c *gin.Context) {
+	appName := c.Param("app")
+	filter := utils.QueryToFilter(c.Request.URL.Query())
+	timeSpan, sparsity := metricsTimeSpan(filter)
+
+	resolution := selectResolution(timeSpan)
+	if name := c.Query("resolution"); name != "" {
+		var ok bool
+		if resolution, ok = lookupResolution(name); !ok {
+			c.AbortWithStatusJSON(400, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("Invalid resolution %s, must be one of %s, %s or %s", name, rawResolution, fiveMinuteResolution, hourlyResolution),
+			})
+			return
+		}
+	}
+
+	timeFilter := types.M{
+		mongo.NameKey: appName,
+		mongo.TimestampKey: types.M{
+			"$gte": time.Now().Unix() - timeSpan,
+		},
+		mongo.InstanceTypeKey: types.M{"$ne": mongo.DBInstance},
+	}
+
+	if resolution.name == rawResolution {
+		fetchRawMetrics(c, timeFilter, sparsity)
+		return
+	}
+
+	rollups, err := mongo.FetchMetricsRollups(resolution.collection, timeFilter)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+
+	record := newMetricsRecord(resolution.name)
+	if len(rollups) > 0 {
+		baseTimestamp := rollups[0].Timestamp
+		downtime := false
+		for _, rollup := range rollups {
+			downtime = downtime || rollup.Uptime < 1
+			if (baseTimestamp - rollup.Timestamp) >= sparsity {
+				baseTimestamp = rollup.Timestamp
+				record.addRollup(rollup, !downtime)
+				downtime = false
+			}
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
+
+// fetchRawMetrics responds with the raw metrics of an application's container
+func fetchRawMetrics(c *gin.Context, filter types.M, sparsity int64) {
+	metrics := mongo.FetchContainerMetrics(filter, -1)
+
+	record := newMetricsRecord(rawResolution)
+	if len(metrics) > 0 {
+		baseTimestamp := metrics[0]["timestamp"].(int64)
+		var downtimeIntensity int = 0
+		var currTimestamp int64
+
+		for i := range metrics {
+			currTimestamp = metrics[i]["timestamp"].(int64)
+			if !metrics[i]["alive"].(bool) {
+				downtimeIntensity++
+			}
+			if (baseTimestamp - currTimestamp) >= sparsity {
+				baseTimestamp = currTimestamp
+				record.add(metrics[i], downtimeIntensity == 0)
+				downtimeIntensity = 0
+			}
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
+
+type databaseMetricsRecord struct {
+	TimestampRecord   []int64   `json:"timestamp_record"`
+	SizeRecord        []int64   `json:"size_record"`
+	TablesRecord      []int64   `json:"tables_record"`
+	ConnectionsRecord []int64   `json:"connections_record"`
+	QueryRateRecord   []float64 `json:"query_rate_record"`
+}
+
+// FetchDatabaseMetrics retrieves the size, number of tables, connections and query rate of a database
+// read from its server in the requested time span
+func FetchDatabaseMetrics(c *gin.Context) {
+	filter := utils.QueryToFilter(c.Request.URL.Query())
+	timeSpan, sparsity := metricsTimeSpan(filter)
+
+	metrics := mongo.FetchDatabaseMetrics(types.M{
+		mongo.NameKey: c.Param("db"),
+		mongo.TimestampKey: types.M{
+			"$gte": time.Now().Unix() - timeSpan,
+		},
+	}, -1)
+
+	record := &databaseMetricsRecord{
+		TimestampRecord:   []int64{},
+		SizeRecord:        []int64{},
+		TablesRecord:      []int64{},
+		ConnectionsRecord: []int64{},
+		QueryRateRecord:   []float64{},
+	}
+	var baseTimestamp int64
+	for i, metric := range metrics {
+		timestamp := int64(metricFloat(metric, mongo.TimestampKey))
+		if i > 0 && (baseTimestamp-timestamp) < sparsity {
+			continue
+		}
+		baseTimestamp = timestamp
+		record.TimestampRecord = append(record.TimestampRecord, timestamp)
+		record.SizeRecord = append(record.SizeRecord, int64(metricFloat(metric, "size")))
+		record.TablesRecord = append(record.TablesRecord, int64(metricFloat(metric, "tables")))
+		record.ConnectionsRecord = append(record.ConnectionsRecord, int64(metricFloat(metric, "connections")))
+		record.QueryRateRecord = append(record.QueryRateRecord, metricFloat(metric, "query_rate"))
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/factory"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/master/middlewares"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	// defaultQueryAuditsLimit is the number of queries returned from the audit log by default
+	defaultQueryAuditsLimit = 100
+
+	// maxQueryAuditsLimit is the maximum number of queries returned from the audit log at once
+	maxQueryAuditsLimit = 1000
+)
+
+// QueryDatabase runs an ad hoc query on a database through its query console via gRPC
+// The query is run with the credentials of the database and recorded in its audit log
+func QueryDatabase(c *gin.Context) {
+	db := c.Param("db")
+	query := &types.DatabaseQuery{}
+	if err := c.ShouldBindJSON(query); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	instanceURL, ok := fetchDatabaseNode(c, db)
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(query)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	response, err := factory.QueryDatabase(db, claims.GetEmail(), data, instanceURL)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	result := &types.QueryResult{}
+	if err := json.Unmarshal(response, result); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if result.Error != "" {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   result.Error,
+			"data":    result,
+		})
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// FetchQueryAudits returns the audit log of the queries run on a database through its query console
+func FetchQueryAudits(c *gin.Context) {
+	limit := int64(defaultQueryAuditsLimit)
+	if val := c.Query("limit"); val != "" {
+		parsed, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || parsed <= 0 || parsed > maxQueryAuditsLimit {
+			c.AbortWithStatusJSON(400, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("limit must be a number between 1 and %d", maxQueryAuditsLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+	audits, err := mongo.FetchQueryAudits(types.M{mongo.DatabaseKey: c.Param("db")}, limit)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    audits,
+	})
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// fetchSession returns the audit information of a single SSH session
+func fetchSession(c *gin.Context) types.M {
+	sessions := mongo.FetchSessions(types.M{mongo.SessionIDKey: c.Param("session")})
+	if len(sessions) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such session exists",
+		})
+		return nil
+	}
+	return sessions[0]
+}
+
+// GetAllSessions returns the audit information of all SSH sessions
+func GetAllSessions(c *gin.Context) {
+	filter := utils.QueryToFilter(c.Request.URL.Query())
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    mongo.FetchSessions(filter),
+	})
+}
+
+// GetSessionInfo returns the audit information of a single SSH session
+func GetSessionInfo(c *gin.Context) {
+	session := fetchSession(c)
+	if session == nil {
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    session,
+	})
+}
+
+// DownloadSessionRecording returns the asciinema transcript of a SSH session
+// which can be played with `asciinema play`
+func DownloadSessionRecording(c *gin.Context) {
+	session := fetchSession(c)
+	if session == nil {
+		return
+	}
+	if recorded, _ := session["recorded"].(bool); !recorded {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "Session was not recorded",
+		})
+		return
+	}
+	var recording bytes.Buffer
+	for _, chunk := range mongo.FetchSessionRecording(c.Param("session")) {
+		if data, ok := chunk["data"].(string); ok {
+			recording.WriteString(data)
+		}
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.cast", c.Param("session")))
+	c.Data(200, "application/x-asciicast", recording.Bytes())
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/master/middlewares"
+	"github.com/sdslabs/gasper/types"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// RegisterSSHKey registers a SSH public key for the logged in user
+func RegisterSSHKey(c *gin.Context) {
+	key := &types.SSHKey{}
+	if err := c.ShouldBind(key); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+
+	publicKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(key.GetPublicKey()))
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "Invalid SSH public key",
+		})
+		return
+	}
+	key.SetPublicKey(strings.TrimSpace(string(gossh.MarshalAuthorizedKey(publicKey))))
+	key.SetFingerprint(gossh.FingerprintSHA256(publicKey))
+
+	count, err := mongo.CountSSHKeys(types.M{
+		mongo.NameKey:  key.GetName(),
+		mongo.OwnerKey: claims.GetEmail(),
+	})
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count > 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("SSH key with name %s already exists", key.GetName()),
+		})
+		return
+	}
+
+	key.SetOwner(claims.GetEmail())
+	key.Apps = []string{}
+	key.SetDateTime()
+
+	// The fingerprint is unique across the collection which rejects keys registered concurrently
+	if _, err := mongo.RegisterSSHKey(key); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.AbortWithStatusJSON(400, gin.H{
+				"success": false,
+				"error":   "SSH key already registered",
+			})
+			return
+		}
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success":     true,
+		"message":     "SSH key registered",
+		"fingerprint": key.GetFingerprint(),
+	})
+}
+
+// FetchSSHKeysByUser returns all SSH public keys registered by the logged in user
+func FetchSSHKeysByUser(c *gin.Context) {
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    mongo.FetchSSHKeys(types.M{mongo.OwnerKey: claims.GetEmail()}),
+	})
+}
+
+// DeleteSSHKey deletes a SSH public key of the logged in user
+func DeleteSSHKey(c *gin.Context) {
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
+	filter := types.M{
+		mongo.NameKey:  c.Param("key"),
+		mongo.OwnerKey: claims.GetEmail(),
+	}
+	count, err := mongo.CountSSHKeys(filter)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such SSH key exists",
+		})
+		return
+	}
+	if _, err := mongo.DeleteSSHKey(filter); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "SSH key deleted",
+	})
+}
+
+// FetchSSHKeysByApp returns the owners, names and fingerprints of the SSH public keys of collaborators
+// granted access to an application
+func FetchSSHKeysByApp(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    mongo.FetchSSHKeyGrants(types.M{mongo.AppsKey: c.Param("app")}),
+	})
+}
+
+// GrantSSHKeyAccess grants a collaborator's SSH public key access to an application
+func GrantSSHKeyAccess(c *gin.Context) {
+	grant := &types.SSHKeyGrant{}
+	if err := c.ShouldBind(grant); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	count, err := mongo.GrantSSHKeyAccess(types.M{
+		mongo.NameKey:  grant.Name,
+		mongo.OwnerKey: grant.Email,
+	}, c.Param("app"))
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("User %s has no SSH key with name %s", grant.Email, grant.Name),
+		})
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "SSH key granted access",
+	})
+}
+
+// RevokeSSHKeyAccess revokes the access of a collaborator's SSH public key to an application
+func RevokeSSHKeyAccess(c *gin.Context) {
+	count, err := mongo.RevokeSSHKeyAccess(types.M{
+		mongo.NameKey:  c.Param("key"),
+		mongo.OwnerKey: c.Param("user"),
+		mongo.AppsKey:  c.Param("app"),
+	}, c.Param("app"))
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	if count == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "No such SSH key has access to the application",
+		})
+		return
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "SSH key access revoked",
+	})
+}
@@ -4,23 +4,37 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alphadose/gotty/backend/localcommand"
 	gotty "github.com/alphadose/gotty/server"
 	gottyUtils "github.com/alphadose/gotty/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sdslabs/gasper/configs"
 	"github.com/sdslabs/gasper/lib/redis"
 	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/master/middlewares"
 	"github.com/sdslabs/gasper/types"
 )
 
+// webTerminalTicketExpiry is the duration after which the ticket of a web terminal expires unless it is
+// refreshed, which happens while the terminal is running so that its reconnections redeem it again
+const webTerminalTicketExpiry = 5 * time.Minute
+
 // DeployWebTerminal shares an application container's shell over web using `gotty`
+// The terminal passes a ticket to GenSSH which records the session under the logged in user
 func DeployWebTerminal(c *gin.Context) {
 	appName := c.Param("app")
+	claims := middlewares.ExtractClaims(c)
+	if claims == nil {
+		utils.SendServerErrorResponse(c, errors.New("Failed to extract JWT claims"))
+		return
+	}
 	port, err := utils.GetFreePort()
 	if err != nil {
 		utils.SendServerErrorResponse(c, err)
@@ -71,8 +85,13 @@ func DeployWebTerminal(c *gin.Context) {
 		return
 	}
 
+	ticket := uuid.New().String()
 	termFactory, err := localcommand.NewFactory(
-		"ssh", []string{"-p", sshPort, fmt.Sprintf("%s@%s", appName, instanceURL)}, backendOptions)
+		"ssh", []string{
+			"-p", sshPort,
+			"-o", fmt.Sprintf("SetEnv=%s=%s", types.WebTerminalTicketEnv, ticket),
+			fmt.Sprintf("%s@%s", appName, instanceURL),
+		}, backendOptions)
 
 	if err != nil {
 		utils.SendServerErrorResponse(c, err)
@@ -85,7 +104,33 @@ func DeployWebTerminal(c *gin.Context) {
 		return
 	}
 
-	go srv.Run(context.Background(), gotty.WithGracefullContext(context.Background()))
+	if err := redis.RegisterWebTerminalTicket(ticket, appName, claims.GetEmail(), webTerminalTicketExpiry); err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+
+	go func() {
+		refresher := time.NewTicker(webTerminalTicketExpiry / 2)
+		defer refresher.Stop()
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			srv.Run(context.Background(), gotty.WithGracefullContext(context.Background()))
+		}()
+		for {
+			select {
+			case <-refresher.C:
+				if err := redis.RefreshWebTerminalTicket(ticket, webTerminalTicketExpiry); err != nil {
+					utils.LogError("Master-Controller-Term-1", err)
+				}
+			case <-stopped:
+				if err := redis.RemoveWebTerminalTicket(ticket); err != nil {
+					utils.LogError("Master-Controller-Term-2", err)
+				}
+				return
+			}
+		}
+	}()
 
 	c.JSON(200, gin.H{
 		"success": true,
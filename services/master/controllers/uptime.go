@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/uptime"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxIncidents is the maximum number of incidents returned in the uptime of an application
+const maxIncidents = 100
+
+// rawUptimeWindow is the window in which the raw metrics are used for the periods which are not rolled up yet
+const rawUptimeWindow = 15 * time.Minute
+
+// uptimeReportEntry is the availability of an application in the admin uptime report
+type uptimeReportEntry struct {
+	App   string `json:"app"`
+	Owner string `json:"owner"`
+	*uptime.Summary
+}
+
+// parseTarget parses the availability (in percent) promised by a SLA from the `target` query parameter
+func parseTarget(c *gin.Context) (float64, bool) {
+	val := c.Query("target")
+	if val == "" {
+		return 0, true
+	}
+	target, err := strconv.ParseFloat(val, 64)
+	if err != nil || target <= 0 || target > 100 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   "target must be a percentage greater than 0 and at most 100",
+		})
+		return 0, false
+	}
+	return target, true
+}
+
+// deployedAt returns the time at which an application instance was created
+func deployedAt(instance types.M) time.Time {
+	if datetime, ok := instance[mongo.DatetimeKey].(primitive.DateTime); ok {
+		return datetime.Time()
+	}
+	return time.Time{}
+}
+
+// fetchTimelines returns the timelines of the given applications, mapped to the time they were
+// deployed at, since the start
+// The liveness of the containers comes from the 5-minute rollups of their metrics and
+// the raw metrics of the latest periods, the reachability comes from the HTTP probes
+// The periods since the deployment in which no metrics were collected count as downtime,
+// except for the latest ones whose metrics might not have been collected yet and the ones
+// older than the retention of the rollups
+func fetchTimelines(deployments map[string]time.Time, start, now time.Time) (map[string]uptime.Timeline, error) {
+	timelines := make(map[string]uptime.Timeline, len(deployments))
+	apps := make([]string, 0, len(deployments))
+	for app := range deployments {
+		timelines[app] = make(uptime.Timeline)
+		apps = append(apps, app)
+	}
+	since := types.M{"$gte": uptime.SlotStart(start.Unix())}
+
+	rollups, err := mongo.FetchContainerUptime(types.M{
+		mongo.NameKey:      types.M{"$in": apps},
+		mongo.TimestampKey: since,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, rollup := range rollups {
+		timelines[rollup.Name].AddRollup(rollup)
+	}
+
+	samples, err := mongo.FetchMetricsSamples(types.M{
+		mongo.NameKey:         types.M{"$in": apps},
+		mongo.TimestampKey:    types.M{"$gte": now.Add(-rawUptimeWindow).Unix()},
+		mongo.InstanceTypeKey: types.M{"$ne": mongo.DBInstance},
+	})
+	if err != nil {
+		return nil, err
+	}
+	grouped := make(map[string][]*types.Metrics)
+	for _, sample := range samples {
+		grouped[sample.Name] = append(grouped[sample.Name], sample)
+	}
+	for app, appSamples := range grouped {
+		timelines[app].AddSamples(appSamples)
+	}
+
+	// The liveness is known only as far back as the 5-minute rollups are retained
+	retained := now.Add(-configs.ServiceConfig.MetricsRetention.FiveMinuteWindow())
+	settled := now.Add(-2 * configs.ServiceConfig.AppMaker.MetricsInterval * time.Second).Unix()
+	for app, deployed := range deployments {
+		from := start
+		if retained.After(from) {
+			from = retained
+		}
+		if deployed.After(from) {
+			from = deployed
+		}
+		timelines[app].AddMissing(from.Unix(), settled)
+	}
+
+	probes, err := mongo.FetchProbeSlots(types.M{
+		mongo.AppKey:       types.M{"$in": apps},
+		mongo.TimestampKey: since,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, probe := range probes {
+		timelines[probe.App].AddProbes(probe)
+	}
+	return timelines, nil
+}
+
+// longestPeriod returns the longest period over which the availability is reported
+func longestPeriod() uptime.Period {
+	longest := uptime.Periods[0]
+	for _, period := range uptime.Periods {
+		if period.Length > longest.Length {
+			longest = period
+		}
+	}
+	return longest
+}
+
+// FetchUptime returns the availability of an application over the last day, week and month
+// along with the incidents in which it was not fully available
+func FetchUptime(c *gin.Context) {
+	appName := c.Param("app")
+	target, ok := parseTarget(c)
+	if !ok {
+		return
+	}
+
+	instances := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.NameKey:         appName,
+		mongo.InstanceTypeKey: mongo.AppInstance,
+	})
+	if len(instances) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Application %s does not exist", appName),
+		})
+		return
+	}
+
+	now := time.Now()
+	longest := longestPeriod()
+	deployments := map[string]time.Time{appName: deployedAt(instances[0])}
+	timelines, err := fetchTimelines(deployments, now.Add(-longest.Length), now)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+	timeline := timelines[appName]
+
+	summaries := make([]*uptime.Summary, 0, len(uptime.Periods))
+	for _, period := range uptime.Periods {
+		summaries = append(summaries, timeline.Summarize(period, now, target))
+	}
+	incidents := timeline.Incidents(now.Add(-longest.Length), now)
+	if len(incidents) > maxIncidents {
+		incidents = incidents[:maxIncidents]
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data": gin.H{
+			"app":       appName,
+			"periods":   summaries,
+			"incidents": incidents,
+		},
+	})
+}
+
+// FetchUptimeReport returns the availability of all applications over a period
+// from the least to the most available
+func FetchUptimeReport(c *gin.Context) {
+	periodName := c.DefaultQuery("period", "month")
+	period, ok := uptime.LookupPeriod(periodName)
+	if !ok {
+		c.AbortWithStatusJSON(400, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Invalid period %s, must be one of day, week or month", periodName),
+		})
+		return
+	}
+	target, ok := parseTarget(c)
+	if !ok {
+		return
+	}
+
+	instances := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.AppInstance,
+	})
+	owners := make(map[string]string, len(instances))
+	deployments := make(map[string]time.Time, len(instances))
+	apps := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		name, ok := instance[mongo.NameKey].(string)
+		if !ok {
+			continue
+		}
+		owners[name], _ = instance[mongo.OwnerKey].(string)
+		deployments[name] = deployedAt(instance)
+		apps = append(apps, name)
+	}
+
+	now := time.Now()
+	timelines, err := fetchTimelines(deployments, now.Add(-period.Length), now)
+	if err != nil {
+		utils.SendServerErrorResponse(c, err)
+		return
+	}
+
+	report := make([]*uptimeReportEntry, 0, len(apps))
+	for _, app := range apps {
+		report = append(report, &uptimeReportEntry{
+			App:     app,
+			Owner:   owners[app],
+			Summary: timelines[app].Summarize(period, now, target),
+		})
+	}
+	// Applications which were never observed are listed at the end
+	sort.SliceStable(report, func(i, j int) bool {
+		first, second := report[i].Availability, report[j].Availability
+		if first == nil || second == nil {
+			return first != nil
+		}
+		return *first < *second
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
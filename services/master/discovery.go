@@ -9,19 +9,24 @@ import (
 	"github.com/sdslabs/gasper/lib/mongo"
 	"github.com/sdslabs/gasper/lib/redis"
 	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/services/genssh"
 	"github.com/sdslabs/gasper/types"
 )
 
 var instanceRegistrationBindings = map[string]func(instances []types.M, currentIP string, config *configs.GenericService){
 	types.AppMaker: registerApps,
-	types.MySQL:    registerDatabases,
-	types.MongoDB:  registerDatabases,
+	types.GenSSH:   registerSSHHostKeys,
 }
 
 var instanceServiceBindings = map[string]func(currentIP, service string) []types.M{
 	types.AppMaker: fetchBoundApps,
-	types.MySQL:    fetchBoundDatabases,
-	types.MongoDB:  fetchBoundDatabases,
+}
+
+func init() {
+	for engine := range configs.ServiceConfig.DbMaker.Engines() {
+		instanceRegistrationBindings[engine] = registerDatabases
+		instanceServiceBindings[engine] = fetchBoundDatabases
+	}
 }
 
 func fetchBoundApps(currentIP, service string) []types.M {
@@ -79,6 +84,17 @@ func registerDatabases(instances []types.M, currentIP string, config *configs.Ge
 	}
 }
 
+func registerSSHHostKeys(instances []types.M, currentIP string, config *configs.GenericService) {
+	hostKeys, err := genssh.HostPublicKeys()
+	if err != nil {
+		utils.LogError("Master-Discovery-6", err)
+		return
+	}
+	if err := redis.RegisterSSHHostKeys(fmt.Sprintf("%s:%d", currentIP, config.Port), hostKeys); err != nil {
+		utils.LogError("Master-Discovery-7", err)
+	}
+}
+
 // exposeService exposes a single microservice along with its apps
 func exposeService(service, currentIP string, config *configs.GenericService) {
 	count := 0
@@ -20,6 +20,14 @@ var disallowedApplicationNames = []string{
 	types.MongoDB,
 	types.DbMaker,
 	types.GenSSH,
+	// The containers of applications share the same namespace as the containers of the
+	// database servers and of the databases deployed in their own containers
+	types.MariaDB,
+	types.PostgreSQL,
+	types.Redis,
+	types.Memcached,
+	types.RabbitMQ,
+	types.MinIO,
 }
 
 var disallowedDatabaseNames = []string{
@@ -31,6 +39,18 @@ var disallowedDatabaseNames = []string{
 	"information_schema",
 	"performance_schema",
 	"sys",
+	"postgres",
+	"template0",
+	"template1",
+	// The containers of the database servers and of the databases deployed in their own
+	// containers share the same namespace
+	types.MariaDB,
+	types.MongoDB,
+	types.PostgreSQL,
+	types.Redis,
+	types.Memcached,
+	types.RabbitMQ,
+	types.MinIO,
 }
 
 func isUniqueInstance(instanceName, instanceType string) (bool, error) {
@@ -0,0 +1,106 @@
+package master
+
+import (
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+// rollupGrace is the time allowed for the metrics of a period to be stored before it is rolled up
+const rollupGrace = time.Minute
+
+// rollupLevel is a resolution at which the raw metrics are aggregated
+type rollupLevel struct {
+	collection string
+	resolution time.Duration
+	retention  func() time.Duration
+}
+
+var rollupLevels = []rollupLevel{
+	{
+		collection: mongo.FiveMinuteMetricsCollection,
+		resolution: 5 * time.Minute,
+		retention:  configs.ServiceConfig.MetricsRetention.FiveMinuteWindow,
+	},
+	{
+		collection: mongo.HourlyMetricsCollection,
+		resolution: time.Hour,
+		retention:  configs.ServiceConfig.MetricsRetention.HourlyWindow,
+	},
+}
+
+// pending returns the start of the periods which have ended but are not rolled up yet
+// Periods whose raw metrics have already expired are skipped
+func (level rollupLevel) pending(now time.Time) []int64 {
+	resolution := int64(level.resolution / time.Second)
+	end := now.Add(-rollupGrace).Unix() / resolution * resolution
+	start := now.Add(-configs.ServiceConfig.MetricsRetention.RawWindow()).Unix()/resolution*resolution + resolution
+
+	latest := mongo.FetchMetricsFrom(level.collection, types.M{}, 1)
+	if len(latest) > 0 {
+		if timestamp, ok := latest[0][mongo.TimestampKey].(int64); ok && timestamp+resolution > start {
+			start = timestamp + resolution
+		}
+	}
+
+	periods := []int64{}
+	for period := start; period+resolution <= end; period += resolution {
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// rollup aggregates the raw metrics of a period for every container
+func (level rollupLevel) rollup(period int64) error {
+	metrics, err := mongo.FetchMetricsInRange(period, period+int64(level.resolution/time.Second))
+	if err != nil {
+		return err
+	}
+
+	samples := make(map[string][]*types.Metrics)
+	for _, sample := range metrics {
+		samples[sample.Name] = append(samples[sample.Name], sample)
+	}
+
+	expiresAt := time.Unix(period, 0).Add(level.retention())
+	for name, containerSamples := range samples {
+		rollup := types.NewMetricsRollup(name, period, containerSamples)
+		rollup.ExpiresAt = expiresAt
+		if err := mongo.UpsertMetricsRollup(level.collection, rollup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollupMetrics aggregates the raw metrics of all the periods which have ended into 5-minute and hourly rollups
+// The rollups are idempotent hence multiple Master instances can run them concurrently
+func rollupMetrics() {
+	now := time.Now()
+	for _, level := range rollupLevels {
+		for _, period := range level.pending(now) {
+			if err := level.rollup(period); err != nil {
+				utils.LogError("Master-Rollup-1", err)
+				break
+			}
+		}
+	}
+
+	// Metrics stored before the retention subsystem existed do not expire by themselves
+	cutoff := now.Add(-configs.ServiceConfig.MetricsRetention.RawWindow()).Unix()
+	if _, err := mongo.DeleteMany(mongo.MetricsCollection, types.M{
+		mongo.ExpiresAtKey: types.M{"$exists": false},
+		mongo.TimestampKey: types.M{"$lt": cutoff},
+	}); err != nil {
+		utils.LogError("Master-Rollup-2", err)
+	}
+}
+
+// ScheduleMetricsRollup runs rollupMetrics every 5 minutes
+func ScheduleMetricsRollup() {
+	scheduler := utils.NewScheduler(5*time.Minute, rollupMetrics)
+	scheduler.RunAsync()
+}
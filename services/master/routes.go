@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/sdslabs/gasper/lib/metrics"
 	c "github.com/sdslabs/gasper/services/master/controllers"
 	m "github.com/sdslabs/gasper/services/master/middlewares"
 	"github.com/sdslabs/gasper/types"
@@ -27,6 +28,7 @@ func NewService() http.Handler {
 		MaxAge:           12 * time.Hour,
 	}
 	router.Use(cors.New(corsConfig))
+	router.Use(metrics.InstrumentRoutes)
 	router.NoRoute(c.Handle404)
 
 	// Bind frontend generated from https://github.com/sdslabs/SWS
@@ -62,26 +64,62 @@ func NewService() http.Handler {
 	app := router.Group("/apps")
 	app.Use(m.AuthRequired())
 	{
-		app.POST("/:language", m.ValidateApplicationRequest, c.CreateApp)
+		// The language shares the wildcard of the application's name as gin requires
+		// the wildcards at the same position of a path to be named alike
+		app.POST("/:app", m.ValidateApplicationRequest, c.CreateApp)
 		app.GET("", c.FetchAppsByUser)
 		app.GET("/:app", m.IsAppOwner, c.GetApplicationInfo)
 		app.PUT("/:app", m.IsAppOwner, c.UpdateAppByName)
 		app.DELETE("/:app", m.IsAppOwner, c.DeleteApp)
 		app.GET("/:app/logs", m.IsAppOwner, c.FetchAppLogs)
+		app.GET("/:app/logs/follow", m.IsAppOwner, c.FollowAppLogs)
+		app.GET("/:app/logs/search", m.IsAppOwner, c.SearchAppLogs)
 		app.PATCH("/:app/rebuild", m.IsAppOwner, c.RebuildApp)
 		app.PATCH("/:app/transfer/:user", m.IsAppOwner, c.TransferApplicationOwnership)
 		app.GET("/:app/term", m.IsAppOwner, c.DeployWebTerminal)
 		app.GET("/:app/metrics", m.IsAppOwner, c.FetchMetrics)
+		app.GET("/:app/uptime", m.IsAppOwner, c.FetchUptime)
+		app.GET("/:app/keys", m.IsAppOwner, c.FetchSSHKeysByApp)
+		app.POST("/:app/keys", m.IsAppOwner, c.GrantSSHKeyAccess)
+		app.DELETE("/:app/keys/:user/:key", m.IsAppOwner, c.RevokeSSHKeyAccess)
+		app.GET("/:app/drains", m.IsAppOwner, c.FetchDrainsByApp)
+		app.POST("/:app/drains", m.IsAppOwner, c.CreateDrain)
+		app.DELETE("/:app/drains/:drain", m.IsAppOwner, c.DeleteDrain)
+		app.GET("/:app/alerts", m.IsAppOwner, c.FetchAlertsByApp)
+		app.GET("/:app/alerts/rules", m.IsAppOwner, c.FetchAlertRulesByApp)
+		app.POST("/:app/alerts/rules", m.IsAppOwner, c.CreateAlertRule)
+		app.DELETE("/:app/alerts/rules/:rule", m.IsAppOwner, c.DeleteAlertRule)
+		app.PUT("/:app/alerts/rules/:rule/silence", m.IsAppOwner, c.SilenceAlertRule)
+		app.DELETE("/:app/alerts/rules/:rule/silence", m.IsAppOwner, c.UnsilenceAlertRule)
 	}
 
 	db := router.Group("/dbs")
 	db.Use(m.AuthRequired())
 	{
-		db.POST("/:database", m.ValidateDatabaseRequest, c.CreateDatabase)
+		// The type of the database shares the wildcard of the database's name as gin requires
+		// the wildcards at the same position of a path to be named alike
+		db.POST("/:db", m.ValidateDatabaseRequest, c.CreateDatabase)
 		db.GET("", c.FetchDatabasesByUser)
 		db.GET("/:db", m.IsDatabaseOwner, c.GetDatabaseInfo)
+		db.GET("/:db/metrics", m.IsDatabaseOwner, c.FetchDatabaseMetrics)
 		db.DELETE("/:db", m.IsDatabaseOwner, c.DeleteDatabase)
 		db.PATCH("/:db/transfer/:user", m.IsDatabaseOwner, c.TransferDatabaseOwnership)
+		db.GET("/:db/backups", m.IsDatabaseOwner, c.FetchBackupsByDatabase)
+		db.POST("/:db/backups", m.IsDatabaseOwner, c.CreateBackup)
+		db.DELETE("/:db/backups/:backup", m.IsDatabaseOwner, c.DeleteBackup)
+		db.GET("/:db/backup-schedule", m.IsDatabaseOwner, c.GetBackupSchedule)
+		db.PUT("/:db/backup-schedule", m.IsDatabaseOwner, c.UpdateBackupSchedule)
+		db.DELETE("/:db/backup-schedule", m.IsDatabaseOwner, c.DeleteBackupSchedule)
+		db.POST("/:db/restore/:backup", m.IsDatabaseOwner, c.RestoreDatabase)
+		db.GET("/:db/restores", m.IsDatabaseOwner, c.FetchRestoresByDatabase)
+		db.POST("/:db/clone", m.IsDatabaseOwner, m.ValidateDatabaseRequest, c.CloneDatabase)
+		db.POST("/:db/rotate-password", m.IsDatabaseOwner, c.RotateDatabasePassword)
+		db.GET("/:db/users", m.IsDatabaseOwner, c.FetchDatabaseUsers)
+		db.POST("/:db/users", m.IsDatabaseOwner, c.CreateDatabaseUser)
+		db.DELETE("/:db/users/:user", m.IsDatabaseOwner, c.DeleteDatabaseUser)
+		db.POST("/:db/users/:user/rotate-password", m.IsDatabaseOwner, c.RotateDatabasePassword)
+		db.POST("/:db/query", m.IsDatabaseOwner, c.QueryDatabase)
+		db.GET("/:db/queries", m.IsDatabaseOwner, c.FetchQueryAudits)
 		db.GET("/:db/redislogs",m.IsDatabaseOwner,c.GetRedisLogs)
 	}
 
@@ -91,6 +129,13 @@ func NewService() http.Handler {
 		user.GET("", c.GetLoggedInUserInfo)
 		user.PUT("/password", c.UpdatePassword)
 		user.DELETE("", c.DeleteUser)
+		user.POST("/keys", c.RegisterSSHKey)
+		user.GET("/keys", c.FetchSSHKeysByUser)
+		user.DELETE("/keys/:key", c.DeleteSSHKey)
+		user.POST("/channels", c.CreateChannel)
+		user.GET("/channels", c.FetchChannelsByUser)
+		user.DELETE("/channels/:channel", c.DeleteChannel)
+		user.POST("/channels/:channel/test", c.TestChannel)
 	}
 
 	admin := router.Group("/admin")
@@ -107,6 +152,9 @@ func NewService() http.Handler {
 			dbs.GET("", c.GetAllDatabases)
 			dbs.GET("/:db", c.GetDatabaseInfo)
 			dbs.DELETE("/:db", c.DeleteDatabase)
+			dbs.GET("/:db/logs/follow", c.FollowDatabaseServerLogs)
+			dbs.POST("/:db/migrate/:node", c.MigrateDatabase)
+			dbs.PUT("/:db/quota", c.UpdateDatabaseQuota)
 		}
 		users := admin.Group("/users")
 		{
@@ -121,6 +169,13 @@ func NewService() http.Handler {
 			nodes.GET("", c.GetAllNodes)
 			nodes.GET("/:type", c.GetNodesByName)
 		}
+		admin.GET("/uptime", c.FetchUptimeReport)
+		sessions := admin.Group("/sessions")
+		{
+			sessions.GET("", c.GetAllSessions)
+			sessions.GET("/:session", c.GetSessionInfo)
+			sessions.GET("/:session/recording", c.DownloadSessionRecording)
+		}
 	}
 
 	return router
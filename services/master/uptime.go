@@ -0,0 +1,118 @@
+package master
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sdslabs/gasper/configs"
+	"github.com/sdslabs/gasper/lib/cloudflare"
+	"github.com/sdslabs/gasper/lib/mongo"
+	"github.com/sdslabs/gasper/lib/redis"
+	"github.com/sdslabs/gasper/lib/uptime"
+	"github.com/sdslabs/gasper/lib/utils"
+	"github.com/sdslabs/gasper/types"
+)
+
+const (
+	defaultProbeInterval = 60 * time.Second
+	defaultProbeTimeout  = 10 * time.Second
+	defaultProbeDays     = 35
+
+	// probeWorkers is the number of applications probed concurrently
+	probeWorkers = 16
+)
+
+// probeClient sends the HTTP probes to the applications
+// Redirects are not followed since a redirecting application is reachable
+var probeClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// probe sends a HTTP request to an application through a GenProxy instance
+// The application is considered down if the request fails or it responds with a server error
+func probe(app, proxy string) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/", proxy), nil)
+	if err != nil {
+		return err
+	}
+	req.Host = fmt.Sprintf("%s.%s.%s", app, cloudflare.ApplicationInstance, configs.GasperConfig.Domain)
+	req.Header.Set("User-Agent", "Gasper-Uptime-Probe")
+
+	res, err := probeClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("GenProxy instance %s responded with status %d", proxy, res.StatusCode)
+	}
+	return nil
+}
+
+// probeApplications probes every application through the GenProxy instances in round robin
+// and counts the results in the 5-minute period of the probe
+func probeApplications() {
+	proxies, err := redis.FetchServiceInstances(types.GenProxy)
+	if err != nil {
+		utils.LogError("Master-Uptime-1", err)
+		return
+	}
+	if len(proxies) == 0 {
+		utils.Log("Master-Uptime-2", "No GenProxy instance is available for probing applications", utils.ErrorTAG)
+		return
+	}
+	apps := mongo.FetchDocs(mongo.InstanceCollection, types.M{
+		mongo.InstanceTypeKey: mongo.AppInstance,
+	})
+
+	retention := configs.ServiceConfig.Master.Uptime.Retention
+	if retention <= 0 {
+		retention = defaultProbeDays
+	}
+	now := time.Now()
+	slot := uptime.SlotStart(now.Unix())
+	expiresAt := time.Unix(slot, 0).Add(time.Duration(retention) * 24 * time.Hour)
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for i := 0; i < probeWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				name, ok := apps[index][mongo.NameKey].(string)
+				if !ok {
+					continue
+				}
+				probeErr := probe(name, proxies[index%len(proxies)])
+				if err := mongo.RecordProbe(name, slot, probeErr, expiresAt); err != nil {
+					utils.LogError("Master-Uptime-3", err)
+				}
+			}
+		}()
+	}
+	for index := range apps {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// ScheduleProbes runs probeApplications at the given probe interval
+func ScheduleProbes() {
+	interval := configs.ServiceConfig.Master.Uptime.ProbeInterval * time.Second
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	timeout := configs.ServiceConfig.Master.Uptime.Timeout * time.Second
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	probeClient.Timeout = timeout
+	scheduler := utils.NewScheduler(interval, probeApplications)
+	scheduler.RunAsync()
+}
@@ -0,0 +1,133 @@
+package types
+
+import "time"
+
+const (
+	// EmailChannel sends notifications as emails through the configured SMTP server
+	EmailChannel = "email"
+
+	// WebhookChannel posts notifications as JSON documents to an endpoint
+	WebhookChannel = "webhook"
+
+	// SlackChannel posts notifications to a Slack compatible incoming webhook
+	SlackChannel = "slack"
+)
+
+const (
+	// AlertOK is the state of an alert rule whose condition is not satisfied
+	AlertOK = "ok"
+
+	// AlertFiring is the state of an alert rule whose condition is satisfied
+	AlertFiring = "firing"
+
+	// AlertResolved is the state of an alert whose rule's condition is no longer satisfied
+	AlertResolved = "resolved"
+)
+
+// IncreasedOperator is the operator of the alert rules which fire when a metric increases
+const IncreasedOperator = "increased"
+
+// NotificationChannel is a destination to which the alerts of a user are sent
+type NotificationChannel struct {
+	ID       string    `json:"id" bson:"channel_id"`
+	Owner    string    `json:"owner" bson:"owner"`
+	Name     string    `form:"name" json:"name" bson:"name" binding:"required"`
+	Type     string    `form:"type" json:"type" bson:"type" binding:"required"`
+	Target   string    `form:"target" json:"target" bson:"target" binding:"required"`
+	Datetime time.Time `json:"datetime" bson:"datetime"`
+}
+
+// GetID returns the ID of the notification channel
+func (channel *NotificationChannel) GetID() string {
+	return channel.ID
+}
+
+// SetID sets the ID of the notification channel in its context
+func (channel *NotificationChannel) SetID(id string) {
+	channel.ID = id
+}
+
+// SetOwner sets the owner of the notification channel in its context
+func (channel *NotificationChannel) SetOwner(owner string) {
+	channel.Owner = owner
+}
+
+// GetType returns the type of the notification channel
+func (channel *NotificationChannel) GetType() string {
+	return channel.Type
+}
+
+// GetTarget returns the email address or the URL to which notifications are sent
+func (channel *NotificationChannel) GetTarget() string {
+	return channel.Target
+}
+
+// SetDateTime sets the date and time of the notification channel's creation
+func (channel *NotificationChannel) SetDateTime() {
+	channel.Datetime = time.Now()
+}
+
+// AlertRule is a condition on the metrics of an application which raises an alert when satisfied
+// The condition must hold for the given duration or number of consecutive metrics intervals
+type AlertRule struct {
+	ID            string    `json:"id" bson:"rule_id"`
+	App           string    `json:"app" bson:"app"`
+	Owner         string    `json:"owner" bson:"owner"`
+	Name          string    `form:"name" json:"name" bson:"name" binding:"required"`
+	Metric        string    `form:"metric" json:"metric" bson:"metric" binding:"required"`
+	Operator      string    `form:"operator" json:"operator" bson:"operator" binding:"required"`
+	Threshold     float64   `form:"threshold" json:"threshold" bson:"threshold"`
+	For           string    `form:"for" json:"for,omitempty" bson:"for,omitempty"`
+	Intervals     int       `form:"intervals" json:"intervals,omitempty" bson:"intervals,omitempty"`
+	Channels      []string  `form:"channels" json:"channels" bson:"channels"`
+	State         string    `json:"state" bson:"state"`
+	Value         float64   `json:"value" bson:"value"`
+	ActiveSince   time.Time `json:"active_since" bson:"active_since"`
+	LastNotified  time.Time `json:"last_notified" bson:"last_notified"`
+	SilencedUntil time.Time `json:"silenced_until" bson:"silenced_until"`
+	Datetime      time.Time `json:"datetime" bson:"datetime"`
+}
+
+// GetID returns the ID of the alert rule
+func (rule *AlertRule) GetID() string {
+	return rule.ID
+}
+
+// SetID sets the ID of the alert rule in its context
+func (rule *AlertRule) SetID(id string) {
+	rule.ID = id
+}
+
+// SetApp sets the name of the application whose metrics are evaluated
+func (rule *AlertRule) SetApp(app string) {
+	rule.App = app
+}
+
+// SetOwner sets the owner of the alert rule in its context
+func (rule *AlertRule) SetOwner(owner string) {
+	rule.Owner = owner
+}
+
+// IsSilenced checks whether the notifications of the alert rule are silenced at the given time
+func (rule *AlertRule) IsSilenced(at time.Time) bool {
+	return rule.SilencedUntil.After(at)
+}
+
+// SetDateTime sets the date and time of the alert rule's creation
+func (rule *AlertRule) SetDateTime() {
+	rule.Datetime = time.Now()
+}
+
+// Alert is a change in the state of an alert rule recorded in the history of an application
+type Alert struct {
+	ID        string    `json:"id" bson:"alert_id"`
+	RuleID    string    `json:"rule_id" bson:"rule_id"`
+	RuleName  string    `json:"rule_name" bson:"rule_name"`
+	App       string    `json:"app" bson:"app"`
+	State     string    `json:"state" bson:"state"`
+	Value     float64   `json:"value" bson:"value"`
+	Message   string    `json:"message" bson:"message"`
+	Silenced  bool      `json:"silenced" bson:"silenced"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	ExpiresAt time.Time `json:"-" bson:"expires_at"`
+}
@@ -0,0 +1,110 @@
+package types
+
+import "time"
+
+const (
+	// BackupRunning is the status of a backup which is being taken
+	BackupRunning = "running"
+
+	// BackupCompleted is the status of a backup which is stored successfully
+	BackupCompleted = "completed"
+
+	// BackupFailed is the status of a backup which could not be taken
+	BackupFailed = "failed"
+)
+
+const (
+	// ManualBackup is the trigger of backups requested by users
+	ManualBackup = "manual"
+
+	// ScheduledBackup is the trigger of backups taken according to the schedule of a database
+	ScheduledBackup = "scheduled"
+
+	// SnapshotBackup is the trigger of backups taken for cloning the live state of a database
+	SnapshotBackup = "snapshot"
+
+	// MigrationBackup is the trigger of backups taken for moving a database to another node
+	// The writes to the database are revoked until it is moved or the migration fails
+	MigrationBackup = "migration"
+)
+
+const (
+	// RestoreOperation restores a backup of a database into the same database
+	RestoreOperation = "restore"
+
+	// CloneOperation restores a backup or a snapshot of a database into a new database
+	CloneOperation = "clone"
+
+	// MigrateOperation restores a snapshot or the latest backup of a database into another node
+	// and moves the database there
+	MigrateOperation = "migrate"
+)
+
+// Backup holds the metadata of a backup of a database
+type Backup struct {
+	ID          string    `json:"id" bson:"backup_id"`
+	Database    string    `json:"database" bson:"database"`
+	Language    string    `json:"language" bson:"language"`
+	Owner       string    `json:"owner" bson:"owner"`
+	Node        string    `json:"-" bson:"node"`
+	HostIP      string    `json:"host_ip" bson:"host_ip"`
+	Trigger     string    `json:"trigger" bson:"trigger"`
+	Status      string    `json:"status" bson:"status"`
+	Format      string    `json:"format" bson:"format"`
+	Compression string    `json:"compression" bson:"compression"`
+	Target      string    `json:"target" bson:"target"`
+	Encrypted   bool      `json:"encrypted" bson:"encrypted"`
+	Path        string    `json:"-" bson:"path"`
+	RawSize     int64     `json:"raw_size" bson:"raw_size"`
+	Size        int64     `json:"size" bson:"size"`
+	Checksum    string    `json:"checksum,omitempty" bson:"checksum,omitempty"`
+	Error       string    `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at" bson:"started_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// GetID returns the ID of the backup
+func (backup *Backup) GetID() string {
+	return backup.ID
+}
+
+// IsCompleted checks whether the backup is stored successfully
+func (backup *Backup) IsCompleted() bool {
+	return backup.Status == BackupCompleted
+}
+
+// BackupSchedule is the schedule according to which the backups of a database are taken
+// Only the latest scheduled backups are retained, as many as the retention count
+type BackupSchedule struct {
+	Database  string    `json:"database" bson:"database"`
+	Interval  string    `form:"interval" json:"interval" bson:"interval" binding:"required"`
+	Retention int       `form:"retention" json:"retention" bson:"retention"`
+	NextRun   time.Time `json:"next_run" bson:"next_run"`
+	LastRun   time.Time `json:"last_run,omitempty" bson:"last_run,omitempty"`
+}
+
+// GetInterval returns the time between the backups of the schedule
+func (schedule *BackupSchedule) GetInterval() (time.Duration, error) {
+	return time.ParseDuration(schedule.Interval)
+}
+
+// Restore holds the state of a restore of a backup into a database
+// Its status is one of the statuses of backups
+type Restore struct {
+	ID          string    `json:"id" bson:"restore_id"`
+	Database    string    `json:"database" bson:"database"`
+	Source      string    `json:"source" bson:"source"`
+	BackupID    string    `json:"backup_id,omitempty" bson:"backup_id,omitempty"`
+	Operation   string    `json:"operation" bson:"operation"`
+	Node        string    `json:"node,omitempty" bson:"node,omitempty"`
+	Status      string    `json:"status" bson:"status"`
+	Error       string    `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at" bson:"started_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// CloneRequest is the request for cloning a database from one of its backups or its live state
+// The new database's name and password are provided along with it
+type CloneRequest struct {
+	Backup string `json:"backup,omitempty"`
+}
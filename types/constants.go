@@ -28,6 +28,18 @@ const (
 	// Redis holds the name of `redis` component under 'dbmaker'
 	Redis = "redis"
 
+	// MariaDB holds the name of `mariadb` component under 'dbmaker'
+	MariaDB = "mariadb"
+
+	// Memcached holds the name of `memcached` component under 'dbmaker'
+	Memcached = "memcached"
+
+	// RabbitMQ holds the name of `rabbitmq` component under 'dbmaker'
+	RabbitMQ = "rabbitmq"
+
+	// MinIO holds the name of `minio` object storage component under 'dbmaker'
+	MinIO = "minio"
+
 	// GenSSH holds the name of `genssh` microservice
 	GenSSH = "genssh"
 
@@ -40,9 +52,18 @@ const (
 	// GenProxySSL holds the name of `genproxy` microservice with SSL support
 	GenProxySSL = "genproxy_ssl"
 
+	// GenDNSDoH holds the name of `gendns` microservice with DNS-over-HTTPS support
+	GenDNSDoH = "gendns_doh"
+
+	// GenDNSDoT holds the name of `gendns` microservice with DNS-over-TLS support
+	GenDNSDoT = "gendns_dot"
+
 	// Jikan holds the name of `jikan` microservice
 	Jikan = "jikan"
 
+	// Exporter holds the name of `exporter` microservice exposing the Prometheus metrics
+	Exporter = "exporter"
+
 	// DefaultMemory is the default memory allotted to a container
 	DefaultMemory = 0.5
 
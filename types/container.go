@@ -43,6 +43,11 @@ type DatabaseContainer struct {
 	Cmd []string
 	// Environment variables
 	Env M
+	// Hostname of the container, assigned by docker if empty
+	Hostname string
+	// Resource limits, 0 if the resource is not limited
+	Memory int64
+	CPU    int64
 }
 
 // HasCustomCMD checks whether a database container needs custom CMD commands on boot
@@ -10,23 +10,33 @@ type Database interface {
 	GetPassword() string
 	GetUser() string
 	SetContainerPort(port int)
+	GetContainerPort() int
+	GetQuota() DatabaseQuota
+	IsDedicated() bool
+	GetVersion() string
+	GetRootPassword() string
 }
 
 // DatabaseConfig is the configuration required for creating a database
 type DatabaseConfig struct {
-	Name          string    `json:"name" bson:"name" valid:"required~Field 'name' is required but was not provided,alphanum~Field 'name' should only have alphanumeric characters,lowercase~Field 'name' should have only lowercase characters"`
-	Password      string    `json:"password" bson:"password" valid:"required~Field 'password' is required but was not provided"`
-	User          string    `json:"user,omitempty" bson:"user,omitempty"`
-	InstanceType  string    `json:"instance_type,omitempty" bson:"instance_type,omitempty"`
-	Language      string    `json:"language,omitempty" bson:"language,omitempty"`
-	CloudflareID  string    `json:"cloudflare_id,omitempty" bson:"cloudflare_id,omitempty"`
-	DbURL         string    `json:"db_url,omitempty" bson:"db_url,omitempty"`
-	HostIP        string    `json:"host_ip,omitempty" bson:"host_ip,omitempty"`
-	PublicIP      string    `json:"public_ip,omitempty" bson:"public_ip,omitempty"`
-	ContainerPort int       `json:"port,omitempty" bson:"port,omitempty"`
-	Owner         string    `json:"owner,omitempty" bson:"owner,omitempty"`
-	Datetime      time.Time `json:"datetime" bson:"datetime"`
-	Success       bool      `json:"success,omitempty" bson:"-"`
+	Name          string        `json:"name" bson:"name" valid:"required~Field 'name' is required but was not provided,alphanum~Field 'name' should only have alphanumeric characters,lowercase~Field 'name' should have only lowercase characters"`
+	Password      string        `json:"password" bson:"password" valid:"required~Field 'password' is required but was not provided"`
+	User          string        `json:"user,omitempty" bson:"user,omitempty"`
+	InstanceType  string        `json:"instance_type,omitempty" bson:"instance_type,omitempty"`
+	Language      string        `json:"language,omitempty" bson:"language,omitempty"`
+	CloudflareID  string        `json:"cloudflare_id,omitempty" bson:"cloudflare_id,omitempty"`
+	DbURL         string        `json:"db_url,omitempty" bson:"db_url,omitempty"`
+	HostIP        string        `json:"host_ip,omitempty" bson:"host_ip,omitempty"`
+	PublicIP      string        `json:"public_ip,omitempty" bson:"public_ip,omitempty"`
+	ContainerPort int           `json:"port,omitempty" bson:"port,omitempty"`
+	Owner         string        `json:"owner,omitempty" bson:"owner,omitempty"`
+	Quota         DatabaseQuota `json:"quota" bson:"quota"`
+	QuotaExceeded bool          `json:"quota_exceeded,omitempty" bson:"quota_exceeded"`
+	Dedicated     bool          `json:"dedicated,omitempty" bson:"dedicated,omitempty"`
+	Version       string        `json:"version,omitempty" bson:"version,omitempty"`
+	RootPassword  string        `json:"-" bson:"root_password,omitempty"`
+	Datetime      time.Time     `json:"datetime" bson:"datetime"`
+	Success       bool          `json:"success,omitempty" bson:"-"`
 }
 
 // GetName returns the database's name
@@ -57,6 +67,11 @@ func (db *DatabaseConfig) SetInstanceType(instanceType string) {
 	db.InstanceType = instanceType
 }
 
+// GetLanguage returns the database's language
+func (db *DatabaseConfig) GetLanguage() string {
+	return db.Language
+}
+
 // SetLanguage sets the database's language in its context
 func (db *DatabaseConfig) SetLanguage(language string) {
 	db.Language = language
@@ -96,6 +111,37 @@ func (db *DatabaseConfig) GetContainerPort() int {
 	return db.ContainerPort
 }
 
+// SetQuota sets the resources which the database can consume in its context
+func (db *DatabaseConfig) SetQuota(quota DatabaseQuota) {
+	db.Quota = quota
+}
+
+// GetQuota returns the resources which the database can consume
+func (db *DatabaseConfig) GetQuota() DatabaseQuota {
+	return db.Quota
+}
+
+// IsDedicated returns whether the database is deployed in its own container running a server
+// of its type instead of sharing the server of its type with other databases
+func (db *DatabaseConfig) IsDedicated() bool {
+	return db.Dedicated
+}
+
+// GetVersion returns the version of the server of a dedicated database
+func (db *DatabaseConfig) GetVersion() string {
+	return db.Version
+}
+
+// SetRootPassword sets the password of the root user of the server of a dedicated database in its context
+func (db *DatabaseConfig) SetRootPassword(password string) {
+	db.RootPassword = password
+}
+
+// GetRootPassword returns the password of the root user of the server of a dedicated database
+func (db *DatabaseConfig) GetRootPassword() string {
+	return db.RootPassword
+}
+
 // SetOwner sets the owner of the database in its context
 // The owner is referenced by his/her email ID
 func (db *DatabaseConfig) SetOwner(owner string) {
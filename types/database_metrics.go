@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// DatabaseMetrics defines a struct for storing the metrics of a database collected from
+// the catalog of the database server
+type DatabaseMetrics struct {
+	Name         string `json:"name" bson:"name"`
+	InstanceType string `json:"instance_type" bson:"instance_type"`
+	Language     string `json:"language" bson:"language"`
+
+	// Size of the database in bytes
+	Size int64 `json:"size" bson:"size"`
+
+	// Number of tables, collections or keys of the database
+	Tables int64 `json:"tables" bson:"tables"`
+
+	// Number of connections open to the database, not tracked for MongoDB
+	Connections int64 `json:"connections" bson:"connections"`
+
+	// Total number of queries run on the database since the server started
+	Queries int64 `json:"queries" bson:"queries"`
+
+	// Number of queries run per second since the previous reading
+	QueryRate float64 `json:"query_rate" bson:"query_rate"`
+
+	ReadTime  int64     `json:"timestamp" bson:"timestamp"`
+	HostIP    string    `json:"host_ip" bson:"host_ip"`
+	ExpiresAt time.Time `json:"-" bson:"expires_at"`
+}
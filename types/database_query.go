@@ -0,0 +1,71 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DatabaseQuery is an ad hoc query run on a database through its query console with the credentials
+// of the database
+// The query is an SQL statement for MySQL, MariaDB and PostgreSQL, a command in extended JSON for
+// MongoDB and a command like in `redis-cli` for Redis
+type DatabaseQuery struct {
+	Query string `form:"query" json:"query" binding:"required"`
+
+	// ReadOnly rejects the queries modifying the database
+	ReadOnly bool `form:"read_only" json:"read_only"`
+
+	// Timeout of the query in seconds, bounded by the timeout configured in DbMaker
+	Timeout int `form:"timeout" json:"timeout" binding:"min=0"`
+
+	// Limit on the number of rows, documents or elements returned by the query, bounded by
+	// the limit configured in DbMaker
+	Limit int `form:"limit" json:"limit" binding:"min=0"`
+}
+
+// GetTimeout returns the time after which the query is cancelled
+func (query *DatabaseQuery) GetTimeout() time.Duration {
+	return time.Duration(query.Timeout) * time.Second
+}
+
+// QueryResult is the result of a query run on a database through its query console
+type QueryResult struct {
+	// Columns and Rows hold the rows returned by an SQL statement
+	Columns []string        `json:"columns,omitempty"`
+	Rows    [][]interface{} `json:"rows,omitempty"`
+
+	// Documents holds the documents returned by a MongoDB command in relaxed extended JSON
+	Documents []json.RawMessage `json:"documents,omitempty"`
+
+	// Reply holds the reply to a Redis command
+	Reply interface{} `json:"reply,omitempty"`
+
+	// RowsAffected is the number of rows modified by an SQL statement
+	RowsAffected int64 `json:"rows_affected"`
+
+	// Truncated denotes whether the result was cut at the limit of the query
+	Truncated bool `json:"truncated"`
+
+	// Duration of the query in milliseconds
+	Duration float64 `json:"duration"`
+
+	// Error with which the query failed, if any
+	Error string `json:"error,omitempty"`
+}
+
+// QueryAudit is the record of a query run on a database through its query console
+type QueryAudit struct {
+	Database     string    `json:"database" bson:"database"`
+	Language     string    `json:"language" bson:"language"`
+	User         string    `json:"user" bson:"user"`
+	Query        string    `json:"query" bson:"query"`
+	ReadOnly     bool      `json:"read_only" bson:"read_only"`
+	Success      bool      `json:"success" bson:"success"`
+	Error        string    `json:"error,omitempty" bson:"error,omitempty"`
+	Rows         int       `json:"rows" bson:"rows"`
+	RowsAffected int64     `json:"rows_affected" bson:"rows_affected"`
+	Duration     float64   `json:"duration" bson:"duration"`
+	HostIP       string    `json:"host_ip" bson:"host_ip"`
+	Timestamp    time.Time `json:"timestamp" bson:"timestamp"`
+	ExpiresAt    time.Time `json:"-" bson:"expires_at"`
+}
@@ -0,0 +1,49 @@
+package types
+
+import "math"
+
+// DatabaseQuota defines the resources which a database can consume in the node it is deployed in
+// A limit of 0 means that the resource is not limited
+type DatabaseQuota struct {
+	// Maximum size of the database in MB after which writes to it are revoked
+	Size int64 `form:"size" json:"size" bson:"size" binding:"min=0"`
+
+	// Maximum number of concurrent connections to the database
+	Connections int `form:"connections" json:"connections" bson:"connections" binding:"min=0"`
+
+	// Memory limit of the container of a Redis database in GB
+	Memory float64 `form:"memory" json:"memory" bson:"memory" binding:"min=0"`
+
+	// CPU quota of the container of a Redis database in units of CPUs
+	CPU float64 `form:"cpu" json:"cpu" bson:"cpu" binding:"min=0"`
+}
+
+// GetSizeLimit returns the maximum size of the database in bytes
+func (quota DatabaseQuota) GetSizeLimit() int64 {
+	return quota.Size * 1024 * 1024
+}
+
+// GetMemoryLimit returns the memory limit of the database's container in bytes
+func (quota DatabaseQuota) GetMemoryLimit() int64 {
+	return int64(quota.Memory * math.Pow(1024, 3))
+}
+
+// GetCPULimit returns the CPU limit of the database's container in units of NanoCPUs
+func (quota DatabaseQuota) GetCPULimit() int64 {
+	return int64(quota.CPU * math.Pow(10, 9))
+}
+
+// DatabaseUsage holds the resources consumed by a database along with its quota
+type DatabaseUsage struct {
+	// Size of the database in bytes
+	Size int64 `json:"size"`
+
+	// Number of connections open to the database, not tracked for MongoDB
+	Connections int `json:"connections,omitempty"`
+
+	// Memory used by the container of a Redis database in bytes
+	Memory int64 `json:"memory,omitempty"`
+
+	Quota         DatabaseQuota `json:"quota"`
+	QuotaExceeded bool          `json:"quota_exceeded"`
+}
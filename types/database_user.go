@@ -0,0 +1,51 @@
+package types
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	// ReadOnlyRole allows a user of a database to only read its contents
+	ReadOnlyRole = "read-only"
+
+	// ReadWriteRole allows a user of a database to read and modify its contents but not to manage it
+	ReadWriteRole = "read-write"
+)
+
+// databasePasswordPattern matches the passwords which can be set for the users of databases
+// Quotes, backslashes and whitespaces are not allowed since the passwords are embedded in the
+// statements run by the database servers
+var databasePasswordPattern = regexp.MustCompile("^[!#-&(-\\[\\]-_a-~]{8,128}$")
+
+// IsValidDatabasePassword checks whether a password can be set for a user of a database
+func IsValidDatabasePassword(password string) bool {
+	return databasePasswordPattern.MatchString(password)
+}
+
+// DatabaseUser is an additional user of a database with a limited role
+type DatabaseUser struct {
+	Database  string    `json:"database" bson:"database"`
+	Username  string    `form:"username" json:"username" bson:"username" binding:"required,alphanum,lowercase,max=16"`
+	Password  string    `form:"password" json:"password,omitempty" bson:"password"`
+	Role      string    `form:"role" json:"role" bson:"role" binding:"required,oneof=read-only read-write"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// GetUser returns the name of the user in the database server
+// It is prefixed with the name of the database since the users of all databases
+// share the same server
+func (user *DatabaseUser) GetUser() string {
+	return user.Database + "_" + user.Username
+}
+
+// IsReadOnly checks whether the user can only read the contents of the database
+func (user *DatabaseUser) IsReadOnly() bool {
+	return user.Role == ReadOnlyRole
+}
+
+// PasswordRotation is the request body for changing the password of a user of a database
+// A random password is generated if none is given
+type PasswordRotation struct {
+	Password string `form:"password" json:"password"`
+}
@@ -0,0 +1,60 @@
+package types
+
+import "time"
+
+const (
+	// SyslogDrain ships logs as RFC 5424 syslog messages over TCP or TLS
+	SyslogDrain = "syslog"
+
+	// HTTPSDrain ships logs as batches of JSON encoded lines posted to an endpoint
+	HTTPSDrain = "https"
+
+	// LokiDrain ships logs to the push API of Loki
+	LokiDrain = "loki"
+)
+
+// LogDrain is an external sink to which the logs of an application are shipped
+type LogDrain struct {
+	ID       string            `json:"id" bson:"drain_id"`
+	App      string            `json:"app" bson:"app"`
+	Type     string            `form:"type" json:"type" bson:"type" binding:"required"`
+	URL      string            `form:"url" json:"url" bson:"url" binding:"required"`
+	Headers  map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+	Datetime time.Time         `json:"datetime" bson:"datetime"`
+}
+
+// GetID returns the ID of the log drain
+func (drain *LogDrain) GetID() string {
+	return drain.ID
+}
+
+// SetID sets the ID of the log drain in its context
+func (drain *LogDrain) SetID(id string) {
+	drain.ID = id
+}
+
+// GetApp returns the name of the application whose logs are drained
+func (drain *LogDrain) GetApp() string {
+	return drain.App
+}
+
+// SetApp sets the name of the application whose logs are drained
+func (drain *LogDrain) SetApp(app string) {
+	drain.App = app
+}
+
+// GetType returns the type of the log drain
+func (drain *LogDrain) GetType() string {
+	return drain.Type
+}
+
+// GetURL returns the URL of the log drain's sink
+func (drain *LogDrain) GetURL() string {
+	return drain.URL
+}
+
+// SetDateTime sets the date and time of the log drain's creation
+func (drain *LogDrain) SetDateTime() {
+	drain.Datetime = time.Now()
+}
@@ -0,0 +1,37 @@
+package types
+
+import "time"
+
+const (
+	// AppCreatedEvent denotes that an application was created
+	AppCreatedEvent = "created"
+
+	// AppRebuiltEvent denotes that an application was rebuilt
+	AppRebuiltEvent = "rebuilt"
+
+	// AppDeletedEvent denotes that an application was deleted
+	AppDeletedEvent = "deleted"
+
+	// AppFailedEvent denotes that an operation on an application failed
+	AppFailedEvent = "failed"
+)
+
+// DeployEvent is an event in the deployment lifecycle of an application
+type DeployEvent struct {
+	App       string    `json:"app"`
+	Event     string    `json:"event"`
+	Message   string    `json:"message,omitempty"`
+	HostIP    string    `json:"host_ip"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewDeployEvent returns a new deploy event of an application occurring on the current node
+func NewDeployEvent(app, event, message, hostIP string) *DeployEvent {
+	return &DeployEvent{
+		App:       app,
+		Event:     event,
+		Message:   message,
+		HostIP:    hostIP,
+		Timestamp: time.Now(),
+	}
+}
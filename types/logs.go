@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// LogQuery holds the filters for following the logs of a container
+type LogQuery struct {
+	// Tail is the number of lines to show from the end of the logs
+	Tail string `json:"tail,omitempty"`
+
+	// Since and Until are unix timestamps bounding the logs, ignored if zero
+	Since int64 `json:"since,omitempty"`
+	Until int64 `json:"until,omitempty"`
+
+	// Stream is either stdout or stderr, both the streams are selected if empty
+	Stream string `json:"stream,omitempty"`
+
+	// Grep is a regular expression matched against every line
+	Grep string `json:"grep,omitempty"`
+}
+
+// LogEntry is a line of a container's logs shipped to MongoDB
+// MongoDB stores timestamps with millisecond precision, hence the exact timestamp of the line
+// is stored along with its position among the lines sharing it for resuming the shipping
+type LogEntry struct {
+	Name          string    `json:"name" bson:"name"`
+	InstanceType  string    `json:"instance_type" bson:"instance_type"`
+	HostIP        string    `json:"host_ip" bson:"host_ip"`
+	Stream        string    `json:"stream" bson:"stream"`
+	Message       string    `json:"message" bson:"message"`
+	Timestamp     time.Time `json:"timestamp" bson:"timestamp"`
+	TimestampNano int64     `json:"-" bson:"timestamp_ns"`
+	Sequence      int       `json:"-" bson:"seq"`
+	ExpiresAt     time.Time `json:"-" bson:"expires_at"`
+}
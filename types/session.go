@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// SSHSession stores the audit information of a SSH session with an application's container
+type SSHSession struct {
+	SessionID  string     `json:"session_id" bson:"session_id"`
+	App        string     `json:"app" bson:"app"`
+	User       string     `json:"user,omitempty" bson:"user,omitempty"`
+	AuthMethod string     `json:"auth_method" bson:"auth_method"`
+	RemoteIP   string     `json:"remote_ip" bson:"remote_ip"`
+	HostIP     string     `json:"host_ip" bson:"host_ip"`
+	Type       string     `json:"type" bson:"type"`
+	Command    string     `json:"command,omitempty" bson:"command,omitempty"`
+	Recorded   bool       `json:"recorded" bson:"recorded"`
+	Start      time.Time  `json:"start" bson:"start"`
+	End        *time.Time `json:"end,omitempty" bson:"end,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at" bson:"expires_at"`
+}
+
+// SessionRecordingChunk stores a part of the asciinema transcript of a SSH session
+type SessionRecordingChunk struct {
+	SessionID string    `json:"session_id" bson:"session_id"`
+	Sequence  int       `json:"seq" bson:"seq"`
+	Data      string    `json:"data" bson:"data"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// WebTerminalTicketEnv is the environment variable through which a web terminal passes the ticket
+// identifying the user it was deployed for to GenSSH
+const WebTerminalTicketEnv = "GASPER_WEB_TERMINAL_TICKET"
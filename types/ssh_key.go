@@ -0,0 +1,69 @@
+package types
+
+import "time"
+
+// SSHKey stores a SSH public key registered by a user for authenticating with GenSSH
+type SSHKey struct {
+	Name        string    `form:"name" json:"name" bson:"name" binding:"required"`
+	PublicKey   string    `form:"public_key" json:"public_key" bson:"public_key" binding:"required"`
+	Fingerprint string    `json:"fingerprint" bson:"fingerprint"`
+	Owner       string    `json:"owner" bson:"owner"`
+	Apps        []string  `json:"apps" bson:"apps"`
+	Datetime    time.Time `json:"datetime" bson:"datetime"`
+}
+
+// GetName returns the name of the SSH key
+func (key *SSHKey) GetName() string {
+	return key.Name
+}
+
+// GetPublicKey returns the SSH public key in the authorized_keys format
+func (key *SSHKey) GetPublicKey() string {
+	return key.PublicKey
+}
+
+// SetPublicKey sets the SSH public key in its context
+func (key *SSHKey) SetPublicKey(publicKey string) {
+	key.PublicKey = publicKey
+}
+
+// GetFingerprint returns the SHA256 fingerprint of the SSH key
+func (key *SSHKey) GetFingerprint() string {
+	return key.Fingerprint
+}
+
+// SetFingerprint sets the SHA256 fingerprint of the SSH key in its context
+func (key *SSHKey) SetFingerprint(fingerprint string) {
+	key.Fingerprint = fingerprint
+}
+
+// GetOwner returns the email of the user who registered the SSH key
+func (key *SSHKey) GetOwner() string {
+	return key.Owner
+}
+
+// SetOwner sets the owner of the SSH key in its context
+func (key *SSHKey) SetOwner(owner string) {
+	key.Owner = owner
+}
+
+// HasAccess checks whether the SSH key has been granted access to an application
+func (key *SSHKey) HasAccess(app string) bool {
+	for _, name := range key.Apps {
+		if name == app {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDateTime sets the time of registration of the SSH key
+func (key *SSHKey) SetDateTime() {
+	key.Datetime = time.Now()
+}
+
+// SSHKeyGrant is the request body for granting a user's SSH key access to an application
+type SSHKeyGrant struct {
+	Email string `form:"email" json:"email" binding:"required"`
+	Name  string `form:"name" json:"name" binding:"required"`
+}
@@ -1,5 +1,12 @@
 package types
 
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
 // MemoryStats defines a struct for storing a container's memory statistics
 type MemoryStats struct {
 	Usage    float64 `json:"usage"`
@@ -9,31 +16,199 @@ type MemoryStats struct {
 
 // CPUUsageStats defines a struct for storing a container's CPU usage statistics
 type CPUUsageStats struct {
-	TotalUsage float64 `json:"total_usage"`
+	TotalUsage  float64   `json:"total_usage"`
+	PercpuUsage []float64 `json:"percpu_usage"`
 }
 
 // CPUStats defines a struct for storing a container's CPU statistics
 type CPUStats struct {
-	OnlineCPUs float64       `json:"online_cpus"`
-	CPUUsage   CPUUsageStats `json:"cpu_usage"`
+	OnlineCPUs     float64       `json:"online_cpus"`
+	CPUUsage       CPUUsageStats `json:"cpu_usage"`
+	SystemCPUUsage float64       `json:"system_cpu_usage"`
+}
+
+// PidsStats defines a struct for storing the statistics of the processes running in a container
+type PidsStats struct {
+	Current float64 `json:"current"`
+}
+
+// NetworkStats defines a struct for storing the statistics of a container's network interface
+type NetworkStats struct {
+	RxBytes float64 `json:"rx_bytes"`
+	TxBytes float64 `json:"tx_bytes"`
+}
+
+// BlkioStatEntry defines a struct for storing a single block IO statistic of a container
+type BlkioStatEntry struct {
+	Major uint64  `json:"major"`
+	Minor uint64  `json:"minor"`
+	Op    string  `json:"op"`
+	Value float64 `json:"value"`
+}
+
+// BlkioStats defines a struct for storing a container's block IO statistics
+type BlkioStats struct {
+	IOServiceBytesRecursive []BlkioStatEntry `json:"io_service_bytes_recursive"`
 }
 
 // Stats defines a struct for storing container statistics
 type Stats struct {
-	Memory MemoryStats `json:"memory_stats"`
-	CPU    CPUStats    `json:"cpu_stats"`
+	Memory   MemoryStats             `json:"memory_stats"`
+	CPU      CPUStats                `json:"cpu_stats"`
+	PreCPU   CPUStats                `json:"precpu_stats"`
+	Pids     PidsStats               `json:"pids_stats"`
+	Networks map[string]NetworkStats `json:"networks"`
+	Blkio    BlkioStats              `json:"blkio_stats"`
+}
+
+// CPUPercent returns the CPU utilization of a container in percentage of a single CPU
+// It is computed from the difference between the current and the previous CPU readings
+// taken by docker hence a container using 2 CPUs completely has a utilization of 200%
+func (stats *Stats) CPUPercent() float64 {
+	cpuDelta := stats.CPU.CPUUsage.TotalUsage - stats.PreCPU.CPUUsage.TotalUsage
+	systemDelta := stats.CPU.SystemCPUUsage - stats.PreCPU.SystemCPUUsage
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	onlineCPUs := stats.CPU.OnlineCPUs
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPU.CPUUsage.PercpuUsage))
+	}
+	return (cpuDelta / systemDelta) * onlineCPUs * 100
+}
+
+// NetworkIO returns the total number of bytes received and transmitted by a container
+func (stats *Stats) NetworkIO() (rx, tx float64) {
+	for _, network := range stats.Networks {
+		rx += network.RxBytes
+		tx += network.TxBytes
+	}
+	return rx, tx
+}
+
+// BlockIO returns the total number of bytes read from and written to block devices by a container
+func (stats *Stats) BlockIO() (read, write float64) {
+	for _, entry := range stats.Blkio.IOServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			read += entry.Value
+		case "write":
+			write += entry.Value
+		}
+	}
+	return read, write
 }
 
 // Metrics defines a struct for storing container metrics
 type Metrics struct {
-	Name           string  `json:"name" bson:"name"`
-	CPUUsage       float64 `json:"cpu_usage" bson:"cpu_usage"`
-	OnlineCPUs     float64 `json:"online_cpus" bson:"online_cpus"`
-	MemoryUsage    float64 `json:"memory_usage" bson:"memory_usage"`
-	MaxMemoryUsage float64 `json:"max_memory_usage" bson:"max_memory_usage"`
-	MemoryLimit    float64 `json:"memory_limit" bson:"memory_limit"`
-	ReadTime       int64   `json:"timestamp" bson:"timestamp"`
-	Alive          bool    `json:"alive" bson:"alive"`
-	HostIP         string  `json:"host_ip" bson:"host_ip"`
-	Logs           string  `json:"logs" bson:"logs"`
+	Name           string    `json:"name" bson:"name"`
+	CPUUsage       float64   `json:"cpu_usage" bson:"cpu_usage"`
+	OnlineCPUs     float64   `json:"online_cpus" bson:"online_cpus"`
+	MemoryUsage    float64   `json:"memory_usage" bson:"memory_usage"`
+	MaxMemoryUsage float64   `json:"max_memory_usage" bson:"max_memory_usage"`
+	MemoryLimit    float64   `json:"memory_limit" bson:"memory_limit"`
+	CPUPercent     float64   `json:"cpu_percent" bson:"cpu_percent"`
+	NetworkRx      float64   `json:"network_rx" bson:"network_rx"`
+	NetworkTx      float64   `json:"network_tx" bson:"network_tx"`
+	BlockRead      float64   `json:"block_read" bson:"block_read"`
+	BlockWrite     float64   `json:"block_write" bson:"block_write"`
+	PIDs           int64     `json:"pids" bson:"pids"`
+	RestartCount   int64     `json:"restart_count" bson:"restart_count"`
+	OOMKilled      bool      `json:"oom_killed" bson:"oom_killed"`
+	ReadTime       int64     `json:"timestamp" bson:"timestamp"`
+	Alive          bool      `json:"alive" bson:"alive"`
+	HostIP         string    `json:"host_ip" bson:"host_ip"`
+	Logs           string    `json:"logs" bson:"logs"`
+	ExpiresAt      time.Time `json:"-" bson:"expires_at"`
+}
+
+// Aggregate defines a struct for storing the aggregates of a metric over a period of time
+type Aggregate struct {
+	Avg float64 `json:"avg" bson:"avg"`
+	Max float64 `json:"max" bson:"max"`
+	P95 float64 `json:"p95" bson:"p95"`
+}
+
+// NewAggregate returns the average, maximum and 95th percentile of the given values
+// The percentile is computed with the nearest-rank method
+func NewAggregate(values []float64) Aggregate {
+	if len(values) == 0 {
+		return Aggregate{}
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return Aggregate{
+		Avg: sum / float64(len(sorted)),
+		Max: sorted[len(sorted)-1],
+		P95: sorted[rank],
+	}
+}
+
+// MetricsRollup defines a struct for storing the metrics of a container aggregated over a period of time
+type MetricsRollup struct {
+	Name         string    `json:"name" bson:"name"`
+	Timestamp    int64     `json:"timestamp" bson:"timestamp"`
+	Samples      int64     `json:"samples" bson:"samples"`
+	Uptime       float64   `json:"uptime" bson:"uptime"`
+	CPUUsage     Aggregate `json:"cpu_usage" bson:"cpu_usage"`
+	CPUPercent   Aggregate `json:"cpu_percent" bson:"cpu_percent"`
+	MemoryUsage  Aggregate `json:"memory_usage" bson:"memory_usage"`
+	NetworkRx    Aggregate `json:"network_rx" bson:"network_rx"`
+	NetworkTx    Aggregate `json:"network_tx" bson:"network_tx"`
+	BlockRead    Aggregate `json:"block_read" bson:"block_read"`
+	BlockWrite   Aggregate `json:"block_write" bson:"block_write"`
+	PIDs         Aggregate `json:"pids" bson:"pids"`
+	RestartCount int64     `json:"restart_count" bson:"restart_count"`
+	OOMKilled    bool      `json:"oom_killed" bson:"oom_killed"`
+	HostIP       string    `json:"host_ip" bson:"host_ip"`
+	ExpiresAt    time.Time `json:"-" bson:"expires_at"`
+}
+
+// NewMetricsRollup aggregates the metrics of a container read in the period starting at the given timestamp
+func NewMetricsRollup(name string, timestamp int64, samples []*Metrics) *MetricsRollup {
+	rollup := &MetricsRollup{
+		Name:      name,
+		Timestamp: timestamp,
+		Samples:   int64(len(samples)),
+	}
+	if len(samples) == 0 {
+		return rollup
+	}
+
+	var cpuUsage, cpuPercent, memoryUsage, networkRx, networkTx, blockRead, blockWrite, pids []float64
+	alive := 0
+	for _, sample := range samples {
+		if sample.Alive {
+			alive++
+		}
+		cpuUsage = append(cpuUsage, sample.CPUUsage)
+		cpuPercent = append(cpuPercent, sample.CPUPercent)
+		memoryUsage = append(memoryUsage, sample.MemoryUsage)
+		networkRx = append(networkRx, sample.NetworkRx)
+		networkTx = append(networkTx, sample.NetworkTx)
+		blockRead = append(blockRead, sample.BlockRead)
+		blockWrite = append(blockWrite, sample.BlockWrite)
+		pids = append(pids, float64(sample.PIDs))
+		if sample.RestartCount > rollup.RestartCount {
+			rollup.RestartCount = sample.RestartCount
+		}
+		rollup.OOMKilled = rollup.OOMKilled || sample.OOMKilled
+		rollup.HostIP = sample.HostIP
+	}
+
+	rollup.Uptime = float64(alive) / float64(len(samples))
+	rollup.CPUUsage = NewAggregate(cpuUsage)
+	rollup.CPUPercent = NewAggregate(cpuPercent)
+	rollup.MemoryUsage = NewAggregate(memoryUsage)
+	rollup.NetworkRx = NewAggregate(networkRx)
+	rollup.NetworkTx = NewAggregate(networkTx)
+	rollup.BlockRead = NewAggregate(blockRead)
+	rollup.BlockWrite = NewAggregate(blockWrite)
+	rollup.PIDs = NewAggregate(pids)
+	return rollup
 }
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// ProbeSlot holds the results of the HTTP probes sent to an application in a 5-minute period
+type ProbeSlot struct {
+	App       string    `json:"app" bson:"app"`
+	Timestamp int64     `json:"timestamp" bson:"timestamp"`
+	Probes    int64     `json:"probes" bson:"probes"`
+	Failures  int64     `json:"failures" bson:"failures"`
+	LastError string    `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	ExpiresAt time.Time `json:"-" bson:"expires_at"`
+}